@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type CalendarService interface {
+	GetFeedURL(ctx context.Context, userID int) (string, error)
+	RotateFeedURL(ctx context.Context, userID int) (string, error)
+	RenderFeed(ctx context.Context, token string) ([]byte, error)
+}
+
+type CalendarController struct {
+	service CalendarService
+}
+
+func NewCalendarController(service CalendarService) *CalendarController {
+	return &CalendarController{service: service}
+}
+
+// GetFeed returns the caller's private .ics subscription URL.
+func (c *CalendarController) GetFeed(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+
+	url, err := c.service.GetFeedURL(r.Context(), userID)
+	if err != nil {
+		calendarError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   map[string]string{"url": url},
+	})
+}
+
+// RotateFeed replaces the caller's feed token, e.g. after the URL leaked.
+func (c *CalendarController) RotateFeed(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+
+	url, err := c.service.RotateFeedURL(r.Context(), userID)
+	if err != nil {
+		calendarError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   map[string]string{"url": url},
+	})
+}
+
+// ServeFeed is public; the token in the path is the credential.
+func (c *CalendarController) ServeFeed(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSuffix(mux.Vars(r)["token"], ".ics")
+
+	feed, err := c.service.RenderFeed(r.Context(), token)
+	if err != nil {
+		calendarError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="courses.ics"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(feed)
+}
+
+func calendarError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if err == models.ErrCalendarTokenNotFound {
+		status = http.StatusNotFound
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"encoding/json"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CategoryService interface {
+	CreateCategory(ctx context.Context, category *models.Category) error
+	GetCategories(ctx context.Context) ([]models.Category, error)
+	GetCategory(ctx context.Context, id primitive.ObjectID) (*models.Category, error)
+	UpdateCategory(ctx context.Context, category *models.Category) error
+	DeleteCategory(ctx context.Context, id primitive.ObjectID) error
+}
+
+type CategoryController struct {
+	service CategoryService
+}
+
+func NewCategoryController(service CategoryService) *CategoryController {
+	return &CategoryController{service: service}
+}
+
+func (c *CategoryController) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var category models.Category
+	if err := json.NewDecoder(r.Body).Decode(&category); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	if err := c.service.CreateCategory(r.Context(), &category); err != nil {
+		categoryError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusCreated,
+		Data:   category,
+	})
+}
+
+func (c *CategoryController) GetCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := c.service.GetCategories(r.Context())
+	if err != nil {
+		categoryError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   categories,
+	})
+}
+
+func (c *CategoryController) GetCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, ok := objectIDParam(w, r, "categoryId")
+	if !ok {
+		return
+	}
+
+	category, err := c.service.GetCategory(r.Context(), categoryID)
+	if err != nil {
+		categoryError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   category,
+	})
+}
+
+// UpdateCategory replaces the category. A new slug is applied to its courses.
+func (c *CategoryController) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, ok := objectIDParam(w, r, "categoryId")
+	if !ok {
+		return
+	}
+
+	var category models.Category
+	if err := json.NewDecoder(r.Body).Decode(&category); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+	category.ID = categoryID
+
+	if err := c.service.UpdateCategory(r.Context(), &category); err != nil {
+		categoryError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   category,
+	})
+}
+
+func (c *CategoryController) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, ok := objectIDParam(w, r, "categoryId")
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteCategory(r.Context(), categoryID); err != nil {
+		categoryError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Category deleted successfully",
+	})
+}
+
+func categoryError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrCategoryNotFound:
+		status = http.StatusNotFound
+	case models.ErrInvalidCategoryData, models.ErrCategoryCycle:
+		status = http.StatusBadRequest
+	case models.ErrDuplicateCategory, models.ErrCategoryInUse:
+		status = http.StatusConflict
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CertificateService interface {
+	IssueCertificate(ctx context.Context, courseID primitive.ObjectID, userID int) (*models.Certificate, error)
+	GetUserCertificates(ctx context.Context, userID int) ([]models.Certificate, error)
+	VerifyCertificate(ctx context.Context, code string) (*models.CertificateVerification, error)
+	RenderPDF(ctx context.Context, code string, userID int, admin bool) ([]byte, error)
+	OpenBadge(ctx context.Context, code string) (map[string]interface{}, error)
+}
+
+type CertificateController struct {
+	service CertificateService
+}
+
+func NewCertificateController(service CertificateService) *CertificateController {
+	return &CertificateController{service: service}
+}
+
+func (c *CertificateController) IssueCertificate(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	certificate, err := c.service.IssueCertificate(r.Context(), courseID, userID)
+	if err != nil {
+		certificateError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   certificate,
+	})
+}
+
+func (c *CertificateController) GetUserCertificates(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+
+	certificates, err := c.service.GetUserCertificates(r.Context(), userID)
+	if err != nil {
+		certificateError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   certificates,
+	})
+}
+
+func (c *CertificateController) VerifyCertificate(w http.ResponseWriter, r *http.Request) {
+	verification, err := c.service.VerifyCertificate(r.Context(), mux.Vars(r)["code"])
+	if err != nil {
+		if err == models.ErrCertificateNotFound {
+			views.JSON(w, views.Response{
+				Status: http.StatusNotFound,
+				Data:   map[string]bool{"valid": false},
+				Error:  err.Error(),
+			})
+			return
+		}
+		certificateError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   verification,
+	})
+}
+
+func (c *CertificateController) DownloadPDF(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	code := mux.Vars(r)["code"]
+
+	pdf, err := c.service.RenderPDF(r.Context(), code, userID, isAdmin(r))
+	if err != nil {
+		certificateError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="certificate-`+code+`.pdf"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(pdf)
+}
+
+// OpenBadge serves the certificate as an Open Badges assertion, which is
+// consumed as-is by badge backpacks rather than wrapped in views.Response.
+func (c *CertificateController) OpenBadge(w http.ResponseWriter, r *http.Request) {
+	assertion, err := c.service.OpenBadge(r.Context(), mux.Vars(r)["code"])
+	if err != nil {
+		certificateError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/ld+json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(assertion)
+}
+
+func certificateError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrCertificateNotFound, models.ErrEnrollmentNotFound, models.ErrCourseNotFound:
+		status = http.StatusNotFound
+	case models.ErrCourseNotCompleted:
+		status = http.StatusConflict
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
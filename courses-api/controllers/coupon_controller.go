@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"encoding/json"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CouponService interface {
+	CreateCoupon(ctx context.Context, coupon *models.Coupon) error
+	GetCoupons(ctx context.Context) ([]models.Coupon, error)
+	GetCoupon(ctx context.Context, id primitive.ObjectID) (*models.Coupon, error)
+	UpdateCoupon(ctx context.Context, coupon *models.Coupon) error
+	DeleteCoupon(ctx context.Context, id primitive.ObjectID) error
+}
+
+type CouponController struct {
+	service CouponService
+}
+
+func NewCouponController(service CouponService) *CouponController {
+	return &CouponController{service: service}
+}
+
+func (c *CouponController) CreateCoupon(w http.ResponseWriter, r *http.Request) {
+	var coupon models.Coupon
+	if err := json.NewDecoder(r.Body).Decode(&coupon); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	if err := c.service.CreateCoupon(r.Context(), &coupon); err != nil {
+		couponError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusCreated,
+		Data:   coupon,
+	})
+}
+
+func (c *CouponController) GetCoupons(w http.ResponseWriter, r *http.Request) {
+	coupons, err := c.service.GetCoupons(r.Context())
+	if err != nil {
+		couponError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   coupons,
+	})
+}
+
+func (c *CouponController) GetCoupon(w http.ResponseWriter, r *http.Request) {
+	couponID, ok := objectIDParam(w, r, "couponId")
+	if !ok {
+		return
+	}
+
+	coupon, err := c.service.GetCoupon(r.Context(), couponID)
+	if err != nil {
+		couponError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   coupon,
+	})
+}
+
+// UpdateCoupon replaces the coupon's settings; its redemption count is kept.
+func (c *CouponController) UpdateCoupon(w http.ResponseWriter, r *http.Request) {
+	couponID, ok := objectIDParam(w, r, "couponId")
+	if !ok {
+		return
+	}
+
+	var coupon models.Coupon
+	if err := json.NewDecoder(r.Body).Decode(&coupon); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+	coupon.ID = couponID
+
+	if err := c.service.UpdateCoupon(r.Context(), &coupon); err != nil {
+		couponError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   coupon,
+	})
+}
+
+func (c *CouponController) DeleteCoupon(w http.ResponseWriter, r *http.Request) {
+	couponID, ok := objectIDParam(w, r, "couponId")
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteCoupon(r.Context(), couponID); err != nil {
+		couponError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Coupon deleted successfully",
+	})
+}
+
+func couponError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrCouponNotFound:
+		status = http.StatusNotFound
+	case models.ErrInvalidCoupon:
+		status = http.StatusBadRequest
+	case models.ErrDuplicateCoupon:
+		status = http.StatusConflict
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
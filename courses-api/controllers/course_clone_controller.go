@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"encoding/json"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CourseCloneService interface {
+	CloneCourse(ctx context.Context, id primitive.ObjectID, req models.CloneRequest, userID int, admin bool) (*models.Course, error)
+}
+
+type CourseCloneController struct {
+	service CourseCloneService
+}
+
+func NewCourseCloneController(service CourseCloneService) *CourseCloneController {
+	return &CourseCloneController{service: service}
+}
+
+// CloneCourse copies a course for a new cohort; the body needs a title or
+// a cohort label and may shift the dates.
+func (c *CourseCloneController) CloneCourse(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	userID := r.Context().Value("userID").(int)
+
+	var req models.CloneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	clone, err := c.service.CloneCourse(r.Context(), courseID, req, userID, isAdmin(r))
+	if err != nil {
+		cloneError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusCreated,
+		Data:   clone,
+	})
+}
+
+func cloneError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrCourseNotFound:
+		status = http.StatusNotFound
+	case models.ErrForbidden:
+		status = http.StatusForbidden
+	case models.ErrInvalidClone, models.ErrInvalidDates, models.ErrInvalidEnrollmentWindow:
+		status = http.StatusBadRequest
+	case models.ErrDuplicateCourse, models.ErrMaterialQuotaExceeded:
+		status = http.StatusConflict
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
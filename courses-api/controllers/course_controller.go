@@ -5,28 +5,46 @@ import (
 	"courses-api/models"
 	"courses-api/views"
 	"encoding/json"
+	"io"
+	"log"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type CourseService interface {
-	CreateCourse(ctx context.Context, course *models.Course) error
-	GetAllCourses(ctx context.Context) ([]models.Course, error)
+	CreateCourse(ctx context.Context, course *models.Course, userID int, admin bool) error
+	GetAllCourses(ctx context.Context, includeUnpublished bool, tags []string) ([]models.Course, error)
+	SuggestTags(ctx context.Context, prefix string, limit int) ([]models.TagCount, error)
 	GetCourse(ctx context.Context, id primitive.ObjectID) (*models.Course, error)
-	UpdateCourse(ctx context.Context, course *models.Course) error
-	DeleteCourse(ctx context.Context, id primitive.ObjectID) error
-	CheckAvailability(ctx context.Context, courseIDs []primitive.ObjectID) (map[string]int, error)
+	GetVisibleCourse(ctx context.Context, id primitive.ObjectID, admin bool) (*models.Course, error)
+	UpdateCourse(ctx context.Context, course *models.Course, userID int, admin bool) error
+	DeleteCourse(ctx context.Context, id primitive.ObjectID, userID int, admin bool) error
+	CheckAvailability(ctx context.Context, courseIDs []primitive.ObjectID) (map[string]models.SeatAvailability, error)
 	GetUserCourses(ctx context.Context, userID int) ([]models.Course, error)
+	ChangeStatus(ctx context.Context, id primitive.ObjectID, status string) (*models.Course, error)
+	ScheduleCourse(ctx context.Context, id primitive.ObjectID, publishAt, unpublishAt *time.Time) (*models.Course, error)
+	ExportCourses(ctx context.Context, format string, w io.Writer) error
+	ImportCourses(ctx context.Context, format string, r io.Reader, dryRun bool) (*models.ImportResult, error)
+}
+
+// ProgressReporter provides per-course progress for the user's course list.
+type ProgressReporter interface {
+	GetUserProgress(ctx context.Context, userID int) (map[string]models.CourseProgress, error)
 }
 
 type CourseController struct {
-	service CourseService
+	service  CourseService
+	progress ProgressReporter
 }
 
-func NewCourseController(service CourseService) *CourseController {
-	return &CourseController{service: service}
+func NewCourseController(service CourseService, progress ProgressReporter) *CourseController {
+	return &CourseController{service: service, progress: progress}
 }
 
 func (c *CourseController) CreateCourse(w http.ResponseWriter, r *http.Request) {
@@ -39,11 +57,9 @@ func (c *CourseController) CreateCourse(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := c.service.CreateCourse(r.Context(), &course); err != nil {
-		views.JSON(w, views.Response{
-			Status: http.StatusInternalServerError,
-			Error:  err.Error(),
-		})
+	userID := r.Context().Value("userID").(int)
+	if err := c.service.CreateCourse(r.Context(), &course, userID, isAdmin(r)); err != nil {
+		courseWriteError(w, err)
 		return
 	}
 
@@ -53,8 +69,10 @@ func (c *CourseController) CreateCourse(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// GetAllCourses lists courses in the language negotiated from ?lang= or
+// Accept-Language.
 func (c *CourseController) GetAllCourses(w http.ResponseWriter, r *http.Request) {
-	courses, err := c.service.GetAllCourses(r.Context())
+	courses, err := c.service.GetAllCourses(r.Context(), isAdmin(r), r.URL.Query()["tag"])
 	if err != nil {
 		views.JSON(w, views.Response{
 			Status: http.StatusInternalServerError,
@@ -63,12 +81,36 @@ func (c *CourseController) GetAllCourses(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	locale := requestLocale(w, r)
+	for i := range courses {
+		courses[i].Localize(locale)
+	}
+
 	views.JSON(w, views.Response{
 		Status: http.StatusOK,
 		Data:   courses,
 	})
 }
 
+// SuggestTags autocompletes tags, e.g. GET /courses/tags?prefix=mach&limit=5.
+func (c *CourseController) SuggestTags(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	tags, err := c.service.SuggestTags(r.Context(), r.URL.Query().Get("prefix"), limit)
+	if err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusInternalServerError,
+			Error:  "Error fetching tags",
+		})
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   tags,
+	})
+}
+
 func (c *CourseController) GetCourse(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	courseID, err := primitive.ObjectIDFromHex(params["id"])
@@ -80,7 +122,7 @@ func (c *CourseController) GetCourse(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	course, err := c.service.GetCourse(r.Context(), courseID)
+	course, err := c.service.GetVisibleCourse(r.Context(), courseID, isAdmin(r))
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "course not found" {
@@ -93,6 +135,9 @@ func (c *CourseController) GetCourse(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	course.Localize(requestLocale(w, r))
+	w.Header().Set("Content-Language", course.Locale)
+
 	views.JSON(w, views.Response{
 		Status: http.StatusOK,
 		Data:   course,
@@ -149,8 +194,16 @@ func (c *CourseController) UpdateCourse(w http.ResponseWriter, r *http.Request)
 	if course.Description != "" {
 		existingCourse.Description = course.Description
 	}
-	if course.ImageURL != "" {
+	if course.DefaultLocale != "" {
+		existingCourse.DefaultLocale = course.DefaultLocale
+	}
+	if course.Translations != nil {
+		existingCourse.Translations = course.Translations
+	}
+	if course.ImageURL != "" && course.ImageURL != existingCourse.ImageURL {
+		// A pasted URL has no generated thumbnail
 		existingCourse.ImageURL = course.ImageURL
+		existingCourse.ThumbnailURL = ""
 	}
 	if course.Instructor != "" {
 		existingCourse.Instructor = course.Instructor
@@ -164,13 +217,44 @@ func (c *CourseController) UpdateCourse(w http.ResponseWriter, r *http.Request)
 	if course.AvailableSeats > 0 {
 		existingCourse.AvailableSeats = course.AvailableSeats
 	}
+	if course.Price > 0 {
+		existingCourse.Price = course.Price
+	}
+	if course.Currency != "" {
+		existingCourse.Currency = course.Currency
+	}
+	if course.StartDate != nil {
+		existingCourse.StartDate = course.StartDate
+	}
+	if course.EndDate != nil {
+		existingCourse.EndDate = course.EndDate
+	}
+	if course.TimeZone != "" {
+		existingCourse.TimeZone = course.TimeZone
+	}
+	if course.Sessions != nil {
+		existingCourse.Sessions = course.Sessions
+	}
+	if course.EnrollmentOpensAt != nil {
+		existingCourse.EnrollmentOpensAt = course.EnrollmentOpensAt
+	}
+	if course.EnrollmentClosesAt != nil {
+		existingCourse.EnrollmentClosesAt = course.EnrollmentClosesAt
+	}
+	if course.Prerequisites != nil {
+		existingCourse.Prerequisites = course.Prerequisites
+	}
+	if course.InstructorIDs != nil {
+		existingCourse.InstructorIDs = course.InstructorIDs
+	}
+	if course.Tags != nil {
+		existingCourse.Tags = course.Tags
+	}
 
 	existingCourse.ID = objectID
-	if err := c.service.UpdateCourse(r.Context(), existingCourse); err != nil {
-		views.JSON(w, views.Response{
-			Status: http.StatusInternalServerError,
-			Error:  err.Error(),
-		})
+	userID := r.Context().Value("userID").(int)
+	if err := c.service.UpdateCourse(r.Context(), existingCourse, userID, isAdmin(r)); err != nil {
+		courseWriteError(w, err)
 		return
 	}
 
@@ -191,15 +275,9 @@ func (c *CourseController) DeleteCourse(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := c.service.DeleteCourse(r.Context(), courseID); err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "course not found" {
-			status = http.StatusNotFound
-		}
-		views.JSON(w, views.Response{
-			Status: status,
-			Error:  err.Error(),
-		})
+	userID := r.Context().Value("userID").(int)
+	if err := c.service.DeleteCourse(r.Context(), courseID, userID, isAdmin(r)); err != nil {
+		courseWriteError(w, err)
 		return
 	}
 
@@ -259,10 +337,214 @@ func (c *CourseController) GetUserCourses(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	progress, err := c.progress.GetUserProgress(r.Context(), userID)
+	if err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusInternalServerError,
+			Error:  err.Error(),
+		})
+		return
+	}
+
 	views.JSON(w, views.Response{
 		Status: http.StatusOK,
 		Data: map[string]interface{}{
-			"courses": courses,
+			"courses":  courses,
+			"progress": progress,
 		},
 	})
-}
\ No newline at end of file
+}
+
+func (c *CourseController) ChangeCourseStatus(w http.ResponseWriter, r *http.Request) {
+	courseID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid course ID",
+		})
+		return
+	}
+
+	var request struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	course, err := c.service.ChangeStatus(r.Context(), courseID, request.Status)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err {
+		case models.ErrCourseNotFound:
+			status = http.StatusNotFound
+		case models.ErrInvalidCourseStatus:
+			status = http.StatusBadRequest
+		case models.ErrInvalidTransition:
+			status = http.StatusConflict
+		}
+		views.JSON(w, views.Response{
+			Status: status,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   course,
+	})
+}
+
+func (c *CourseController) ScheduleCourse(w http.ResponseWriter, r *http.Request) {
+	courseID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid course ID",
+		})
+		return
+	}
+
+	// A null or missing time clears that side of the schedule
+	var request struct {
+		PublishAt   *time.Time `json:"publish_at"`
+		UnpublishAt *time.Time `json:"unpublish_at"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	course, err := c.service.ScheduleCourse(r.Context(), courseID, request.PublishAt, request.UnpublishAt)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err {
+		case models.ErrCourseNotFound:
+			status = http.StatusNotFound
+		case models.ErrInvalidSchedule:
+			status = http.StatusBadRequest
+		}
+		views.JSON(w, views.Response{
+			Status: status,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   course,
+	})
+}
+
+// requestLocale negotiates the response language. The answer depends on
+// Accept-Language, so caches are told to key on it.
+func requestLocale(w http.ResponseWriter, r *http.Request) string {
+	w.Header().Add("Vary", "Accept-Language")
+	return models.NegotiateLocale(r.URL.Query().Get("lang"), r.Header.Get("Accept-Language"))
+}
+
+// isAdmin reports whether the request was authenticated as an admin.
+func isAdmin(r *http.Request) bool {
+	admin, _ := r.Context().Value("admin").(bool)
+	return admin
+}
+
+// courseFormatTypes maps the bulk formats to their media types.
+var courseFormatTypes = map[string]string{
+	models.CourseFormatCSV:    "text/csv",
+	models.CourseFormatJSON:   "application/json",
+	models.CourseFormatNDJSON: "application/x-ndjson",
+}
+
+// courseFormat reads the bulk format from ?format=, falling back to the
+// request's Content-Type and then to fallback.
+func courseFormat(r *http.Request, fallback string) string {
+	if format := strings.ToLower(r.URL.Query().Get("format")); format != "" {
+		return format
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	for format, formatType := range courseFormatTypes {
+		if mediaType == formatType {
+			return format
+		}
+	}
+	return fallback
+}
+
+// ExportCourses streams the whole catalog as CSV, JSON or NDJSON.
+func (c *CourseController) ExportCourses(w http.ResponseWriter, r *http.Request) {
+	format := courseFormat(r, models.CourseFormatJSON)
+	if !models.IsCourseFormat(format) {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  models.ErrUnsupportedFormat.Error(),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", courseFormatTypes[format])
+	w.Header().Set("Content-Disposition", `attachment; filename="courses.`+format+`"`)
+	w.WriteHeader(http.StatusOK)
+	// The status is already sent, so a failure can only cut the export short
+	if err := c.service.ExportCourses(r.Context(), format, w); err != nil {
+		log.Printf("Error exporting courses: %v", err)
+	}
+}
+
+// ImportCourses upserts courses from the request body. With ?dry_run=true
+// rows are only validated.
+func (c *CourseController) ImportCourses(w http.ResponseWriter, r *http.Request) {
+	format := courseFormat(r, "")
+	if !models.IsCourseFormat(format) {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  models.ErrUnsupportedFormat.Error(),
+		})
+		return
+	}
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+	r.Body = http.MaxBytesReader(w, r.Body, models.MaxImportBytes)
+	result, err := c.service.ImportCourses(r.Context(), format, r.Body, dryRun)
+	if err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	// Rows are saved independently, so failed rows are reported in the result
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   result,
+	})
+}
+
+// courseWriteError reports failures creating, updating or deleting a course.
+func courseWriteError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case err.Error() == "course not found":
+		status = http.StatusNotFound
+	case err == models.ErrForbidden:
+		status = http.StatusForbidden
+	case err == models.ErrUnknownInstructor, err == models.ErrInvalidTags, err == models.ErrInvalidLocale:
+		status = http.StatusBadRequest
+	case err == models.ErrSeatsInUse:
+		status = http.StatusConflict
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
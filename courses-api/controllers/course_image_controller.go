@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"errors"
+	"io"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// multipartOverhead leaves room for the form boundaries and headers around
+// the image itself.
+const multipartOverhead = 64 << 10
+
+type CourseImageService interface {
+	UploadImage(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool, data []byte) (*models.Course, error)
+	DeleteImage(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool) (*models.Course, error)
+}
+
+type CourseImageController struct {
+	service CourseImageService
+}
+
+func NewCourseImageController(service CourseImageService) *CourseImageController {
+	return &CourseImageController{service: service}
+}
+
+// UploadImage accepts a multipart form with the file in the "image" field.
+func (c *CourseImageController) UploadImage(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	userID := r.Context().Value("userID").(int)
+
+	r.Body = http.MaxBytesReader(w, r.Body, models.MaxCourseImageBytes+multipartOverhead)
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			courseImageError(w, models.ErrImageTooLarge)
+			return
+		}
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "a multipart form with an image file is required",
+		})
+		return
+	}
+	defer file.Close()
+
+	// The declared type must agree with the sniffed one checked by the service
+	if !models.IsAllowedImageType(header.Header.Get("Content-Type")) {
+		courseImageError(w, models.ErrUnsupportedImage)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, models.MaxCourseImageBytes+1))
+	if err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "error reading image",
+		})
+		return
+	}
+
+	course, err := c.service.UploadImage(r.Context(), courseID, userID, isAdmin(r), data)
+	if err != nil {
+		courseImageError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   course,
+	})
+}
+
+func (c *CourseImageController) DeleteImage(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	userID := r.Context().Value("userID").(int)
+
+	course, err := c.service.DeleteImage(r.Context(), courseID, userID, isAdmin(r))
+	if err != nil {
+		courseImageError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   course,
+	})
+}
+
+func courseImageError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrCourseNotFound:
+		status = http.StatusNotFound
+	case models.ErrForbidden:
+		status = http.StatusForbidden
+	case models.ErrImageTooLarge:
+		status = http.StatusRequestEntityTooLarge
+	case models.ErrUnsupportedImage:
+		status = http.StatusUnsupportedMediaType
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
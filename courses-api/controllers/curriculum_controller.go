@@ -0,0 +1,379 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CurriculumService interface {
+	GetCurriculum(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool) ([]models.Section, error)
+	GetLesson(ctx context.Context, courseID, lessonID primitive.ObjectID, userID int, admin bool) (*models.Lesson, error)
+	CreateSection(ctx context.Context, section *models.Section) error
+	UpdateSection(ctx context.Context, section *models.Section) error
+	DeleteSection(ctx context.Context, courseID, sectionID primitive.ObjectID) error
+	ReorderSections(ctx context.Context, courseID primitive.ObjectID, ids []primitive.ObjectID) error
+	CreateLesson(ctx context.Context, lesson *models.Lesson) error
+	UpdateLesson(ctx context.Context, lesson *models.Lesson) error
+	DeleteLesson(ctx context.Context, courseID, lessonID primitive.ObjectID) error
+	ReorderLessons(ctx context.Context, courseID, sectionID primitive.ObjectID, ids []primitive.ObjectID) error
+}
+
+type CurriculumController struct {
+	service CurriculumService
+}
+
+func NewCurriculumController(service CurriculumService) *CurriculumController {
+	return &CurriculumController{service: service}
+}
+
+func (c *CurriculumController) GetCurriculum(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	userID, _ := r.Context().Value("userID").(int)
+	sections, err := c.service.GetCurriculum(r.Context(), courseID, userID, isAdmin(r))
+	if err != nil {
+		curriculumError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   sections,
+	})
+}
+
+func (c *CurriculumController) GetLesson(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	lessonID, ok := objectIDParam(w, r, "lessonId")
+	if !ok {
+		return
+	}
+
+	userID, _ := r.Context().Value("userID").(int)
+	lesson, err := c.service.GetLesson(r.Context(), courseID, lessonID, userID, isAdmin(r))
+	if err != nil {
+		curriculumError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   lesson,
+	})
+}
+
+func (c *CurriculumController) CreateSection(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var section models.Section
+	if err := json.NewDecoder(r.Body).Decode(&section); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+	section.ID = primitive.NilObjectID
+	section.CourseID = courseID
+
+	if err := c.service.CreateSection(r.Context(), &section); err != nil {
+		curriculumError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusCreated,
+		Data:   section,
+	})
+}
+
+func (c *CurriculumController) UpdateSection(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	sectionID, ok := objectIDParam(w, r, "sectionId")
+	if !ok {
+		return
+	}
+
+	var section models.Section
+	if err := json.NewDecoder(r.Body).Decode(&section); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+	section.ID = sectionID
+	section.CourseID = courseID
+
+	if err := c.service.UpdateSection(r.Context(), &section); err != nil {
+		curriculumError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   section,
+	})
+}
+
+func (c *CurriculumController) DeleteSection(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	sectionID, ok := objectIDParam(w, r, "sectionId")
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteSection(r.Context(), courseID, sectionID); err != nil {
+		curriculumError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Section successfully deleted",
+	})
+}
+
+func (c *CurriculumController) ReorderSections(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	ids, ok := decodeIDList(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.service.ReorderSections(r.Context(), courseID, ids); err != nil {
+		curriculumError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Sections successfully reordered",
+	})
+}
+
+func (c *CurriculumController) CreateLesson(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	sectionID, ok := objectIDParam(w, r, "sectionId")
+	if !ok {
+		return
+	}
+
+	var lesson models.Lesson
+	if err := json.NewDecoder(r.Body).Decode(&lesson); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+	lesson.ID = primitive.NilObjectID
+	lesson.CourseID = courseID
+	lesson.SectionID = sectionID
+
+	if err := c.service.CreateLesson(r.Context(), &lesson); err != nil {
+		curriculumError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusCreated,
+		Data:   lesson,
+	})
+}
+
+func (c *CurriculumController) UpdateLesson(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	lessonID, ok := objectIDParam(w, r, "lessonId")
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Title       string `json:"title"`
+		Type        string `json:"type"`
+		Content     string `json:"content"`
+		URL         string `json:"url"`
+		Duration    *int   `json:"duration"`
+		FreePreview *bool  `json:"free_preview"`
+		Optional    *bool  `json:"optional"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	lesson, err := c.service.GetLesson(r.Context(), courseID, lessonID, 0, true)
+	if err != nil {
+		curriculumError(w, err)
+		return
+	}
+
+	// Update only the fields that are provided
+	if request.Title != "" {
+		lesson.Title = request.Title
+	}
+	if request.Type != "" {
+		lesson.Type = request.Type
+	}
+	if request.Content != "" {
+		lesson.Content = request.Content
+	}
+	if request.URL != "" {
+		lesson.URL = request.URL
+	}
+	if request.Duration != nil {
+		lesson.Duration = *request.Duration
+	}
+	if request.FreePreview != nil {
+		lesson.FreePreview = *request.FreePreview
+	}
+	if request.Optional != nil {
+		lesson.Optional = *request.Optional
+	}
+
+	if err := c.service.UpdateLesson(r.Context(), lesson); err != nil {
+		curriculumError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   lesson,
+	})
+}
+
+func (c *CurriculumController) DeleteLesson(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	lessonID, ok := objectIDParam(w, r, "lessonId")
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteLesson(r.Context(), courseID, lessonID); err != nil {
+		curriculumError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Lesson successfully deleted",
+	})
+}
+
+func (c *CurriculumController) ReorderLessons(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	sectionID, ok := objectIDParam(w, r, "sectionId")
+	if !ok {
+		return
+	}
+	ids, ok := decodeIDList(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.service.ReorderLessons(r.Context(), courseID, sectionID, ids); err != nil {
+		curriculumError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Lessons successfully reordered",
+	})
+}
+
+func curriculumError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrCourseNotFound, models.ErrSectionNotFound, models.ErrLessonNotFound:
+		status = http.StatusNotFound
+	case models.ErrInvalidSection, models.ErrInvalidLesson, models.ErrInvalidLessonType, models.ErrInvalidOrder:
+		status = http.StatusBadRequest
+	case models.ErrLessonLocked:
+		status = http.StatusForbidden
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
+
+// objectIDParam parses the named route variable as an ObjectID, writing a
+// 400 response if it is malformed.
+func objectIDParam(w http.ResponseWriter, r *http.Request, name string) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)[name])
+	if err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid " + name,
+		})
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// decodeIDList reads a JSON array of hex ObjectIDs from the request body.
+func decodeIDList(w http.ResponseWriter, r *http.Request) ([]primitive.ObjectID, bool) {
+	var idStrings []string
+	if err := json.NewDecoder(r.Body).Decode(&idStrings); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return nil, false
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(idStrings))
+	for _, idStr := range idStrings {
+		id, err := primitive.ObjectIDFromHex(idStr)
+		if err != nil {
+			views.JSON(w, views.Response{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid ID format",
+			})
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	return ids, true
+}
@@ -0,0 +1,418 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DiscussionService interface {
+	ListThreads(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool, cursor string, limit int) (*models.ThreadPage, error)
+	GetThread(ctx context.Context, courseID, threadID primitive.ObjectID, userID int, admin bool) (*models.Thread, error)
+	CreateThread(ctx context.Context, thread *models.Thread, admin bool) error
+	UpdateThread(ctx context.Context, courseID, threadID primitive.ObjectID, userID int, admin bool, title, body string) (*models.Thread, error)
+	DeleteThread(ctx context.Context, courseID, threadID primitive.ObjectID, userID int, admin bool) error
+	VoteThread(ctx context.Context, courseID, threadID primitive.ObjectID, userID int, admin bool, upvote bool) error
+	PinThread(ctx context.Context, courseID, threadID primitive.ObjectID, userID int, admin bool, pinned bool) error
+	ListReplies(ctx context.Context, courseID, threadID primitive.ObjectID, userID int, admin bool, cursor string, limit int) (*models.ReplyPage, error)
+	CreateReply(ctx context.Context, reply *models.Reply, admin bool) error
+	UpdateReply(ctx context.Context, courseID, threadID, replyID primitive.ObjectID, userID int, admin bool, body string) (*models.Reply, error)
+	DeleteReply(ctx context.Context, courseID, threadID, replyID primitive.ObjectID, userID int, admin bool) error
+	VoteReply(ctx context.Context, courseID, threadID, replyID primitive.ObjectID, userID int, admin bool, upvote bool) error
+	MarkAnswer(ctx context.Context, courseID, threadID, replyID primitive.ObjectID, userID int, admin bool, answer bool) error
+}
+
+type DiscussionController struct {
+	service DiscussionService
+}
+
+func NewDiscussionController(service DiscussionService) *DiscussionController {
+	return &DiscussionController{service: service}
+}
+
+// ListThreads pages through threads with ?cursor= and ?limit=.
+func (c *DiscussionController) ListThreads(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	page, err := c.service.ListThreads(r.Context(), courseID, userID, isAdmin(r), r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		discussionError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   page,
+	})
+}
+
+func (c *DiscussionController) GetThread(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	threadID, ok := objectIDParam(w, r, "threadId")
+	if !ok {
+		return
+	}
+
+	thread, err := c.service.GetThread(r.Context(), courseID, threadID, userID, isAdmin(r))
+	if err != nil {
+		discussionError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   thread,
+	})
+}
+
+func (c *DiscussionController) CreateThread(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	thread := models.Thread{
+		CourseID: courseID,
+		AuthorID: userID,
+		Title:    request.Title,
+		Body:     request.Body,
+	}
+	if err := c.service.CreateThread(r.Context(), &thread, isAdmin(r)); err != nil {
+		discussionError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusCreated,
+		Data:   thread,
+	})
+}
+
+func (c *DiscussionController) UpdateThread(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	threadID, ok := objectIDParam(w, r, "threadId")
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	thread, err := c.service.UpdateThread(r.Context(), courseID, threadID, userID, isAdmin(r), request.Title, request.Body)
+	if err != nil {
+		discussionError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   thread,
+	})
+}
+
+func (c *DiscussionController) DeleteThread(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	threadID, ok := objectIDParam(w, r, "threadId")
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteThread(r.Context(), courseID, threadID, userID, isAdmin(r)); err != nil {
+		discussionError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Thread successfully deleted",
+	})
+}
+
+// VoteThread adds the caller's upvote on POST and removes it on DELETE.
+func (c *DiscussionController) VoteThread(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	threadID, ok := objectIDParam(w, r, "threadId")
+	if !ok {
+		return
+	}
+
+	upvote := r.Method == http.MethodPost
+	if err := c.service.VoteThread(r.Context(), courseID, threadID, userID, isAdmin(r), upvote); err != nil {
+		discussionError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Vote recorded",
+	})
+}
+
+func (c *DiscussionController) PinThread(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	threadID, ok := objectIDParam(w, r, "threadId")
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Pinned bool `json:"pinned"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	if err := c.service.PinThread(r.Context(), courseID, threadID, userID, isAdmin(r), request.Pinned); err != nil {
+		discussionError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Thread pin updated",
+	})
+}
+
+// ListReplies pages through a thread's replies with ?cursor= and ?limit=.
+func (c *DiscussionController) ListReplies(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	threadID, ok := objectIDParam(w, r, "threadId")
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	page, err := c.service.ListReplies(r.Context(), courseID, threadID, userID, isAdmin(r), r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		discussionError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   page,
+	})
+}
+
+func (c *DiscussionController) CreateReply(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	threadID, ok := objectIDParam(w, r, "threadId")
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Body string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	reply := models.Reply{
+		ThreadID: threadID,
+		CourseID: courseID,
+		AuthorID: userID,
+		Body:     request.Body,
+	}
+	if err := c.service.CreateReply(r.Context(), &reply, isAdmin(r)); err != nil {
+		discussionError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusCreated,
+		Data:   reply,
+	})
+}
+
+func (c *DiscussionController) UpdateReply(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, threadID, replyID, ok := replyParams(w, r)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Body string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	reply, err := c.service.UpdateReply(r.Context(), courseID, threadID, replyID, userID, isAdmin(r), request.Body)
+	if err != nil {
+		discussionError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   reply,
+	})
+}
+
+func (c *DiscussionController) DeleteReply(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, threadID, replyID, ok := replyParams(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteReply(r.Context(), courseID, threadID, replyID, userID, isAdmin(r)); err != nil {
+		discussionError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Reply successfully deleted",
+	})
+}
+
+// VoteReply adds the caller's upvote on POST and removes it on DELETE.
+func (c *DiscussionController) VoteReply(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, threadID, replyID, ok := replyParams(w, r)
+	if !ok {
+		return
+	}
+
+	upvote := r.Method == http.MethodPost
+	if err := c.service.VoteReply(r.Context(), courseID, threadID, replyID, userID, isAdmin(r), upvote); err != nil {
+		discussionError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Vote recorded",
+	})
+}
+
+func (c *DiscussionController) MarkAnswer(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, threadID, replyID, ok := replyParams(w, r)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Answer bool `json:"answer"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	if err := c.service.MarkAnswer(r.Context(), courseID, threadID, replyID, userID, isAdmin(r), request.Answer); err != nil {
+		discussionError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Answer updated",
+	})
+}
+
+func replyParams(w http.ResponseWriter, r *http.Request) (courseID, threadID, replyID primitive.ObjectID, ok bool) {
+	if courseID, ok = objectIDParam(w, r, "id"); !ok {
+		return
+	}
+	if threadID, ok = objectIDParam(w, r, "threadId"); !ok {
+		return
+	}
+	replyID, ok = objectIDParam(w, r, "replyId")
+	return
+}
+
+func discussionError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrCourseNotFound, models.ErrThreadNotFound, models.ErrReplyNotFound:
+		status = http.StatusNotFound
+	case models.ErrInvalidThread, models.ErrInvalidReply, models.ErrInvalidCursor:
+		status = http.StatusBadRequest
+	case models.ErrForbidden, models.ErrEditWindowClosed, models.ErrDeleteWindowClosed:
+		status = http.StatusForbidden
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
@@ -5,7 +5,10 @@ import (
 	"courses-api/models"
 	"courses-api/views"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -13,8 +16,11 @@ import (
 
 type EnrollmentService interface {
     CreateEnrollment(ctx context.Context, enrollment *models.Enrollment) error
+    EnrollUser(ctx context.Context, enrollment *models.Enrollment, overridePrerequisites bool) error
+    BulkEnroll(ctx context.Context, courseID primitive.ObjectID, userIDs []int, overridePrerequisites bool) (*models.BulkEnrollment, error)
     GetUserEnrollments(ctx context.Context, userID int) ([]models.Enrollment, error)
     CheckEnrollment(ctx context.Context, courseID primitive.ObjectID, userID int) (bool, error)
+    Unenroll(ctx context.Context, courseID primitive.ObjectID, userID, cancelledBy int, reason string) (*models.EnrollmentCancellation, error)
 }
 
 type EnrollmentController struct {
@@ -42,28 +48,86 @@ func (c *EnrollmentController) CreateEnrollment(w http.ResponseWriter, r *http.R
     enrollment.UserID = userID
 
     if err := c.service.CreateEnrollment(r.Context(), &enrollment); err != nil {
-        status := http.StatusInternalServerError
-        switch err {
-        case models.ErrCourseNotFound:
-            status = http.StatusNotFound
-        case models.ErrNoAvailableSeats:
-            status = http.StatusConflict
-        case models.ErrAlreadyEnrolled:
-            status = http.StatusConflict
-        }
+        enrollmentError(w, err)
+        return
+    }
+
+    views.JSON(w, views.Response{
+        Status: http.StatusCreated,
+        Data:   enrollment,
+    })
+}
+
+// EnrollUser lets an admin enroll any user with {"user_id": 1}, optionally
+// skipping the prerequisite check with "override_prerequisites": true.
+func (c *EnrollmentController) EnrollUser(w http.ResponseWriter, r *http.Request) {
+    courseID, ok := objectIDParam(w, r, "id")
+    if !ok {
+        return
+    }
+
+    var request struct {
+        UserID                int  `json:"user_id"`
+        OverridePrerequisites bool `json:"override_prerequisites"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.UserID <= 0 {
         views.JSON(w, views.Response{
-            Status: status,
-            Error:  err.Error(),
+            Status: http.StatusBadRequest,
+            Error:  "Invalid request body",
         })
         return
     }
 
+    enrollment := models.Enrollment{
+        CourseID: courseID,
+        UserID:   request.UserID,
+    }
+    if err := c.service.EnrollUser(r.Context(), &enrollment, request.OverridePrerequisites); err != nil {
+        enrollmentError(w, err)
+        return
+    }
+
     views.JSON(w, views.Response{
         Status: http.StatusCreated,
         Data:   enrollment,
     })
 }
 
+// BulkEnroll lets an admin enroll {"user_ids": [1, 2, 3]} at once. Each user
+// gets their own result, so the response is 200 even if some failed.
+func (c *EnrollmentController) BulkEnroll(w http.ResponseWriter, r *http.Request) {
+    courseID, ok := objectIDParam(w, r, "id")
+    if !ok {
+        return
+    }
+
+    var request struct {
+        UserIDs               []int `json:"user_ids"`
+        OverridePrerequisites bool  `json:"override_prerequisites"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+        views.JSON(w, views.Response{
+            Status: http.StatusBadRequest,
+            Error:  "Invalid request body",
+        })
+        return
+    }
+
+    result, err := c.service.BulkEnroll(r.Context(), courseID, request.UserIDs, request.OverridePrerequisites)
+    if err != nil {
+        views.JSON(w, views.Response{
+            Status: http.StatusBadRequest,
+            Error:  err.Error(),
+        })
+        return
+    }
+
+    views.JSON(w, views.Response{
+        Status: http.StatusOK,
+        Data:   result,
+    })
+}
+
 func (c *EnrollmentController) CheckEnrollment(w http.ResponseWriter, r *http.Request) {
     userID := r.Context().Value("userID").(int)
     vars := mux.Vars(r)
@@ -91,4 +155,128 @@ func (c *EnrollmentController) CheckEnrollment(w http.ResponseWriter, r *http.Re
             "enrolled": enrolled,
         },
     })
-} 
\ No newline at end of file
+}
+
+// Unenroll lets a student leave a course, optionally giving a reason.
+func (c *EnrollmentController) Unenroll(w http.ResponseWriter, r *http.Request) {
+    userID := r.Context().Value("userID").(int)
+    courseID, ok := objectIDParam(w, r, "courseId")
+    if !ok {
+        return
+    }
+
+    reason, ok := decodeReason(w, r)
+    if !ok {
+        return
+    }
+
+    cancellation, err := c.service.Unenroll(r.Context(), courseID, userID, userID, reason)
+    if err != nil {
+        unenrollError(w, err)
+        return
+    }
+
+    views.JSON(w, views.Response{
+        Status: http.StatusOK,
+        Data:   cancellation,
+    })
+}
+
+// RemoveEnrollment lets an admin remove any user from a course.
+func (c *EnrollmentController) RemoveEnrollment(w http.ResponseWriter, r *http.Request) {
+    adminID := r.Context().Value("userID").(int)
+    courseID, ok := objectIDParam(w, r, "id")
+    if !ok {
+        return
+    }
+    userID, err := strconv.Atoi(mux.Vars(r)["userId"])
+    if err != nil {
+        views.JSON(w, views.Response{
+            Status: http.StatusBadRequest,
+            Error:  "Invalid user ID",
+        })
+        return
+    }
+
+    reason, ok := decodeReason(w, r)
+    if !ok {
+        return
+    }
+
+    cancellation, err := c.service.Unenroll(r.Context(), courseID, userID, adminID, reason)
+    if err != nil {
+        unenrollError(w, err)
+        return
+    }
+
+    views.JSON(w, views.Response{
+        Status: http.StatusOK,
+        Data:   cancellation,
+    })
+}
+
+// decodeReason reads the optional {"reason": "..."} body.
+func decodeReason(w http.ResponseWriter, r *http.Request) (string, bool) {
+    var request struct {
+        Reason string `json:"reason"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+        views.JSON(w, views.Response{
+            Status: http.StatusBadRequest,
+            Error:  "Invalid request body",
+        })
+        return "", false
+    }
+    return request.Reason, true
+}
+
+func enrollmentError(w http.ResponseWriter, err error) {
+    if prerequisitesError(w, err) {
+        return
+    }
+    status := http.StatusInternalServerError
+    switch err {
+    case models.ErrCourseNotFound:
+        status = http.StatusNotFound
+    case models.ErrNoAvailableSeats:
+        status = http.StatusConflict
+    case models.ErrAlreadyEnrolled:
+        status = http.StatusConflict
+    case models.ErrEnrollmentNotOpen, models.ErrEnrollmentClosed:
+        status = http.StatusForbidden
+    case models.ErrPaymentRequired:
+        status = http.StatusPaymentRequired
+    }
+    views.JSON(w, views.Response{
+        Status: status,
+        Error:  err.Error(),
+    })
+}
+
+// prerequisitesError responds with the missing prerequisites if err lists them.
+func prerequisitesError(w http.ResponseWriter, err error) bool {
+    var missing *models.PrerequisitesError
+    if !errors.As(err, &missing) {
+        return false
+    }
+    views.JSON(w, views.Response{
+        Status: http.StatusForbidden,
+        Data:   missing.Missing,
+        Error:  err.Error(),
+    })
+    return true
+}
+
+func unenrollError(w http.ResponseWriter, err error) {
+    status := http.StatusInternalServerError
+    switch err {
+    case models.ErrEnrollmentNotFound, models.ErrCourseNotFound:
+        status = http.StatusNotFound
+    case models.ErrInvalidReason:
+        status = http.StatusBadRequest
+    }
+    views.JSON(w, views.Response{
+        Status: status,
+        Error:  err.Error(),
+    })
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type InstructorService interface {
+	GetInstructor(ctx context.Context, userID, viewerID int, admin bool) (*models.InstructorPage, error)
+	SaveProfile(ctx context.Context, profile *models.InstructorProfile, callerID int, admin bool) error
+	DeleteProfile(ctx context.Context, userID int) error
+}
+
+type InstructorController struct {
+	service InstructorService
+}
+
+func NewInstructorController(service InstructorService) *InstructorController {
+	return &InstructorController{service: service}
+}
+
+// GetInstructor is public; signed-in instructors also see their unpublished courses.
+func (c *InstructorController) GetInstructor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+	viewerID, _ := r.Context().Value("userID").(int)
+
+	page, err := c.service.GetInstructor(r.Context(), userID, viewerID, isAdmin(r))
+	if err != nil {
+		instructorError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   page,
+	})
+}
+
+// SaveProfile lets admins create or edit any instructor profile, and
+// instructors edit their own.
+func (c *InstructorController) SaveProfile(w http.ResponseWriter, r *http.Request) {
+	callerID := r.Context().Value("userID").(int)
+	userID, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var profile models.InstructorProfile
+	if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+	profile.UserID = userID
+
+	if err := c.service.SaveProfile(r.Context(), &profile, callerID, isAdmin(r)); err != nil {
+		instructorError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   profile,
+	})
+}
+
+func (c *InstructorController) DeleteProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteProfile(r.Context(), userID); err != nil {
+		instructorError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Instructor profile deleted",
+	})
+}
+
+// userIDParam parses the {userId} path parameter, writing a 400 response if
+// it is not a positive integer.
+func userIDParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	userID, err := strconv.Atoi(mux.Vars(r)["userId"])
+	if err != nil || userID <= 0 {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid user ID",
+		})
+		return 0, false
+	}
+	return userID, true
+}
+
+func instructorError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrInstructorNotFound, models.ErrUserNotFound:
+		status = http.StatusNotFound
+	case models.ErrInvalidInstructorProfile:
+		status = http.StatusBadRequest
+	case models.ErrForbidden:
+		status = http.StatusForbidden
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"encoding/json"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type LicenseService interface {
+	CreateLicense(ctx context.Context, license *models.SeatLicense, adminID int) error
+	ListLicenses(ctx context.Context, userID int, admin bool) ([]models.SeatLicense, error)
+	GetLicense(ctx context.Context, id primitive.ObjectID, userID int, admin bool) (*models.LicenseDetail, error)
+	AssignSeat(ctx context.Context, id primitive.ObjectID, userID, managerID int, admin bool) (*models.Enrollment, error)
+	RevokeSeat(ctx context.Context, id primitive.ObjectID, userID, managerID int, admin bool) (*models.EnrollmentCancellation, error)
+}
+
+type LicenseController struct {
+	service LicenseService
+}
+
+func NewLicenseController(service LicenseService) *LicenseController {
+	return &LicenseController{service: service}
+}
+
+// CreateLicense lets an admin sell a pool of seats, e.g.
+// {"course_id": "...", "organization": "Acme", "manager_ids": [7], "seats": 25}.
+func (c *LicenseController) CreateLicense(w http.ResponseWriter, r *http.Request) {
+	adminID := r.Context().Value("userID").(int)
+
+	var request struct {
+		CourseID     primitive.ObjectID `json:"course_id"`
+		Organization string             `json:"organization"`
+		ManagerIDs   []int              `json:"manager_ids"`
+		Seats        int                `json:"seats"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	license := models.SeatLicense{
+		CourseID:     request.CourseID,
+		Organization: request.Organization,
+		ManagerIDs:   request.ManagerIDs,
+		Seats:        request.Seats,
+	}
+	if err := c.service.CreateLicense(r.Context(), &license, adminID); err != nil {
+		licenseError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusCreated,
+		Data:   license,
+	})
+}
+
+func (c *LicenseController) ListLicenses(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+
+	licenses, err := c.service.ListLicenses(r.Context(), userID, isAdmin(r))
+	if err != nil {
+		licenseError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   licenses,
+	})
+}
+
+func (c *LicenseController) GetLicense(w http.ResponseWriter, r *http.Request) {
+	licenseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	userID := r.Context().Value("userID").(int)
+
+	license, err := c.service.GetLicense(r.Context(), licenseID, userID, isAdmin(r))
+	if err != nil {
+		licenseError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   license,
+	})
+}
+
+// AssignSeat enrolls {"user_id": 1} on one of the license's seats.
+func (c *LicenseController) AssignSeat(w http.ResponseWriter, r *http.Request) {
+	licenseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	managerID := r.Context().Value("userID").(int)
+
+	var request struct {
+		UserID int `json:"user_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.UserID <= 0 {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	enrollment, err := c.service.AssignSeat(r.Context(), licenseID, request.UserID, managerID, isAdmin(r))
+	if err != nil {
+		licenseError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusCreated,
+		Data:   enrollment,
+	})
+}
+
+func (c *LicenseController) RevokeSeat(w http.ResponseWriter, r *http.Request) {
+	licenseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	userID, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+	managerID := r.Context().Value("userID").(int)
+
+	cancellation, err := c.service.RevokeSeat(r.Context(), licenseID, userID, managerID, isAdmin(r))
+	if err != nil {
+		licenseError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   cancellation,
+	})
+}
+
+func licenseError(w http.ResponseWriter, err error) {
+	if prerequisitesError(w, err) {
+		return
+	}
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrLicenseNotFound, models.ErrCourseNotFound, models.ErrEnrollmentNotFound:
+		status = http.StatusNotFound
+	case models.ErrForbidden, models.ErrEnrollmentNotOpen, models.ErrEnrollmentClosed:
+		status = http.StatusForbidden
+	case models.ErrInvalidLicense:
+		status = http.StatusBadRequest
+	case models.ErrNoLicenseSeats, models.ErrNoAvailableSeats, models.ErrAlreadyEnrolled:
+		status = http.StatusConflict
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
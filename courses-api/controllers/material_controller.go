@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"errors"
+	"io"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// materialMemory is how much of an upload is buffered in memory before the
+// multipart parser spills it to a temporary file.
+const materialMemory = 8 << 20
+
+type MaterialService interface {
+	ListMaterials(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool) (*models.MaterialList, error)
+	UploadMaterial(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool, name string, size int64, body io.Reader) (*models.Material, error)
+	DeleteMaterial(ctx context.Context, courseID, materialID primitive.ObjectID, userID int, admin bool) error
+	DownloadLink(ctx context.Context, courseID, materialID primitive.ObjectID, userID int, admin bool) (*models.MaterialLink, error)
+}
+
+type MaterialController struct {
+	service MaterialService
+}
+
+func NewMaterialController(service MaterialService) *MaterialController {
+	return &MaterialController{service: service}
+}
+
+func (c *MaterialController) ListMaterials(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	userID := r.Context().Value("userID").(int)
+
+	list, err := c.service.ListMaterials(r.Context(), courseID, userID, isAdmin(r))
+	if err != nil {
+		materialError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   list,
+	})
+}
+
+// UploadMaterial accepts a multipart form with the file in the "file" field.
+func (c *MaterialController) UploadMaterial(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	userID := r.Context().Value("userID").(int)
+
+	r.Body = http.MaxBytesReader(w, r.Body, models.MaxMaterialBytes+multipartOverhead)
+	if err := r.ParseMultipartForm(materialMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			materialError(w, models.ErrMaterialTooLarge)
+			return
+		}
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "a multipart form with a file is required",
+		})
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "a multipart form with a file is required",
+		})
+		return
+	}
+	defer file.Close()
+
+	material, err := c.service.UploadMaterial(r.Context(), courseID, userID, isAdmin(r), header.Filename, header.Size, file)
+	if err != nil {
+		materialError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusCreated,
+		Data:   material,
+	})
+}
+
+func (c *MaterialController) DeleteMaterial(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	materialID, ok := objectIDParam(w, r, "materialId")
+	if !ok {
+		return
+	}
+	userID := r.Context().Value("userID").(int)
+
+	if err := c.service.DeleteMaterial(r.Context(), courseID, materialID, userID, isAdmin(r)); err != nil {
+		materialError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Material deleted",
+	})
+}
+
+// DownloadLink returns a short-lived URL rather than the file itself, so
+// downloads do not pass through the API replicas.
+func (c *MaterialController) DownloadLink(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	materialID, ok := objectIDParam(w, r, "materialId")
+	if !ok {
+		return
+	}
+	userID := r.Context().Value("userID").(int)
+
+	link, err := c.service.DownloadLink(r.Context(), courseID, materialID, userID, isAdmin(r))
+	if err != nil {
+		materialError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   link,
+	})
+}
+
+func materialError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrCourseNotFound, models.ErrMaterialNotFound:
+		status = http.StatusNotFound
+	case models.ErrForbidden:
+		status = http.StatusForbidden
+	case models.ErrInvalidMaterial:
+		status = http.StatusUnsupportedMediaType
+	case models.ErrMaterialTooLarge:
+		status = http.StatusRequestEntityTooLarge
+	case models.ErrMaterialQuotaExceeded:
+		status = http.StatusConflict
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
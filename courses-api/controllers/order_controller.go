@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxWebhookSize bounds webhook bodies read into memory for verification.
+const maxWebhookSize = 64 << 10
+
+type OrderService interface {
+	Quote(ctx context.Context, courseID primitive.ObjectID, userID int, couponCode string) (*models.Quote, error)
+	Checkout(ctx context.Context, courseID primitive.ObjectID, userID int, method, couponCode string) (*models.Order, error)
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+	GetOrder(ctx context.Context, id primitive.ObjectID, userID int, admin bool) (*models.Order, error)
+	GetUserOrders(ctx context.Context, userID int) ([]models.Order, error)
+	CancelOrder(ctx context.Context, id primitive.ObjectID, userID int) (*models.Order, error)
+}
+
+type OrderController struct {
+	service OrderService
+}
+
+func NewOrderController(service OrderService) *OrderController {
+	return &OrderController{service: service}
+}
+
+// Quote prices a course for the user, with an optional ?coupon= code.
+func (c *OrderController) Quote(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	quote, err := c.service.Quote(r.Context(), courseID, userID, r.URL.Query().Get("coupon"))
+	if err != nil {
+		orderError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   quote,
+	})
+}
+
+// Checkout starts a purchase with {"payment_method": "...", "coupon_code": "..."}. Pending
+// payments are reported with 202 Accepted until the provider confirms them.
+func (c *OrderController) Checkout(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var request struct {
+		PaymentMethod string `json:"payment_method"`
+		CouponCode    string `json:"coupon_code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	order, err := c.service.Checkout(r.Context(), courseID, userID, request.PaymentMethod, request.CouponCode)
+	if err != nil {
+		if order != nil {
+			views.JSON(w, views.Response{
+				Status: http.StatusPaymentRequired,
+				Data:   order,
+				Error:  err.Error(),
+			})
+			return
+		}
+		orderError(w, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if order.Status == models.OrderPending {
+		status = http.StatusAccepted
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Data:   order,
+	})
+}
+
+// PaymentWebhook receives provider notifications. It is unauthenticated; the
+// signature header is what proves the request came from the provider.
+func (c *OrderController) PaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookSize))
+	if err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	if err := c.service.HandleWebhook(r.Context(), payload, r.Header.Get("X-Payment-Signature")); err != nil {
+		orderError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Webhook processed",
+	})
+}
+
+func (c *OrderController) GetUserOrders(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+
+	orders, err := c.service.GetUserOrders(r.Context(), userID)
+	if err != nil {
+		orderError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   orders,
+	})
+}
+
+func (c *OrderController) GetOrder(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	orderID, ok := objectIDParam(w, r, "orderId")
+	if !ok {
+		return
+	}
+
+	order, err := c.service.GetOrder(r.Context(), orderID, userID, isAdmin(r))
+	if err != nil {
+		orderError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   order,
+	})
+}
+
+func (c *OrderController) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	orderID, ok := objectIDParam(w, r, "orderId")
+	if !ok {
+		return
+	}
+
+	order, err := c.service.CancelOrder(r.Context(), orderID, userID)
+	if err != nil {
+		orderError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   order,
+	})
+}
+
+func orderError(w http.ResponseWriter, err error) {
+	if prerequisitesError(w, err) {
+		return
+	}
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrCourseNotFound, models.ErrOrderNotFound, models.ErrCouponNotFound:
+		status = http.StatusNotFound
+	case models.ErrInvalidPaymentMethod:
+		status = http.StatusBadRequest
+	case models.ErrCouponExpired, models.ErrCouponNotApplicable, models.ErrCouponExhausted,
+		models.ErrCouponLimitReached:
+		status = http.StatusUnprocessableEntity
+	case models.ErrInvalidSignature:
+		status = http.StatusUnauthorized
+	case models.ErrCourseIsFree, models.ErrCheckoutInProgress, models.ErrOrderNotPending,
+		models.ErrAlreadyEnrolled, models.ErrNoAvailableSeats:
+		status = http.StatusConflict
+	case models.ErrEnrollmentNotOpen, models.ErrEnrollmentClosed:
+		status = http.StatusForbidden
+	case models.ErrPaymentFailed:
+		status = http.StatusBadGateway
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
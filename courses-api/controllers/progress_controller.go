@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ProgressService interface {
+	MarkLesson(ctx context.Context, courseID, lessonID primitive.ObjectID, userID int, status string) (*models.CourseProgress, error)
+	GetProgress(ctx context.Context, courseID primitive.ObjectID, userID int) (*models.CourseProgress, error)
+	GetUserProgress(ctx context.Context, userID int) (map[string]models.CourseProgress, error)
+}
+
+type ProgressController struct {
+	service ProgressService
+}
+
+func NewProgressController(service ProgressService) *ProgressController {
+	return &ProgressController{service: service}
+}
+
+func (c *ProgressController) StartLesson(w http.ResponseWriter, r *http.Request) {
+	c.markLesson(w, r, models.LessonStarted)
+}
+
+func (c *ProgressController) CompleteLesson(w http.ResponseWriter, r *http.Request) {
+	c.markLesson(w, r, models.LessonCompleted)
+}
+
+func (c *ProgressController) markLesson(w http.ResponseWriter, r *http.Request, status string) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	lessonID, ok := objectIDParam(w, r, "lessonId")
+	if !ok {
+		return
+	}
+
+	progress, err := c.service.MarkLesson(r.Context(), courseID, lessonID, userID, status)
+	if err != nil {
+		progressError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   progress,
+	})
+}
+
+// GetProgress returns the caller's own progress through a course.
+func (c *ProgressController) GetProgress(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	progress, err := c.service.GetProgress(r.Context(), courseID, userID)
+	if err != nil {
+		progressError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   progress,
+	})
+}
+
+// GetStudentProgress returns any student's progress through a course.
+func (c *ProgressController) GetStudentProgress(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	userID, err := strconv.Atoi(mux.Vars(r)["userId"])
+	if err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid user ID",
+		})
+		return
+	}
+
+	progress, err := c.service.GetProgress(r.Context(), courseID, userID)
+	if err != nil {
+		progressError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   progress,
+	})
+}
+
+func progressError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrEnrollmentNotFound, models.ErrLessonNotFound:
+		status = http.StatusNotFound
+	case models.ErrInvalidProgress:
+		status = http.StatusBadRequest
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
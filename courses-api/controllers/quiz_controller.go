@@ -0,0 +1,279 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"encoding/json"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type QuizService interface {
+	CreateQuiz(ctx context.Context, quiz *models.Quiz) error
+	UpdateQuiz(ctx context.Context, quiz *models.Quiz) error
+	DeleteQuiz(ctx context.Context, courseID, quizID primitive.ObjectID) error
+	ListQuizzes(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool) ([]models.Quiz, error)
+	GetQuiz(ctx context.Context, courseID, quizID primitive.ObjectID, userID int, admin bool) (*models.Quiz, error)
+	StartAttempt(ctx context.Context, courseID, quizID primitive.ObjectID, userID int) (*models.QuizAttempt, error)
+	SubmitAttempt(ctx context.Context, courseID, quizID, attemptID primitive.ObjectID, userID int, answers []models.Answer) (*models.QuizAttempt, error)
+	GetAttempts(ctx context.Context, courseID, quizID primitive.ObjectID, userID int) ([]models.QuizAttempt, error)
+	GetStats(ctx context.Context, courseID, quizID primitive.ObjectID) (*models.QuizStats, error)
+}
+
+type QuizController struct {
+	service QuizService
+}
+
+func NewQuizController(service QuizService) *QuizController {
+	return &QuizController{service: service}
+}
+
+func (c *QuizController) CreateQuiz(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var quiz models.Quiz
+	if err := json.NewDecoder(r.Body).Decode(&quiz); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+	quiz.ID = primitive.NilObjectID
+	quiz.CourseID = courseID
+
+	if err := c.service.CreateQuiz(r.Context(), &quiz); err != nil {
+		quizError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusCreated,
+		Data:   quiz,
+	})
+}
+
+// UpdateQuiz replaces the quiz definition.
+func (c *QuizController) UpdateQuiz(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	quizID, ok := objectIDParam(w, r, "quizId")
+	if !ok {
+		return
+	}
+
+	var quiz models.Quiz
+	if err := json.NewDecoder(r.Body).Decode(&quiz); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+	quiz.ID = quizID
+	quiz.CourseID = courseID
+
+	if err := c.service.UpdateQuiz(r.Context(), &quiz); err != nil {
+		quizError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   quiz,
+	})
+}
+
+func (c *QuizController) DeleteQuiz(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	quizID, ok := objectIDParam(w, r, "quizId")
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteQuiz(r.Context(), courseID, quizID); err != nil {
+		quizError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Quiz successfully deleted",
+	})
+}
+
+func (c *QuizController) ListQuizzes(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	quizzes, err := c.service.ListQuizzes(r.Context(), courseID, userID, isAdmin(r))
+	if err != nil {
+		quizError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   quizzes,
+	})
+}
+
+func (c *QuizController) GetQuiz(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	quizID, ok := objectIDParam(w, r, "quizId")
+	if !ok {
+		return
+	}
+
+	quiz, err := c.service.GetQuiz(r.Context(), courseID, quizID, userID, isAdmin(r))
+	if err != nil {
+		quizError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   quiz,
+	})
+}
+
+func (c *QuizController) StartAttempt(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	quizID, ok := objectIDParam(w, r, "quizId")
+	if !ok {
+		return
+	}
+
+	attempt, err := c.service.StartAttempt(r.Context(), courseID, quizID, userID)
+	if err != nil {
+		quizError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusCreated,
+		Data:   attempt,
+	})
+}
+
+func (c *QuizController) SubmitAttempt(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	quizID, ok := objectIDParam(w, r, "quizId")
+	if !ok {
+		return
+	}
+	attemptID, ok := objectIDParam(w, r, "attemptId")
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Answers []models.Answer `json:"answers"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	attempt, err := c.service.SubmitAttempt(r.Context(), courseID, quizID, attemptID, userID, request.Answers)
+	if err != nil {
+		quizError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   attempt,
+	})
+}
+
+func (c *QuizController) GetAttempts(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	quizID, ok := objectIDParam(w, r, "quizId")
+	if !ok {
+		return
+	}
+
+	attempts, err := c.service.GetAttempts(r.Context(), courseID, quizID, userID)
+	if err != nil {
+		quizError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   attempts,
+	})
+}
+
+func (c *QuizController) GetStats(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	quizID, ok := objectIDParam(w, r, "quizId")
+	if !ok {
+		return
+	}
+
+	stats, err := c.service.GetStats(r.Context(), courseID, quizID)
+	if err != nil {
+		quizError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   stats,
+	})
+}
+
+func quizError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrCourseNotFound, models.ErrQuizNotFound, models.ErrAttemptNotFound:
+		status = http.StatusNotFound
+	case models.ErrEnrollmentNotFound:
+		status = http.StatusForbidden
+	case models.ErrInvalidQuiz, models.ErrInvalidQuestion, models.ErrInvalidQuestionType:
+		status = http.StatusBadRequest
+	case models.ErrAttemptLimitReached, models.ErrAttemptClosed, models.ErrAttemptExpired:
+		status = http.StatusConflict
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ReviewService interface {
+	CreateReview(ctx context.Context, review *models.Review) error
+	UpdateReview(ctx context.Context, courseID primitive.ObjectID, userID int, rating int, comment string) (*models.Review, error)
+	GetReviews(ctx context.Context, query models.ReviewQuery) (*models.ReviewPage, error)
+	SetHidden(ctx context.Context, courseID, reviewID primitive.ObjectID, hidden bool) error
+}
+
+type ReviewController struct {
+	service ReviewService
+}
+
+func NewReviewController(service ReviewService) *ReviewController {
+	return &ReviewController{service: service}
+}
+
+func (c *ReviewController) CreateReview(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var review models.Review
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+	review.ID = primitive.NilObjectID
+	review.CourseID = courseID
+	review.UserID = userID
+
+	if err := c.service.CreateReview(r.Context(), &review); err != nil {
+		reviewError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusCreated,
+		Data:   review,
+	})
+}
+
+// UpdateReview edits the caller's own review of the course.
+func (c *ReviewController) UpdateReview(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Rating  int    `json:"rating"`
+		Comment string `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	review, err := c.service.UpdateReview(r.Context(), courseID, userID, request.Rating, request.Comment)
+	if err != nil {
+		reviewError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   review,
+	})
+}
+
+// GetReviews lists reviews with ?page=, ?limit= and ?sort=newest|oldest|highest|lowest.
+// Admins may add ?include_hidden=true.
+func (c *ReviewController) GetReviews(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	params := r.URL.Query()
+	page, _ := strconv.Atoi(params.Get("page"))
+	limit, _ := strconv.Atoi(params.Get("limit"))
+	query := models.ReviewQuery{
+		CourseID:      courseID,
+		Sort:          params.Get("sort"),
+		Page:          page,
+		Limit:         limit,
+		IncludeHidden: isAdmin(r) && params.Get("include_hidden") == "true",
+	}
+
+	result, err := c.service.GetReviews(r.Context(), query)
+	if err != nil {
+		reviewError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   result,
+	})
+}
+
+func (c *ReviewController) SetHidden(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	reviewID, ok := objectIDParam(w, r, "reviewId")
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Hidden bool `json:"hidden"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	if err := c.service.SetHidden(r.Context(), courseID, reviewID, request.Hidden); err != nil {
+		reviewError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Review moderation updated",
+	})
+}
+
+func reviewError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrCourseNotFound, models.ErrReviewNotFound:
+		status = http.StatusNotFound
+	case models.ErrInvalidRating:
+		status = http.StatusBadRequest
+	case models.ErrNotEnrolled:
+		status = http.StatusForbidden
+	case models.ErrAlreadyReviewed:
+		status = http.StatusConflict
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
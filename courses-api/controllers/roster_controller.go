@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RosterService interface {
+	RosterCourse(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool) (*models.Course, error)
+	GetRoster(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool, page, limit int) (*models.RosterPage, error)
+	ExportRoster(ctx context.Context, course *models.Course, w io.Writer) error
+}
+
+type RosterController struct {
+	service RosterService
+}
+
+func NewRosterController(service RosterService) *RosterController {
+	return &RosterController{service: service}
+}
+
+// GetRoster lists enrolled students with ?page= and ?limit=.
+func (c *RosterController) GetRoster(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	userID := r.Context().Value("userID").(int)
+
+	params := r.URL.Query()
+	page, _ := strconv.Atoi(params.Get("page"))
+	limit, _ := strconv.Atoi(params.Get("limit"))
+	roster, err := c.service.GetRoster(r.Context(), courseID, userID, isAdmin(r), page, limit)
+	if err != nil {
+		rosterError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   roster,
+	})
+}
+
+// ExportRoster streams the whole roster as a CSV download.
+func (c *RosterController) ExportRoster(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+	userID := r.Context().Value("userID").(int)
+
+	course, err := c.service.RosterCourse(r.Context(), courseID, userID, isAdmin(r))
+	if err != nil {
+		rosterError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="roster-`+courseID.Hex()+`.csv"`)
+	w.WriteHeader(http.StatusOK)
+	// The status is already sent, so a failure can only cut the export short
+	if err := c.service.ExportRoster(r.Context(), course, w); err != nil {
+		log.Printf("Error exporting roster for course %s: %v", courseID.Hex(), err)
+	}
+}
+
+func rosterError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrCourseNotFound:
+		status = http.StatusNotFound
+	case models.ErrForbidden:
+		status = http.StatusForbidden
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
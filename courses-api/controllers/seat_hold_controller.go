@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SeatHoldService interface {
+	CreateHold(ctx context.Context, courseID primitive.ObjectID, userID, minutes int) (*models.SeatHold, error)
+	ConfirmHold(ctx context.Context, holdID primitive.ObjectID, userID int) (*models.Enrollment, error)
+	ReleaseHold(ctx context.Context, holdID primitive.ObjectID, userID int) error
+}
+
+type SeatHoldController struct {
+	service SeatHoldService
+}
+
+func NewSeatHoldController(service SeatHoldService) *SeatHoldController {
+	return &SeatHoldController{service: service}
+}
+
+// CreateHold reserves a seat for {"minutes": n}, or the default hold time
+// when the body is empty.
+func (c *SeatHoldController) CreateHold(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Minutes int `json:"minutes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+		views.JSON(w, views.Response{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid request body",
+		})
+		return
+	}
+
+	hold, err := c.service.CreateHold(r.Context(), courseID, userID, request.Minutes)
+	if err != nil {
+		seatHoldError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusCreated,
+		Data:   hold,
+	})
+}
+
+func (c *SeatHoldController) ConfirmHold(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	holdID, ok := objectIDParam(w, r, "holdId")
+	if !ok {
+		return
+	}
+
+	enrollment, err := c.service.ConfirmHold(r.Context(), holdID, userID)
+	if err != nil {
+		seatHoldError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusCreated,
+		Data:   enrollment,
+	})
+}
+
+func (c *SeatHoldController) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	holdID, ok := objectIDParam(w, r, "holdId")
+	if !ok {
+		return
+	}
+
+	if err := c.service.ReleaseHold(r.Context(), holdID, userID); err != nil {
+		seatHoldError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Seat hold released",
+	})
+}
+
+func seatHoldError(w http.ResponseWriter, err error) {
+	if prerequisitesError(w, err) {
+		return
+	}
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrCourseNotFound, models.ErrHoldNotFound:
+		status = http.StatusNotFound
+	case models.ErrInvalidHoldTime:
+		status = http.StatusBadRequest
+	case models.ErrNoAvailableSeats, models.ErrAlreadyEnrolled, models.ErrHoldExists:
+		status = http.StatusConflict
+	case models.ErrEnrollmentNotOpen, models.ErrEnrollmentClosed:
+		status = http.StatusForbidden
+	case models.ErrHoldExpired:
+		status = http.StatusGone
+	case models.ErrPaymentRequired:
+		status = http.StatusPaymentRequired
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/views"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WaitlistService interface {
+	Join(ctx context.Context, courseID primitive.ObjectID, userID int) (*models.WaitlistPosition, error)
+	GetPosition(ctx context.Context, courseID primitive.ObjectID, userID int) (*models.WaitlistPosition, error)
+	Leave(ctx context.Context, courseID primitive.ObjectID, userID int) error
+	GetWaitlist(ctx context.Context, courseID primitive.ObjectID) ([]models.WaitlistEntry, error)
+}
+
+type WaitlistController struct {
+	service WaitlistService
+}
+
+func NewWaitlistController(service WaitlistService) *WaitlistController {
+	return &WaitlistController{service: service}
+}
+
+func (c *WaitlistController) JoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	position, err := c.service.Join(r.Context(), courseID, userID)
+	if err != nil {
+		waitlistError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusCreated,
+		Data:   position,
+	})
+}
+
+func (c *WaitlistController) GetPosition(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	position, err := c.service.GetPosition(r.Context(), courseID, userID)
+	if err != nil {
+		waitlistError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   position,
+	})
+}
+
+func (c *WaitlistController) LeaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(int)
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.service.Leave(r.Context(), courseID, userID); err != nil {
+		waitlistError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status:  http.StatusOK,
+		Message: "Removed from waitlist",
+	})
+}
+
+func (c *WaitlistController) GetWaitlist(w http.ResponseWriter, r *http.Request) {
+	courseID, ok := objectIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	entries, err := c.service.GetWaitlist(r.Context(), courseID)
+	if err != nil {
+		waitlistError(w, err)
+		return
+	}
+
+	views.JSON(w, views.Response{
+		Status: http.StatusOK,
+		Data:   entries,
+	})
+}
+
+func waitlistError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case models.ErrCourseNotFound, models.ErrWaitlistEntryNotFound:
+		status = http.StatusNotFound
+	case models.ErrAlreadyEnrolled, models.ErrAlreadyWaitlisted, models.ErrSeatsAvailable:
+		status = http.StatusConflict
+	}
+	views.JSON(w, views.Response{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
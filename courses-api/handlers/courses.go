@@ -66,19 +66,8 @@ func CreateCourse(client *mongo.Client, w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Validate category is one of the allowed values
-	validCategories := []string{"web-development", "mobile-development", "data-science", "design", "business"}
-	isValidCategory := false
-	for _, validCat := range validCategories {
-		if course.Category == validCat {
-			isValidCategory = true
-			break
-		}
-	}
-
-	if !isValidCategory {
-		jsonResponse(w, http.StatusBadRequest, map[string]string{
-			"error": "Invalid category. Must be one of: web-development, mobile-development, data-science, design, business"})
+	// Validate category against the categories collection
+	if !validCategory(client, w, course.Category) {
 		return
 	}
 
@@ -111,6 +100,21 @@ func CreateCourse(client *mongo.Client, w http.ResponseWriter, r *http.Request)
 	publishToRabbitMQ(course)
 }
 
+// validCategory checks that the category exists, writing the error response if not
+func validCategory(client *mongo.Client, w http.ResponseWriter, slug string) bool {
+	collection := client.Database("coursesdb").Collection("categories")
+	count, err := collection.CountDocuments(context.TODO(), bson.M{"slug": slug})
+	if err != nil {
+		jsonResponse(w, http.StatusInternalServerError, map[string]string{"error": "Error checking category"})
+		return false
+	}
+	if count == 0 {
+		jsonResponse(w, http.StatusBadRequest, map[string]string{"error": "Invalid category"})
+		return false
+	}
+	return true
+}
+
 // GetAllCourses - Obtener todos los cursos
 func GetAllCourses(client *mongo.Client, w http.ResponseWriter, r *http.Request) {
 	collection := client.Database("coursesdb").Collection("courses")
@@ -195,19 +199,8 @@ func UpdateCourse(client *mongo.Client, w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Validate category
-	validCategories := []string{"web-development", "mobile-development", "data-science", "design", "business"}
-	isValidCategory := false
-	for _, validCat := range validCategories {
-		if course.Category == validCat {
-			isValidCategory = true
-			break
-		}
-	}
-
-	if !isValidCategory {
-		jsonResponse(w, http.StatusBadRequest, map[string]string{
-			"error": "Invalid category. Must be one of: web-development, mobile-development, data-science, design, business"})
+	// Validate category against the categories collection
+	if !validCategory(client, w, course.Category) {
 		return
 	}
 
@@ -1,13 +1,16 @@
 package main
 
 import (
+	"context"
 	"courses-api/config"
 	"courses-api/controllers"
 	"courses-api/middlewares"
+	"courses-api/models"
 	"courses-api/repositories/mongodb"
 	"courses-api/services"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -22,17 +25,136 @@ func main() {
 	// Initialize repositories
 	courseRepo := mongodb.NewCourseRepository(db)
 	enrollmentRepo := mongodb.NewEnrollmentRepository(db)
+	sectionRepo := mongodb.NewSectionRepository(db)
+	lessonRepo := mongodb.NewLessonRepository(db)
+	progressRepo := mongodb.NewLessonProgressRepository(db)
+	certificateRepo := mongodb.NewCertificateRepository(db)
+	quizRepo := mongodb.NewQuizRepository(db)
+	quizAttemptRepo := mongodb.NewQuizAttemptRepository(db)
+	reviewRepo := mongodb.NewReviewRepository(db)
+	threadRepo := mongodb.NewThreadRepository(db)
+	replyRepo := mongodb.NewReplyRepository(db)
+	calendarTokenRepo := mongodb.NewCalendarTokenRepository(db)
+	waitlistRepo := mongodb.NewWaitlistRepository(db)
+	cancellationRepo := mongodb.NewEnrollmentCancellationRepository(db)
+	seatHoldRepo := mongodb.NewSeatHoldRepository(db)
+	orderRepo := mongodb.NewOrderRepository(db)
+	couponRepo := mongodb.NewCouponRepository(db)
+	instructorRepo := mongodb.NewInstructorRepository(db)
+	categoryRepo := mongodb.NewCategoryRepository(db)
+	materialRepo := mongodb.NewMaterialRepository(db)
+	licenseRepo := mongodb.NewSeatLicenseRepository(db)
+	transactor := mongodb.NewTransactor(db)
+
+	if err := courseRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Error creating course indexes: %v", err)
+	}
+	if err := enrollmentRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Error creating enrollment indexes: %v", err)
+	}
+	if err := progressRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Error creating lesson progress indexes: %v", err)
+	}
+	if err := certificateRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Error creating certificate indexes: %v", err)
+	}
+	if err := quizAttemptRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Error creating quiz attempt indexes: %v", err)
+	}
+	if err := reviewRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Error creating review indexes: %v", err)
+	}
+	if err := waitlistRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Error creating waitlist indexes: %v", err)
+	}
+	if err := calendarTokenRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Error creating calendar token indexes: %v", err)
+	}
+	if err := seatHoldRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Error creating seat hold indexes: %v", err)
+	}
+	if err := couponRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Error creating coupon indexes: %v", err)
+	}
+	if err := categoryRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Error creating category indexes: %v", err)
+	}
+	if err := materialRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Error creating material indexes: %v", err)
+	}
+	if err := licenseRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Error creating seat license indexes: %v", err)
+	}
+
+	// Course validation checks categories against this cache
+	categoryCache := models.NewCategoryCache(categoryRepo.Slugs, time.Minute)
+	models.UseCategoryCache(categoryCache)
+
+	// Initialize clients for other services
+	usersClient := services.NewUsersClient()
 	
+	// Courses are charged through the fake provider until a real one is configured
+	paymentProvider := services.NewFakePaymentProvider()
+
+	// Uploaded files go to the local filesystem or an S3-compatible bucket
+	blobStore := services.NewBlobStore()
+	materialStore := services.NewMaterialStore()
+
 	// Initialize message queue
 	messageQueue := services.NewRabbitMQService()
 	
 	// Initialize services
-	courseService := services.NewCourseService(courseRepo, enrollmentRepo, messageQueue)
-	enrollmentService := services.NewEnrollmentService(enrollmentRepo, courseRepo, messageQueue)
-	
+	waitlistService := services.NewWaitlistService(waitlistRepo, courseRepo, enrollmentRepo, messageQueue)
+	courseService := services.NewCourseService(courseRepo, enrollmentRepo, instructorRepo, messageQueue, waitlistService)
+	prerequisiteService := services.NewPrerequisiteService(courseRepo, enrollmentRepo, lessonRepo)
+	enrollmentService := services.NewEnrollmentService(enrollmentRepo, courseRepo, waitlistRepo, cancellationRepo, licenseRepo, transactor, prerequisiteService, messageQueue, waitlistService)
+	seatHoldService := services.NewSeatHoldService(seatHoldRepo, courseRepo, enrollmentRepo, waitlistRepo, transactor, prerequisiteService, messageQueue, waitlistService)
+	orderService := services.NewOrderService(orderRepo, courseRepo, couponRepo, transactor, seatHoldService, paymentProvider)
+	couponService := services.NewCouponService(couponRepo)
+	curriculumService := services.NewCurriculumService(sectionRepo, lessonRepo, courseRepo, enrollmentRepo)
+	certificateService := services.NewCertificateService(certificateRepo, enrollmentRepo, courseRepo, usersClient)
+	progressService := services.NewProgressService(progressRepo, enrollmentRepo, sectionRepo, lessonRepo, certificateService)
+	quizService := services.NewQuizService(quizRepo, quizAttemptRepo, enrollmentRepo, courseRepo)
+	reviewService := services.NewReviewService(reviewRepo, courseRepo, enrollmentRepo, usersClient, messageQueue)
+	discussionService := services.NewDiscussionService(threadRepo, replyRepo, courseRepo, enrollmentRepo, usersClient)
+	calendarService := services.NewCalendarService(calendarTokenRepo, enrollmentRepo, courseRepo)
+	instructorService := services.NewInstructorService(instructorRepo, courseRepo, usersClient)
+	categoryService := services.NewCategoryService(categoryRepo, courseRepo, couponRepo, transactor, categoryCache, messageQueue)
+	courseImageService := services.NewCourseImageService(courseRepo, blobStore, messageQueue)
+	materialService := services.NewMaterialService(materialRepo, courseRepo, enrollmentRepo, materialStore, models.DefaultMaterialQuota)
+	licenseService := services.NewLicenseService(licenseRepo, courseRepo, enrollmentRepo, waitlistRepo, transactor, prerequisiteService, messageQueue, enrollmentService)
+	rosterService := services.NewRosterService(enrollmentRepo, courseRepo, usersClient)
+	courseCloneService := services.NewCourseCloneService(courseRepo, sectionRepo, lessonRepo, quizRepo, materialRepo, enrollmentRepo, transactor, messageQueue, models.DefaultMaterialQuota)
+
+	// Start background jobs
+	courseScheduler := services.NewCourseScheduler(courseService, time.Minute)
+	go courseScheduler.Run(context.Background())
+	waitlistSweeper := services.NewWaitlistSweeper(waitlistService, time.Minute)
+	go waitlistSweeper.Run(context.Background())
+	seatHoldSweeper := services.NewSeatHoldSweeper(seatHoldService, 30*time.Second)
+	go seatHoldSweeper.Run(context.Background())
+
 	// Initialize controllers
-	courseController := controllers.NewCourseController(courseService)
+	courseController := controllers.NewCourseController(courseService, progressService)
 	enrollmentController := controllers.NewEnrollmentController(enrollmentService)
+	curriculumController := controllers.NewCurriculumController(curriculumService)
+	progressController := controllers.NewProgressController(progressService)
+	certificateController := controllers.NewCertificateController(certificateService)
+	quizController := controllers.NewQuizController(quizService)
+	reviewController := controllers.NewReviewController(reviewService)
+	discussionController := controllers.NewDiscussionController(discussionService)
+	calendarController := controllers.NewCalendarController(calendarService)
+	waitlistController := controllers.NewWaitlistController(waitlistService)
+	seatHoldController := controllers.NewSeatHoldController(seatHoldService)
+	orderController := controllers.NewOrderController(orderService)
+	couponController := controllers.NewCouponController(couponService)
+	instructorController := controllers.NewInstructorController(instructorService)
+	categoryController := controllers.NewCategoryController(categoryService)
+	courseImageController := controllers.NewCourseImageController(courseImageService)
+	materialController := controllers.NewMaterialController(materialService)
+	courseCloneController := controllers.NewCourseCloneController(courseCloneService)
+	rosterController := controllers.NewRosterController(rosterService)
+	licenseController := controllers.NewLicenseController(licenseService)
 	serviceController := controllers.NewServiceController()
 
 	// Set up router
@@ -40,19 +162,156 @@ func main() {
 	r.Use(middlewares.CorsMiddleware)
 
 	// Register routes
-	r.HandleFunc("/courses", courseController.GetAllCourses).Methods("GET", "OPTIONS")
+	r.HandleFunc("/courses", middlewares.OptionalToken(courseController.GetAllCourses)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/courses/myCourses", middlewares.VerifyToken(courseController.GetUserCourses)).Methods("GET", "OPTIONS")
-	r.HandleFunc("/courses/{id}", courseController.GetCourse).Methods("GET", "OPTIONS")
+	r.HandleFunc("/courses/tags", courseController.SuggestTags).Methods("GET", "OPTIONS")
+	r.HandleFunc("/courses/export", middlewares.VerifyAdmin(courseController.ExportCourses)).Methods("GET")
+	r.HandleFunc("/courses/import", middlewares.VerifyAdmin(courseController.ImportCourses)).Methods("POST")
+	r.HandleFunc("/courses/{id}", middlewares.OptionalToken(courseController.GetCourse)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/courses/availability", courseController.CheckAvailability).Methods("POST", "OPTIONS")
 	
 	// Protected routes
-	r.HandleFunc("/courses", middlewares.VerifyAdmin(courseController.CreateCourse)).Methods("POST")
-	r.HandleFunc("/courses/{id}", middlewares.VerifyAdmin(courseController.UpdateCourse)).Methods("PUT")
-	r.HandleFunc("/courses/{id}", middlewares.VerifyAdmin(courseController.DeleteCourse)).Methods("DELETE")
+	// Instructors may manage the courses they teach; the service checks ownership
+	r.HandleFunc("/courses", middlewares.VerifyToken(courseController.CreateCourse)).Methods("POST")
+	r.HandleFunc("/courses/{id}", middlewares.VerifyToken(courseController.UpdateCourse)).Methods("PUT")
+	r.HandleFunc("/courses/{id}", middlewares.VerifyToken(courseController.DeleteCourse)).Methods("DELETE")
+	r.HandleFunc("/courses/{id}/status", middlewares.VerifyAdmin(courseController.ChangeCourseStatus)).Methods("POST")
+	r.HandleFunc("/courses/{id}/schedule", middlewares.VerifyAdmin(courseController.ScheduleCourse)).Methods("PUT")
+	r.HandleFunc("/courses/{id}/image", middlewares.VerifyToken(courseImageController.UploadImage)).Methods("POST")
+	r.HandleFunc("/courses/{id}/image", middlewares.VerifyToken(courseImageController.DeleteImage)).Methods("DELETE")
+	r.HandleFunc("/courses/{id}/clone", middlewares.VerifyToken(courseCloneController.CloneCourse)).Methods("POST")
+
+	// Files kept on the local filesystem are served by every replica;
+	// materials only through signed links
+	if localStore, ok := blobStore.(*services.LocalBlobStore); ok {
+		r.PathPrefix("/uploads/").Handler(http.StripPrefix("/uploads/", localStore.Handler())).Methods("GET", "HEAD")
+	}
+	if localStore, ok := materialStore.(*services.LocalBlobStore); ok {
+		r.PathPrefix("/files/").Handler(http.StripPrefix("/files/", localStore.Handler())).Methods("GET", "HEAD")
+	}
+
+	// Course material routes
+	r.HandleFunc("/courses/{id}/materials", middlewares.VerifyToken(materialController.ListMaterials)).Methods("GET")
+	r.HandleFunc("/courses/{id}/materials", middlewares.VerifyToken(materialController.UploadMaterial)).Methods("POST")
+	r.HandleFunc("/courses/{id}/materials/{materialId}", middlewares.VerifyToken(materialController.DeleteMaterial)).Methods("DELETE")
+	r.HandleFunc("/courses/{id}/materials/{materialId}/link", middlewares.VerifyToken(materialController.DownloadLink)).Methods("POST")
+
+	// Curriculum routes
+	r.HandleFunc("/courses/{id}/curriculum", middlewares.OptionalToken(curriculumController.GetCurriculum)).Methods("GET", "OPTIONS")
+	r.HandleFunc("/courses/{id}/lessons/{lessonId}", middlewares.OptionalToken(curriculumController.GetLesson)).Methods("GET", "OPTIONS")
+	r.HandleFunc("/courses/{id}/sections", middlewares.VerifyAdmin(curriculumController.CreateSection)).Methods("POST")
+	r.HandleFunc("/courses/{id}/sections/order", middlewares.VerifyAdmin(curriculumController.ReorderSections)).Methods("PUT")
+	r.HandleFunc("/courses/{id}/sections/{sectionId}", middlewares.VerifyAdmin(curriculumController.UpdateSection)).Methods("PUT")
+	r.HandleFunc("/courses/{id}/sections/{sectionId}", middlewares.VerifyAdmin(curriculumController.DeleteSection)).Methods("DELETE")
+	r.HandleFunc("/courses/{id}/sections/{sectionId}/lessons", middlewares.VerifyAdmin(curriculumController.CreateLesson)).Methods("POST")
+	r.HandleFunc("/courses/{id}/sections/{sectionId}/lessons/order", middlewares.VerifyAdmin(curriculumController.ReorderLessons)).Methods("PUT")
+	r.HandleFunc("/courses/{id}/lessons/{lessonId}", middlewares.VerifyAdmin(curriculumController.UpdateLesson)).Methods("PUT")
+	r.HandleFunc("/courses/{id}/lessons/{lessonId}", middlewares.VerifyAdmin(curriculumController.DeleteLesson)).Methods("DELETE")
+
+	// Progress routes
+	r.HandleFunc("/courses/{id}/lessons/{lessonId}/start", middlewares.VerifyToken(progressController.StartLesson)).Methods("POST")
+	r.HandleFunc("/courses/{id}/lessons/{lessonId}/complete", middlewares.VerifyToken(progressController.CompleteLesson)).Methods("POST")
+	r.HandleFunc("/courses/{id}/progress", middlewares.VerifyToken(progressController.GetProgress)).Methods("GET")
+	r.HandleFunc("/courses/{id}/progress/{userId}", middlewares.VerifyAdmin(progressController.GetStudentProgress)).Methods("GET")
+
+	// Certificate routes
+	r.HandleFunc("/courses/{id}/certificate", middlewares.VerifyToken(certificateController.IssueCertificate)).Methods("POST")
+	r.HandleFunc("/certificates", middlewares.VerifyToken(certificateController.GetUserCertificates)).Methods("GET")
+	r.HandleFunc("/certificates/{code}/verify", certificateController.VerifyCertificate).Methods("GET", "OPTIONS")
+	r.HandleFunc("/certificates/{code}/badge", certificateController.OpenBadge).Methods("GET", "OPTIONS")
+	r.HandleFunc("/certificates/{code}/pdf", middlewares.VerifyToken(certificateController.DownloadPDF)).Methods("GET")
+
+	// Quiz routes
+	r.HandleFunc("/courses/{id}/quizzes", middlewares.VerifyToken(quizController.ListQuizzes)).Methods("GET")
+	r.HandleFunc("/courses/{id}/quizzes", middlewares.VerifyAdmin(quizController.CreateQuiz)).Methods("POST")
+	r.HandleFunc("/courses/{id}/quizzes/{quizId}", middlewares.VerifyToken(quizController.GetQuiz)).Methods("GET")
+	r.HandleFunc("/courses/{id}/quizzes/{quizId}", middlewares.VerifyAdmin(quizController.UpdateQuiz)).Methods("PUT")
+	r.HandleFunc("/courses/{id}/quizzes/{quizId}", middlewares.VerifyAdmin(quizController.DeleteQuiz)).Methods("DELETE")
+	r.HandleFunc("/courses/{id}/quizzes/{quizId}/stats", middlewares.VerifyAdmin(quizController.GetStats)).Methods("GET")
+	r.HandleFunc("/courses/{id}/quizzes/{quizId}/attempts", middlewares.VerifyToken(quizController.StartAttempt)).Methods("POST")
+	r.HandleFunc("/courses/{id}/quizzes/{quizId}/attempts", middlewares.VerifyToken(quizController.GetAttempts)).Methods("GET")
+	r.HandleFunc("/courses/{id}/quizzes/{quizId}/attempts/{attemptId}/submit", middlewares.VerifyToken(quizController.SubmitAttempt)).Methods("POST")
+
+	// Review routes
+	r.HandleFunc("/courses/{id}/reviews", middlewares.OptionalToken(reviewController.GetReviews)).Methods("GET", "OPTIONS")
+	r.HandleFunc("/courses/{id}/reviews", middlewares.VerifyToken(reviewController.CreateReview)).Methods("POST")
+	r.HandleFunc("/courses/{id}/reviews", middlewares.VerifyToken(reviewController.UpdateReview)).Methods("PUT")
+	r.HandleFunc("/courses/{id}/reviews/{reviewId}/hidden", middlewares.VerifyAdmin(reviewController.SetHidden)).Methods("PUT")
+
+	// Discussion routes
+	r.HandleFunc("/courses/{id}/threads", middlewares.VerifyToken(discussionController.ListThreads)).Methods("GET")
+	r.HandleFunc("/courses/{id}/threads", middlewares.VerifyToken(discussionController.CreateThread)).Methods("POST")
+	r.HandleFunc("/courses/{id}/threads/{threadId}", middlewares.VerifyToken(discussionController.GetThread)).Methods("GET")
+	r.HandleFunc("/courses/{id}/threads/{threadId}", middlewares.VerifyToken(discussionController.UpdateThread)).Methods("PUT")
+	r.HandleFunc("/courses/{id}/threads/{threadId}", middlewares.VerifyToken(discussionController.DeleteThread)).Methods("DELETE")
+	r.HandleFunc("/courses/{id}/threads/{threadId}/upvote", middlewares.VerifyToken(discussionController.VoteThread)).Methods("POST", "DELETE")
+	r.HandleFunc("/courses/{id}/threads/{threadId}/pin", middlewares.VerifyToken(discussionController.PinThread)).Methods("PUT")
+	r.HandleFunc("/courses/{id}/threads/{threadId}/replies", middlewares.VerifyToken(discussionController.ListReplies)).Methods("GET")
+	r.HandleFunc("/courses/{id}/threads/{threadId}/replies", middlewares.VerifyToken(discussionController.CreateReply)).Methods("POST")
+	r.HandleFunc("/courses/{id}/threads/{threadId}/replies/{replyId}", middlewares.VerifyToken(discussionController.UpdateReply)).Methods("PUT")
+	r.HandleFunc("/courses/{id}/threads/{threadId}/replies/{replyId}", middlewares.VerifyToken(discussionController.DeleteReply)).Methods("DELETE")
+	r.HandleFunc("/courses/{id}/threads/{threadId}/replies/{replyId}/upvote", middlewares.VerifyToken(discussionController.VoteReply)).Methods("POST", "DELETE")
+	r.HandleFunc("/courses/{id}/threads/{threadId}/replies/{replyId}/answer", middlewares.VerifyToken(discussionController.MarkAnswer)).Methods("PUT")
 
 	// Enrollment routes
 	r.HandleFunc("/enrollments", middlewares.VerifyToken(enrollmentController.CreateEnrollment)).Methods("POST")
 	r.HandleFunc("/enrollments/check/{courseId}", middlewares.VerifyToken(enrollmentController.CheckEnrollment)).Methods("GET")
+	r.HandleFunc("/enrollments/{courseId}", middlewares.VerifyToken(enrollmentController.Unenroll)).Methods("DELETE")
+	r.HandleFunc("/courses/{id}/enrollments", middlewares.VerifyAdmin(enrollmentController.EnrollUser)).Methods("POST")
+	r.HandleFunc("/courses/{id}/enrollments/bulk", middlewares.VerifyAdmin(enrollmentController.BulkEnroll)).Methods("POST")
+	r.HandleFunc("/courses/{id}/enrollments/{userId}", middlewares.VerifyAdmin(enrollmentController.RemoveEnrollment)).Methods("DELETE")
+	r.HandleFunc("/courses/{id}/roster", middlewares.VerifyToken(rosterController.GetRoster)).Methods("GET")
+	r.HandleFunc("/courses/{id}/roster/export", middlewares.VerifyToken(rosterController.ExportRoster)).Methods("GET")
+
+	// Seat license routes
+	r.HandleFunc("/licenses", middlewares.VerifyAdmin(licenseController.CreateLicense)).Methods("POST")
+	r.HandleFunc("/licenses", middlewares.VerifyToken(licenseController.ListLicenses)).Methods("GET")
+	r.HandleFunc("/licenses/{id}", middlewares.VerifyToken(licenseController.GetLicense)).Methods("GET")
+	r.HandleFunc("/licenses/{id}/assignments", middlewares.VerifyToken(licenseController.AssignSeat)).Methods("POST")
+	r.HandleFunc("/licenses/{id}/assignments/{userId}", middlewares.VerifyToken(licenseController.RevokeSeat)).Methods("DELETE")
+
+	// Seat hold routes
+	r.HandleFunc("/courses/{id}/holds", middlewares.VerifyToken(seatHoldController.CreateHold)).Methods("POST")
+	r.HandleFunc("/holds/{holdId}/confirm", middlewares.VerifyToken(seatHoldController.ConfirmHold)).Methods("POST")
+	r.HandleFunc("/holds/{holdId}", middlewares.VerifyToken(seatHoldController.ReleaseHold)).Methods("DELETE")
+
+	// Order and payment routes
+	r.HandleFunc("/courses/{id}/quote", middlewares.VerifyToken(orderController.Quote)).Methods("GET")
+	r.HandleFunc("/courses/{id}/checkout", middlewares.VerifyToken(orderController.Checkout)).Methods("POST")
+	r.HandleFunc("/orders", middlewares.VerifyToken(orderController.GetUserOrders)).Methods("GET")
+	r.HandleFunc("/orders/{orderId}", middlewares.VerifyToken(orderController.GetOrder)).Methods("GET")
+	r.HandleFunc("/orders/{orderId}", middlewares.VerifyToken(orderController.CancelOrder)).Methods("DELETE")
+	r.HandleFunc("/payments/webhook", orderController.PaymentWebhook).Methods("POST")
+
+	// Coupon routes
+	r.HandleFunc("/coupons", middlewares.VerifyAdmin(couponController.CreateCoupon)).Methods("POST")
+	r.HandleFunc("/coupons", middlewares.VerifyAdmin(couponController.GetCoupons)).Methods("GET")
+	r.HandleFunc("/coupons/{couponId}", middlewares.VerifyAdmin(couponController.GetCoupon)).Methods("GET")
+	r.HandleFunc("/coupons/{couponId}", middlewares.VerifyAdmin(couponController.UpdateCoupon)).Methods("PUT")
+	r.HandleFunc("/coupons/{couponId}", middlewares.VerifyAdmin(couponController.DeleteCoupon)).Methods("DELETE")
+
+	// Category routes
+	r.HandleFunc("/categories", categoryController.GetCategories).Methods("GET", "OPTIONS")
+	r.HandleFunc("/categories/{categoryId}", categoryController.GetCategory).Methods("GET", "OPTIONS")
+	r.HandleFunc("/categories", middlewares.VerifyAdmin(categoryController.CreateCategory)).Methods("POST")
+	r.HandleFunc("/categories/{categoryId}", middlewares.VerifyAdmin(categoryController.UpdateCategory)).Methods("PUT")
+	r.HandleFunc("/categories/{categoryId}", middlewares.VerifyAdmin(categoryController.DeleteCategory)).Methods("DELETE")
+
+	// Instructor routes
+	r.HandleFunc("/instructors/{userId}", middlewares.OptionalToken(instructorController.GetInstructor)).Methods("GET", "OPTIONS")
+	r.HandleFunc("/instructors/{userId}", middlewares.VerifyToken(instructorController.SaveProfile)).Methods("PUT")
+	r.HandleFunc("/instructors/{userId}", middlewares.VerifyAdmin(instructorController.DeleteProfile)).Methods("DELETE")
+
+	// Waitlist routes
+	r.HandleFunc("/courses/{id}/waitlist", middlewares.VerifyToken(waitlistController.JoinWaitlist)).Methods("POST")
+	r.HandleFunc("/courses/{id}/waitlist", middlewares.VerifyToken(waitlistController.LeaveWaitlist)).Methods("DELETE")
+	r.HandleFunc("/courses/{id}/waitlist", middlewares.VerifyAdmin(waitlistController.GetWaitlist)).Methods("GET")
+	r.HandleFunc("/courses/{id}/waitlist/position", middlewares.VerifyToken(waitlistController.GetPosition)).Methods("GET")
+
+	// Calendar routes
+	r.HandleFunc("/calendar", middlewares.VerifyToken(calendarController.GetFeed)).Methods("GET")
+	r.HandleFunc("/calendar/token", middlewares.VerifyToken(calendarController.RotateFeed)).Methods("POST")
+	r.HandleFunc("/calendar/{token}", calendarController.ServeFeed).Methods("GET", "OPTIONS")
 
 	// Service routes
 	r.HandleFunc("/api/services", middlewares.VerifyAdmin(serviceController.GetServices)).Methods("GET")
@@ -61,20 +61,10 @@ func VerifyAdmin(next http.HandlerFunc) http.HandlerFunc {
 
 func VerifyToken(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var tokenString string
-
-		// First check Authorization header
-		authHeader := r.Header.Get("Authorization")
-		if strings.HasPrefix(authHeader, "Bearer ") {
-			tokenString = strings.TrimPrefix(authHeader, "Bearer ")
-		} else {
-			// If no Authorization header, check cookie
-			cookie, err := r.Cookie("token")
-			if err != nil {
-				http.Error(w, models.ErrUnauthorized.Error(), http.StatusUnauthorized)
-				return
-			}
-			tokenString = cookie.Value
+		tokenString, ok := tokenFromRequest(r)
+		if !ok {
+			http.Error(w, models.ErrUnauthorized.Error(), http.StatusUnauthorized)
+			return
 		}
 
 		claims := &Claims{}
@@ -89,6 +79,47 @@ func VerifyToken(next http.HandlerFunc) http.HandlerFunc {
 
 		// Add claims to context
 		ctx := context.WithValue(r.Context(), "userID", claims.UserID)
+		ctx = context.WithValue(ctx, "admin", claims.Admin)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+}
+
+// OptionalToken adds the caller's claims to the context when a valid token is
+// present, and lets anonymous requests through unchanged.
+func OptionalToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tokenString, ok := tokenFromRequest(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		claims := &Claims{}
+		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+			return jwtSecret, nil
+		})
+		if err != nil || !token.Valid {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), "userID", claims.UserID)
+		ctx = context.WithValue(ctx, "admin", claims.Admin)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	}
 }
+
+func tokenFromRequest(r *http.Request) (string, bool) {
+	// First check Authorization header
+	authHeader := r.Header.Get("Authorization")
+	if strings.HasPrefix(authHeader, "Bearer ") {
+		return strings.TrimPrefix(authHeader, "Bearer "), true
+	}
+
+	// If no Authorization header, check cookie
+	cookie, err := r.Cookie("token")
+	if err != nil {
+		return "", false
+	}
+	return cookie.Value, true
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CalendarToken grants unauthenticated read access to a user's .ics feed so
+// that calendar clients can subscribe without a JWT.
+type CalendarToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	UserID    int                `bson:"user_id" json:"user_id"`
+	Token     string             `bson:"token" json:"token"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Category groups courses. Courses refer to categories by slug; ParentID
+// links a subcategory to its parent.
+type Category struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	Slug        string              `bson:"slug" json:"slug"`
+	DisplayName string              `bson:"display_name" json:"display_name"`
+	Icon        string              `bson:"icon,omitempty" json:"icon,omitempty"`
+	ParentID    *primitive.ObjectID `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// DefaultCategories seed an empty categories collection with the catalog
+// that existed before categories could be managed.
+var DefaultCategories = []Category{
+	{Slug: "web-development", DisplayName: "Web Development", Icon: "globe"},
+	{Slug: "mobile-development", DisplayName: "Mobile Development", Icon: "smartphone"},
+	{Slug: "data-science", DisplayName: "Data Science", Icon: "bar-chart"},
+	{Slug: "design", DisplayName: "Design", Icon: "palette"},
+	{Slug: "business", DisplayName: "Business", Icon: "briefcase"},
+}
+
+var categorySlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+func (c *Category) Validate() error {
+	c.Slug = strings.ToLower(strings.TrimSpace(c.Slug))
+	c.DisplayName = strings.TrimSpace(c.DisplayName)
+	if len(c.Slug) > 50 || !categorySlugPattern.MatchString(c.Slug) ||
+		c.DisplayName == "" || len(c.DisplayName) > 100 || len(c.Icon) > 200 {
+		return ErrInvalidCategoryData
+	}
+	if c.ParentID != nil && *c.ParentID == c.ID {
+		return ErrCategoryCycle
+	}
+	return nil
+}
+
+// CategoryCache keeps the category slugs in memory so Course.Validate does
+// not query the database every time. Changes made on this replica invalidate
+// it immediately; other replicas pick them up once the TTL expires.
+type CategoryCache struct {
+	load    func(ctx context.Context) ([]string, error)
+	ttl     time.Duration
+	mu      sync.Mutex
+	slugs   map[string]bool
+	expires time.Time
+}
+
+func NewCategoryCache(load func(ctx context.Context) ([]string, error), ttl time.Duration) *CategoryCache {
+	return &CategoryCache{load: load, ttl: ttl}
+}
+
+// Contains reports whether slug is a known category. If reloading fails the
+// previous slugs are used until the next attempt.
+func (c *CategoryCache) Contains(slug string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.slugs == nil || time.Now().After(c.expires) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		slugs, err := c.load(ctx)
+		cancel()
+		if err != nil {
+			if c.slugs == nil {
+				return false, err
+			}
+		} else {
+			c.slugs = make(map[string]bool, len(slugs))
+			for _, s := range slugs {
+				c.slugs[s] = true
+			}
+		}
+		c.expires = time.Now().Add(c.ttl)
+	}
+	return c.slugs[slug], nil
+}
+
+// Invalidate forces the next lookup to reload the slugs.
+func (c *CategoryCache) Invalidate() {
+	c.mu.Lock()
+	c.expires = time.Time{}
+	c.mu.Unlock()
+}
+
+var categoryCache *CategoryCache
+
+// UseCategoryCache makes Course.Validate check categories against the cache
+// instead of DefaultCategories. It is meant to be called once at startup.
+func UseCategoryCache(cache *CategoryCache) {
+	categoryCache = cache
+}
+
+// IsValidCategory reports whether slug names an existing category.
+func IsValidCategory(slug string) (bool, error) {
+	if categoryCache != nil {
+		return categoryCache.Contains(slug)
+	}
+	for _, category := range DefaultCategories {
+		if category.Slug == slug {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCategoryValidate(t *testing.T) {
+	category := Category{Slug: " Machine-Learning ", DisplayName: "Machine Learning"}
+	assert.NoError(t, category.Validate())
+	assert.Equal(t, "machine-learning", category.Slug)
+
+	assert.Equal(t, ErrInvalidCategoryData, (&Category{Slug: "two words", DisplayName: "X"}).Validate())
+	assert.Equal(t, ErrInvalidCategoryData, (&Category{Slug: "design"}).Validate())
+
+	id := primitive.NewObjectID()
+	assert.Equal(t, ErrCategoryCycle, (&Category{ID: id, Slug: "design", DisplayName: "Design", ParentID: &id}).Validate())
+}
+
+func TestCategoryCacheReloadsAfterInvalidate(t *testing.T) {
+	slugs := []string{"design"}
+	loads := 0
+	cache := NewCategoryCache(func(ctx context.Context) ([]string, error) {
+		loads++
+		return slugs, nil
+	}, time.Hour)
+
+	ok, err := cache.Contains("design")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	slugs = []string{"product-design"}
+	ok, _ = cache.Contains("product-design")
+	assert.False(t, ok, "cached slugs are used until the TTL expires")
+	assert.Equal(t, 1, loads)
+
+	cache.Invalidate()
+	ok, _ = cache.Contains("product-design")
+	assert.True(t, ok)
+	assert.Equal(t, 2, loads)
+}
+
+func TestCategoryCacheKeepsStaleSlugsOnError(t *testing.T) {
+	fail := false
+	cache := NewCategoryCache(func(ctx context.Context) ([]string, error) {
+		if fail {
+			return nil, errors.New("database down")
+		}
+		return []string{"design"}, nil
+	}, time.Hour)
+
+	_, err := cache.Contains("design")
+	assert.NoError(t, err)
+
+	fail = true
+	cache.Invalidate()
+	ok, err := cache.Contains("design")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Certificate struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Code         string             `bson:"code" json:"code"`
+	EnrollmentID primitive.ObjectID `bson:"enrollment_id" json:"enrollment_id"`
+	CourseID     primitive.ObjectID `bson:"course_id" json:"course_id"`
+	UserID       int                `bson:"user_id" json:"user_id"`
+	StudentName  string             `bson:"student_name" json:"student_name"`
+	StudentEmail string             `bson:"student_email" json:"-"`
+	CourseTitle  string             `bson:"course_title" json:"course_title"`
+	Instructor   string             `bson:"instructor" json:"instructor"`
+	IssuedAt     time.Time          `bson:"issued_at" json:"issued_at"`
+}
+
+// CertificateVerification is the public view of a certificate
+type CertificateVerification struct {
+	Valid       bool      `json:"valid"`
+	Code        string    `json:"code"`
+	StudentName string    `json:"student_name"`
+	CourseTitle string    `json:"course_title"`
+	Instructor  string    `json:"instructor"`
+	IssuedAt    time.Time `json:"issued_at"`
+}
+
+func (c *Certificate) Verification() CertificateVerification {
+	return CertificateVerification{
+		Valid:       true,
+		Code:        c.Code,
+		StudentName: c.StudentName,
+		CourseTitle: c.CourseTitle,
+		Instructor:  c.Instructor,
+		IssuedAt:    c.IssuedAt,
+	}
+}
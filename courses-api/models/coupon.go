@@ -0,0 +1,112 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Coupon discount types
+const (
+	CouponPercentage = "percentage"
+	CouponFixed      = "fixed"
+)
+
+// Coupon is a promotional discount code. MaxRedemptions and PerUserLimit are
+// unlimited when zero. When CourseIDs or Categories are set, the coupon only
+// applies to courses matching at least one of them.
+type Coupon struct {
+	ID             primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	Code           string               `bson:"code" json:"code"`
+	Type           string               `bson:"type" json:"type"`
+	Value          int64                `bson:"value" json:"value"` // percent, or minor units for fixed
+	Currency       string               `bson:"currency,omitempty" json:"currency,omitempty"`
+	ExpiresAt      *time.Time           `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	MaxRedemptions int                  `bson:"max_redemptions" json:"max_redemptions"`
+	PerUserLimit   int                  `bson:"per_user_limit" json:"per_user_limit"`
+	CourseIDs      []primitive.ObjectID `bson:"course_ids,omitempty" json:"course_ids,omitempty"`
+	Categories     []string             `bson:"categories,omitempty" json:"categories,omitempty"`
+	Active         bool                 `bson:"active" json:"active"`
+	Redemptions    int                  `bson:"redemptions" json:"redemptions"`
+	CreatedAt      time.Time            `bson:"created_at" json:"created_at"`
+}
+
+// Quote is the price a user would pay for a course, with an optional coupon.
+type Quote struct {
+	CourseID   primitive.ObjectID `json:"course_id"`
+	Currency   string             `json:"currency,omitempty"`
+	ListPrice  int64              `json:"list_price"`
+	Discount   int64              `json:"discount"`
+	Total      int64              `json:"total"`
+	CouponCode string             `json:"coupon_code,omitempty"`
+}
+
+// NormalizeCouponCode makes codes case-insensitive.
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (c *Coupon) Validate() error {
+	c.Code = NormalizeCouponCode(c.Code)
+	if c.Code == "" || len(c.Code) > 40 || c.MaxRedemptions < 0 || c.PerUserLimit < 0 {
+		return ErrInvalidCoupon
+	}
+
+	switch c.Type {
+	case CouponPercentage:
+		if c.Value < 1 || c.Value > 100 {
+			return ErrInvalidCoupon
+		}
+	case CouponFixed:
+		if c.Value <= 0 || !isCurrencyCode(c.Currency) {
+			return ErrInvalidCoupon
+		}
+	default:
+		return ErrInvalidCoupon
+	}
+	return nil
+}
+
+// IsExpired reports whether the coupon can no longer be used at the given time.
+func (c *Coupon) IsExpired(now time.Time) bool {
+	return c.ExpiresAt != nil && !now.Before(*c.ExpiresAt)
+}
+
+// AppliesTo reports whether the coupon's course and category restrictions
+// allow it on the given course.
+func (c *Coupon) AppliesTo(course *Course) bool {
+	if len(c.CourseIDs) == 0 && len(c.Categories) == 0 {
+		return true
+	}
+	for _, id := range c.CourseIDs {
+		if id == course.ID {
+			return true
+		}
+	}
+	for _, category := range c.Categories {
+		if category == course.Category {
+			return true
+		}
+	}
+	return false
+}
+
+// Discount returns the amount taken off the price, never more than the price.
+// Fixed discounts only apply to prices in the coupon's currency.
+func (c *Coupon) Discount(price int64, currency string) (int64, error) {
+	var discount int64
+	switch c.Type {
+	case CouponPercentage:
+		discount = price * c.Value / 100
+	case CouponFixed:
+		if c.Currency != currency {
+			return 0, ErrCouponNotApplicable
+		}
+		discount = c.Value
+	}
+	if discount > price {
+		discount = price
+	}
+	return discount, nil
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCouponDiscount(t *testing.T) {
+	percent := Coupon{Type: CouponPercentage, Value: 25}
+	discount, err := percent.Discount(4999, "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1249), discount)
+
+	fixed := Coupon{Type: CouponFixed, Value: 10000, Currency: "USD"}
+	discount, err = fixed.Discount(4999, "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4999), discount, "discount is capped at the price")
+
+	_, err = fixed.Discount(4999, "EUR")
+	assert.Equal(t, ErrCouponNotApplicable, err)
+}
+
+func TestCouponAppliesTo(t *testing.T) {
+	course := Course{ID: primitive.NewObjectID(), Category: "programming"}
+
+	assert.True(t, (&Coupon{}).AppliesTo(&course))
+	assert.True(t, (&Coupon{CourseIDs: []primitive.ObjectID{course.ID}}).AppliesTo(&course))
+	assert.True(t, (&Coupon{Categories: []string{"design", "programming"}}).AppliesTo(&course))
+	assert.False(t, (&Coupon{CourseIDs: []primitive.ObjectID{primitive.NewObjectID()}}).AppliesTo(&course))
+}
+
+func TestCouponValidate(t *testing.T) {
+	coupon := Coupon{Code: " spring24 ", Type: CouponPercentage, Value: 20}
+	assert.NoError(t, coupon.Validate())
+	assert.Equal(t, "SPRING24", coupon.Code)
+
+	assert.Equal(t, ErrInvalidCoupon, (&Coupon{Code: "X", Type: CouponPercentage, Value: 150}).Validate())
+	assert.Equal(t, ErrInvalidCoupon, (&Coupon{Code: "X", Type: CouponFixed, Value: 500}).Validate())
+	assert.Equal(t, ErrInvalidCoupon, (&Coupon{Code: "X", Type: "bogus", Value: 5}).Validate())
+}
+
+func TestCouponIsExpired(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	assert.True(t, (&Coupon{ExpiresAt: &past}).IsExpired(now))
+	assert.False(t, (&Coupon{}).IsExpired(now))
+}
@@ -2,40 +2,164 @@ package models
 
 import (
 	"errors"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Course lifecycle states
+const (
+    CourseStatusDraft     = "draft"
+    CourseStatusPublished = "published"
+    CourseStatusArchived  = "archived"
+)
+
+// courseTransitions lists the states a course may move to from each state
+var courseTransitions = map[string][]string{
+    CourseStatusDraft:     {CourseStatusPublished, CourseStatusArchived},
+    CourseStatusPublished: {CourseStatusDraft, CourseStatusArchived},
+    CourseStatusArchived:  {CourseStatusDraft, CourseStatusPublished},
+}
+
 type Course struct {
     ID             primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+    ExternalKey    string            `bson:"external_key,omitempty" json:"external_key,omitempty"` // stable ID from the catalog it was imported from
     Title          string            `bson:"title" json:"title"`
     Description    string            `bson:"description" json:"description"`
+    DefaultLocale  string            `bson:"default_locale,omitempty" json:"default_locale,omitempty"` // language of Title and Description
+    Translations   map[string]CourseTranslation `bson:"translations,omitempty" json:"translations,omitempty"`
+    Locale         string            `bson:"-" json:"locale,omitempty"` // locale served, set by Localize
     Instructor     string            `bson:"instructor" json:"instructor"`
+    InstructorIDs  []int             `bson:"instructor_ids,omitempty" json:"instructor_ids,omitempty"`
     Duration       int               `bson:"duration" json:"duration"`
     AvailableSeats int              `bson:"available_seats" json:"available_seats"`
+    HeldSeats      int               `bson:"held_seats" json:"held_seats"`
+    OfferedSeats   int               `bson:"offered_seats,omitempty" json:"offered_seats,omitempty"` // open seats offered to waitlisted users
+    Price          int64             `bson:"price" json:"price"` // minor units, e.g. cents
+    Currency       string            `bson:"currency,omitempty" json:"currency,omitempty"`
     Category       string            `bson:"category" json:"category"`
+    Tags           []string          `bson:"tags,omitempty" json:"tags,omitempty"`
     ImageURL       string            `bson:"image_url" json:"image_url"`
+    ThumbnailURL   string            `bson:"thumbnail_url,omitempty" json:"thumbnail_url,omitempty"`
+    ImageKeys      []string          `bson:"image_keys,omitempty" json:"-"` // uploaded blobs, removed on replacement
+    Status         string            `bson:"status,omitempty" json:"status,omitempty"`
+    PublishAt      *time.Time        `bson:"publish_at,omitempty" json:"publish_at,omitempty"`
+    UnpublishAt    *time.Time        `bson:"unpublish_at,omitempty" json:"unpublish_at,omitempty"`
+    AverageRating  float64           `bson:"average_rating" json:"average_rating"`
+    ReviewCount    int               `bson:"review_count" json:"review_count"`
+    StartDate      *time.Time        `bson:"start_date,omitempty" json:"start_date,omitempty"`
+    EndDate        *time.Time        `bson:"end_date,omitempty" json:"end_date,omitempty"`
+    TimeZone       string            `bson:"time_zone,omitempty" json:"time_zone,omitempty"`
+    Sessions       []Session         `bson:"sessions,omitempty" json:"sessions,omitempty"`
+    EnrollmentOpensAt  *time.Time    `bson:"enrollment_opens_at,omitempty" json:"enrollment_opens_at,omitempty"`
+    EnrollmentClosesAt *time.Time    `bson:"enrollment_closes_at,omitempty" json:"enrollment_closes_at,omitempty"`
+    Prerequisites  []primitive.ObjectID `bson:"prerequisites,omitempty" json:"prerequisites,omitempty"`
+    Cohort         string            `bson:"cohort,omitempty" json:"cohort,omitempty"`
+    ClonedFrom     *primitive.ObjectID `bson:"cloned_from,omitempty" json:"cloned_from,omitempty"`
 }
 
-var ValidCategories = []string{"web-development", "mobile-development", "data-science", "design", "business"}
-
 func (c *Course) Validate() error {
     if c.Title == "" || c.Description == "" || c.Instructor == "" || 
        c.Duration <= 0 || c.AvailableSeats <= 0 || c.Category == "" {
         return errors.New("all fields are required and numeric values must be greater than 0")
     }
 
-    isValidCategory := false
-    for _, validCat := range ValidCategories {
-        if c.Category == validCat {
-            isValidCategory = true
-            break
+    isValidCategory, err := IsValidCategory(c.Category)
+    if err != nil {
+        return err
+    }
+    if !isValidCategory {
+        return ErrInvalidCategory
+    }
+
+    if c.Price < 0 || (c.Price > 0 && !isCurrencyCode(c.Currency)) {
+        return ErrInvalidPrice
+    }
+
+    if c.Status != "" && !IsValidCourseStatus(c.Status) {
+        return ErrInvalidCourseStatus
+    }
+
+    if c.PublishAt != nil && c.UnpublishAt != nil && !c.UnpublishAt.After(*c.PublishAt) {
+        return ErrInvalidSchedule
+    }
+
+    if err := c.normalizeTags(); err != nil {
+        return err
+    }
+
+    if err := c.validateTranslations(); err != nil {
+        return err
+    }
+
+    if err := c.validatePrerequisites(); err != nil {
+        return err
+    }
+
+    return c.validateCalendar()
+}
+
+// IsPublished reports whether the course is publicly visible. Courses stored
+// before lifecycle states existed have no status and are treated as published.
+func (c *Course) IsPublished() bool {
+    return c.Status == "" || c.Status == CourseStatusPublished
+}
+
+// IsFree reports whether the course can be enrolled in without paying.
+func (c *Course) IsFree() bool {
+    return c.Price == 0
+}
+
+// isCurrencyCode checks for an upper-case ISO 4217 style code such as "USD"
+func isCurrencyCode(code string) bool {
+    if len(code) != 3 {
+        return false
+    }
+    for _, r := range code {
+        if r < 'A' || r > 'Z' {
+            return false
         }
     }
+    return true
+}
 
-    if !isValidCategory {
-        return errors.New("invalid category")
+// IsTaughtBy reports whether the user is one of the course's instructors.
+func (c *Course) IsTaughtBy(userID int) bool {
+    for _, id := range c.InstructorIDs {
+        if id == userID {
+            return true
+        }
     }
+    return false
+}
+
+// OpenSeats returns the seats that are neither taken nor held for checkout.
+func (c *Course) OpenSeats() int {
+    if c.HeldSeats >= c.AvailableSeats {
+        return 0
+    }
+    return c.AvailableSeats - c.HeldSeats
+}
+
+// CurrentStatus returns the course status, defaulting legacy courses to published.
+func (c *Course) CurrentStatus() string {
+    if c.Status == "" {
+        return CourseStatusPublished
+    }
+    return c.Status
+}
+
+// CanTransitionTo reports whether the course may move to the given status.
+func (c *Course) CanTransitionTo(status string) bool {
+    for _, next := range courseTransitions[c.CurrentStatus()] {
+        if next == status {
+            return true
+        }
+    }
+    return false
+}
 
-    return nil
+func IsValidCourseStatus(status string) bool {
+    _, ok := courseTransitions[status]
+    return ok
 }
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxCohortLength = 100
+
+// CloneRequest describes a new run of an existing course. Dates move by
+// ShiftDays, or so that the clone starts on StartDate; Seats defaults to
+// the original capacity.
+type CloneRequest struct {
+	Title     string     `json:"title"`
+	Cohort    string     `json:"cohort"`
+	StartDate *time.Time `json:"start_date"`
+	ShiftDays int        `json:"shift_days"`
+	Seats     int        `json:"seats"`
+}
+
+// Apply builds the clone of original with the given capacity. The clone is
+// a draft with no ratings, holds or import key, and its publishing
+// schedule is cleared so it never goes live on the original's dates.
+func (r *CloneRequest) Apply(original *Course, seats int) (*Course, error) {
+	r.Title = strings.TrimSpace(r.Title)
+	r.Cohort = strings.TrimSpace(r.Cohort)
+	if (r.Title == "" && r.Cohort == "") || utf8.RuneCountInString(r.Cohort) > maxCohortLength ||
+		seats < 0 || (r.StartDate != nil && (r.ShiftDays != 0 || original.StartDate == nil)) {
+		return nil, ErrInvalidClone
+	}
+
+	clone := *original
+	clone.ID = primitive.NilObjectID
+	clone.ExternalKey = ""
+	clone.Title = r.cloneTitle(original)
+	clone.Cohort = r.Cohort
+	clone.AvailableSeats = seats
+	clone.HeldSeats = 0
+	clone.OfferedSeats = 0
+	clone.AverageRating = 0
+	clone.ReviewCount = 0
+	clone.Status = CourseStatusDraft
+	clone.PublishAt = nil
+	clone.UnpublishAt = nil
+	sourceID := original.ID
+	clone.ClonedFrom = &sourceID
+
+	// Copy slices so the clone never aliases the original
+	clone.InstructorIDs = append([]int(nil), original.InstructorIDs...)
+	clone.Tags = append([]string(nil), original.Tags...)
+	clone.Sessions = append([]Session(nil), original.Sessions...)
+	clone.Prerequisites = append([]primitive.ObjectID(nil), original.Prerequisites...)
+	clone.ImageKeys = append([]string(nil), original.ImageKeys...)
+	if original.Translations != nil {
+		clone.Translations = make(map[string]CourseTranslation, len(original.Translations))
+		for locale, translation := range original.Translations {
+			clone.Translations[locale] = translation
+		}
+	}
+
+	shift := func(t *time.Time) *time.Time {
+		if t == nil {
+			return nil
+		}
+		var shifted time.Time
+		if r.StartDate != nil {
+			shifted = t.Add(r.StartDate.Sub(*original.StartDate))
+		} else {
+			shifted = t.AddDate(0, 0, r.ShiftDays)
+		}
+		return &shifted
+	}
+	clone.StartDate = shift(original.StartDate)
+	clone.EndDate = shift(original.EndDate)
+	clone.EnrollmentOpensAt = shift(original.EnrollmentOpensAt)
+	clone.EnrollmentClosesAt = shift(original.EnrollmentClosesAt)
+	return &clone, nil
+}
+
+// cloneTitle returns the requested title, or the original's with its
+// cohort label replaced by the new one.
+func (r *CloneRequest) cloneTitle(original *Course) string {
+	if r.Title != "" {
+		return r.Title
+	}
+	base := original.Title
+	if original.Cohort != "" {
+		base = strings.TrimSuffix(base, " ("+original.Cohort+")")
+	}
+	return base + " (" + r.Cohort + ")"
+}
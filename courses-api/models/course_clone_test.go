@@ -0,0 +1,89 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func cloneSource() *Course {
+	start := time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 2, 0)
+	publish := start.AddDate(0, -1, 0)
+	return &Course{
+		ID:             primitive.NewObjectID(),
+		Title:          "Go Basics (Spring)",
+		Cohort:         "Spring",
+		ExternalKey:    "go-basics",
+		InstructorIDs:  []int{7},
+		Tags:           []string{"go"},
+		StartDate:      &start,
+		EndDate:        &end,
+		PublishAt:      &publish,
+		AvailableSeats: 3,
+		HeldSeats:      2,
+		AverageRating:  4.5,
+		ReviewCount:    10,
+		Status:         CourseStatusPublished,
+	}
+}
+
+func TestCloneRequestApplyResetsRunState(t *testing.T) {
+	original := cloneSource()
+	req := CloneRequest{Cohort: " Fall ", ShiftDays: 7}
+
+	clone, err := req.Apply(original, 30)
+	require.NoError(t, err)
+
+	assert.True(t, clone.ID.IsZero())
+	assert.Equal(t, "Go Basics (Fall)", clone.Title)
+	assert.Equal(t, "Fall", clone.Cohort)
+	assert.Equal(t, original.ID, *clone.ClonedFrom)
+	assert.Empty(t, clone.ExternalKey)
+	assert.Equal(t, 30, clone.AvailableSeats)
+	assert.Zero(t, clone.HeldSeats)
+	assert.Zero(t, clone.ReviewCount)
+	assert.Equal(t, CourseStatusDraft, clone.Status)
+	assert.Nil(t, clone.PublishAt)
+	assert.Equal(t, original.StartDate.AddDate(0, 0, 7), *clone.StartDate)
+	assert.Equal(t, original.EndDate.AddDate(0, 0, 7), *clone.EndDate)
+
+	clone.Tags[0] = "changed"
+	assert.Equal(t, "go", original.Tags[0])
+}
+
+func TestCloneRequestApplyMovesDatesToStartDate(t *testing.T) {
+	original := cloneSource()
+	start := original.StartDate.AddDate(0, 6, 0)
+	req := CloneRequest{Title: "Go Basics 2", StartDate: &start}
+
+	clone, err := req.Apply(original, 5)
+	require.NoError(t, err)
+	assert.Equal(t, "Go Basics 2", clone.Title)
+	assert.Equal(t, start, *clone.StartDate)
+	assert.Equal(t, original.EndDate.Sub(*original.StartDate), clone.EndDate.Sub(*clone.StartDate))
+}
+
+func TestCloneRequestApplyRejectsInvalidRequests(t *testing.T) {
+	original := cloneSource()
+	start := time.Now()
+	for _, req := range []CloneRequest{
+		{},
+		{Title: "  "},
+		{Cohort: "Fall", StartDate: &start, ShiftDays: 3},
+	} {
+		_, err := req.Apply(original, 1)
+		assert.Equal(t, ErrInvalidClone, err)
+	}
+
+	_, err := (&CloneRequest{Cohort: "Fall"}).Apply(original, -1)
+	assert.Equal(t, ErrInvalidClone, err)
+
+	undated := cloneSource()
+	undated.StartDate = nil
+	_, err = (&CloneRequest{Cohort: "Fall", StartDate: &start}).Apply(undated, 1)
+	assert.Equal(t, ErrInvalidClone, err)
+}
@@ -0,0 +1,25 @@
+package models
+
+// Limits for uploaded course images
+const (
+	MaxCourseImageBytes  = 5 << 20
+	MaxCourseImageSide   = 8000
+	CourseThumbnailWidth = 400
+)
+
+// allowedImageTypes are the sniffed content types accepted for course images
+var allowedImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+func IsAllowedImageType(contentType string) bool {
+	_, ok := allowedImageTypes[contentType]
+	return ok
+}
+
+// ImageExtension returns the file extension stored images of the type get.
+func ImageExtension(contentType string) string {
+	return allowedImageTypes[contentType]
+}
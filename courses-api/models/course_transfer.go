@@ -0,0 +1,47 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Formats for bulk course import and export
+const (
+	CourseFormatCSV    = "csv"
+	CourseFormatJSON   = "json"
+	CourseFormatNDJSON = "ndjson"
+)
+
+// Limits for a single import request
+const (
+	MaxImportBytes = 50 << 20
+	MaxImportRows  = 5000
+)
+
+// Outcomes of an imported row
+const (
+	ImportCreate = "create"
+	ImportUpdate = "update"
+	ImportError  = "error"
+)
+
+func IsCourseFormat(format string) bool {
+	return format == CourseFormatCSV || format == CourseFormatJSON || format == CourseFormatNDJSON
+}
+
+// ImportRow reports what happened to one row of an import. Rows are
+// numbered from 1, not counting the CSV header.
+type ImportRow struct {
+	Row         int                 `json:"row"`
+	ExternalKey string              `json:"external_key,omitempty"`
+	Action      string              `json:"action"`
+	CourseID    *primitive.ObjectID `json:"course_id,omitempty"`
+	Error       string              `json:"error,omitempty"`
+}
+
+// ImportResult summarizes an import. In a dry run nothing is written and
+// the actions say what would have happened.
+type ImportResult struct {
+	DryRun  bool        `json:"dry_run"`
+	Created int         `json:"created"`
+	Updated int         `json:"updated"`
+	Failed  int         `json:"failed"`
+	Rows    []ImportRow `json:"rows"`
+}
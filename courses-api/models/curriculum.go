@@ -0,0 +1,68 @@
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Lesson types
+const (
+	LessonTypeText  = "text"
+	LessonTypeVideo = "video"
+	LessonTypeFile  = "file"
+)
+
+type Section struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	CourseID primitive.ObjectID `bson:"course_id" json:"course_id"`
+	Title    string             `bson:"title" json:"title"`
+	Position int                `bson:"position" json:"position"`
+	Lessons  []Lesson           `bson:"-" json:"lessons,omitempty"`
+}
+
+type Lesson struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	CourseID    primitive.ObjectID `bson:"course_id" json:"course_id"`
+	SectionID   primitive.ObjectID `bson:"section_id" json:"section_id"`
+	Title       string             `bson:"title" json:"title"`
+	Type        string             `bson:"type" json:"type"`
+	Content     string             `bson:"content,omitempty" json:"content,omitempty"`
+	URL         string             `bson:"url,omitempty" json:"url,omitempty"`
+	Duration    int                `bson:"duration" json:"duration"`
+	FreePreview bool               `bson:"free_preview" json:"free_preview"`
+	Optional    bool               `bson:"optional" json:"optional"`
+	Position    int                `bson:"position" json:"position"`
+}
+
+func (s *Section) Validate() error {
+	if s.Title == "" {
+		return ErrInvalidSection
+	}
+	return nil
+}
+
+func (l *Lesson) Validate() error {
+	if l.Title == "" || l.Duration < 0 {
+		return ErrInvalidLesson
+	}
+
+	switch l.Type {
+	case LessonTypeText:
+		if l.Content == "" {
+			return ErrInvalidLesson
+		}
+	case LessonTypeVideo, LessonTypeFile:
+		if l.URL == "" {
+			return ErrInvalidLesson
+		}
+	default:
+		return ErrInvalidLessonType
+	}
+
+	return nil
+}
+
+// StripContent removes the lesson body so only its outline is exposed.
+func (l *Lesson) StripContent() {
+	l.Content = ""
+	l.URL = ""
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Authors may only change their posts for a short while after posting
+const (
+	PostEditWindow   = 15 * time.Minute
+	PostDeleteWindow = 30 * time.Minute
+)
+
+type Thread struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	CourseID       primitive.ObjectID  `bson:"course_id" json:"course_id"`
+	AuthorID       int                 `bson:"author_id" json:"author_id"`
+	AuthorName     string              `bson:"author_name" json:"author_name"`
+	Title          string              `bson:"title" json:"title"`
+	Body           string              `bson:"body" json:"body"`
+	Pinned         bool                `bson:"pinned" json:"pinned"`
+	Upvotes        int                 `bson:"upvotes" json:"upvotes"`
+	UpvoterIDs     []int               `bson:"upvoter_ids" json:"-"`
+	ReplyCount     int                 `bson:"reply_count" json:"reply_count"`
+	AnswerID       *primitive.ObjectID `bson:"answer_id,omitempty" json:"answer_id,omitempty"`
+	CreatedAt      time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time           `bson:"updated_at" json:"updated_at"`
+	LastActivityAt time.Time           `bson:"last_activity_at" json:"last_activity_at"`
+}
+
+type Reply struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	ThreadID         primitive.ObjectID `bson:"thread_id" json:"thread_id"`
+	CourseID         primitive.ObjectID `bson:"course_id" json:"course_id"`
+	AuthorID         int                `bson:"author_id" json:"author_id"`
+	AuthorName       string             `bson:"author_name" json:"author_name"`
+	Body             string             `bson:"body" json:"body"`
+	Upvotes          int                `bson:"upvotes" json:"upvotes"`
+	UpvoterIDs       []int              `bson:"upvoter_ids" json:"-"`
+	InstructorAnswer bool               `bson:"instructor_answer" json:"instructor_answer"`
+	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// ThreadPage is one page of a course's threads. Pinned threads are listed
+// separately on the first page only.
+type ThreadPage struct {
+	Pinned     []Thread `json:"pinned,omitempty"`
+	Threads    []Thread `json:"threads"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+type ReplyPage struct {
+	Replies    []Reply `json:"replies"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+func (t *Thread) Validate() error {
+	if t.Title == "" || t.Body == "" {
+		return ErrInvalidThread
+	}
+	return nil
+}
+
+func (r *Reply) Validate() error {
+	if r.Body == "" {
+		return ErrInvalidReply
+	}
+	return nil
+}
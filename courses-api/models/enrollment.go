@@ -11,4 +11,20 @@ type Enrollment struct {
     CourseID  primitive.ObjectID `bson:"course_id" json:"course_id"`
     UserID    int               `bson:"user_id" json:"user_id"`
     Date      time.Time         `bson:"date" json:"date"`
-} 
\ No newline at end of file
+    LastLessonID *primitive.ObjectID `bson:"last_lesson_id,omitempty" json:"last_lesson_id,omitempty"`
+    CompletedAt  *time.Time          `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+    // LicenseID is set when the seat was assigned from a seat license
+    LicenseID    *primitive.ObjectID `bson:"license_id,omitempty" json:"license_id,omitempty"`
+}
+
+// EnrollmentCancellation records an enrollment that was removed, by whom and why
+type EnrollmentCancellation struct {
+    ID           primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+    EnrollmentID primitive.ObjectID `bson:"enrollment_id" json:"enrollment_id"`
+    CourseID     primitive.ObjectID `bson:"course_id" json:"course_id"`
+    UserID       int               `bson:"user_id" json:"user_id"`
+    EnrolledAt   time.Time         `bson:"enrolled_at" json:"enrolled_at"`
+    Reason       string            `bson:"reason,omitempty" json:"reason,omitempty"`
+    CancelledBy  int               `bson:"cancelled_by" json:"cancelled_by"`
+    CancelledAt  time.Time         `bson:"cancelled_at" json:"cancelled_at"`
+}
@@ -8,13 +8,178 @@ var (
     ErrDuplicateCourse    = errors.New("a course with the same title and instructor already exists")
     ErrInvalidCourseData  = errors.New("invalid course data")
     ErrInvalidCategory    = errors.New("invalid category")
+    ErrInvalidCourseStatus = errors.New("invalid course status")
+    ErrInvalidTransition  = errors.New("course status transition not allowed")
+    ErrInvalidSchedule    = errors.New("unpublish_at must be after publish_at")
+    ErrInvalidTimeZone    = errors.New("invalid time zone")
+    ErrInvalidDates       = errors.New("end_date must not be before start_date")
+    ErrInvalidSession     = errors.New("sessions require start and end dates, a weekday from 0 to 6, a HH:MM start time and a positive duration")
+    ErrInvalidEnrollmentWindow = errors.New("enrollment_closes_at must be after enrollment_opens_at")
+    ErrInvalidPrice       = errors.New("price must not be negative and paid courses need a three-letter upper-case currency code")
+    ErrInvalidTags        = errors.New("courses may have up to 20 tags of at most 40 letters, digits or -+#. characters")
+    ErrInvalidLocale      = errors.New("locales must be en or es, and translations must be into a locale other than the course's default_locale")
+    ErrInvalidClone       = errors.New("a clone needs a new title or cohort label of at most 100 characters, non-negative seats, and at most one of start_date and shift_days; start_date needs a course with a start date")
 )
 
 // Enrollment-related errors
 var (
     ErrNoAvailableSeats  = errors.New("no available seats in the course")
+    ErrSeatsInUse        = errors.New("cannot remove more seats than are open")
     ErrAlreadyEnrolled   = errors.New("user is already enrolled in this course")
     ErrEnrollmentNotFound = errors.New("enrollment not found")
+    ErrInvalidProgress    = errors.New("progress status must be started or completed")
+    ErrEnrollmentNotOpen  = errors.New("enrollment for this course has not opened yet")
+    ErrEnrollmentClosed   = errors.New("enrollment for this course has closed")
+    ErrInvalidReason      = errors.New("cancellation reason must be at most 500 characters")
+)
+
+// Waitlist-related errors
+var (
+    ErrWaitlistEntryNotFound = errors.New("user is not on the waitlist for this course")
+    ErrAlreadyWaitlisted     = errors.New("user is already on the waitlist for this course")
+    ErrSeatsAvailable        = errors.New("the course has available seats; enroll directly")
+)
+
+// Curriculum-related errors
+var (
+    ErrSectionNotFound   = errors.New("section not found")
+    ErrLessonNotFound    = errors.New("lesson not found")
+    ErrInvalidSection    = errors.New("section title is required")
+    ErrInvalidLesson     = errors.New("lesson requires a title, a non-negative duration and content matching its type")
+    ErrInvalidLessonType = errors.New("lesson type must be one of: text, video, file")
+    ErrInvalidOrder      = errors.New("order must list every item exactly once")
+    ErrLessonLocked      = errors.New("lesson content is only available to enrolled users")
+)
+
+// Certificate-related errors
+var (
+    ErrCertificateNotFound = errors.New("certificate not found")
+    ErrCertificateExists   = errors.New("a certificate was already issued for this enrollment")
+    ErrCourseNotCompleted  = errors.New("course has not been completed")
+    ErrUserNotFound        = errors.New("user not found")
+)
+
+// Quiz-related errors
+var (
+    ErrQuizNotFound        = errors.New("quiz not found")
+    ErrAttemptNotFound     = errors.New("quiz attempt not found")
+    ErrInvalidQuiz         = errors.New("quiz requires a title, at least one question and valid limits")
+    ErrInvalidQuestion     = errors.New("question requires a prompt and a consistent answer key")
+    ErrInvalidQuestionType = errors.New("question type must be one of: single_choice, multiple_choice, short_answer")
+    ErrAttemptLimitReached = errors.New("maximum number of attempts reached")
+    ErrAttemptClosed       = errors.New("quiz attempt has already been submitted or expired")
+    ErrAttemptExpired      = errors.New("quiz attempt time limit exceeded")
+)
+
+// Review-related errors
+var (
+    ErrReviewNotFound  = errors.New("review not found")
+    ErrAlreadyReviewed = errors.New("user has already reviewed this course")
+    ErrInvalidRating   = errors.New("rating must be between 1 and 5")
+    ErrNotEnrolled     = errors.New("user is not enrolled in this course")
+)
+
+// Discussion-related errors
+var (
+    ErrThreadNotFound     = errors.New("thread not found")
+    ErrReplyNotFound      = errors.New("reply not found")
+    ErrInvalidThread      = errors.New("thread title and body are required")
+    ErrInvalidReply       = errors.New("reply body is required")
+    ErrInvalidCursor      = errors.New("invalid cursor")
+    ErrEditWindowClosed   = errors.New("the edit window for this post has closed")
+    ErrDeleteWindowClosed = errors.New("the delete window for this post has closed")
+)
+
+// Seat hold errors
+var (
+    ErrHoldNotFound     = errors.New("seat hold not found")
+    ErrHoldExists       = errors.New("user already holds a seat in this course")
+    ErrHoldExpired      = errors.New("seat hold has expired")
+    ErrInvalidHoldTime  = errors.New("hold minutes must be between 1 and 30")
+)
+
+// Order and payment errors
+var (
+    ErrOrderNotFound        = errors.New("order not found")
+    ErrPaymentRequired      = errors.New("this course requires payment; use checkout")
+    ErrCourseIsFree         = errors.New("this course is free; enroll directly")
+    ErrCheckoutInProgress   = errors.New("a checkout for this course is already in progress")
+    ErrOrderNotPending      = errors.New("order is no longer pending")
+    ErrPaymentDeclined      = errors.New("payment was declined")
+    ErrPaymentFailed        = errors.New("payment provider error")
+    ErrInvalidPaymentMethod = errors.New("invalid payment method")
+    ErrInvalidSignature     = errors.New("invalid webhook signature")
+)
+
+// Coupon errors
+var (
+    ErrCouponNotFound      = errors.New("coupon not found")
+    ErrDuplicateCoupon     = errors.New("a coupon with this code already exists")
+    ErrInvalidCoupon       = errors.New("coupon requires a code, a percentage between 1 and 100 or a positive fixed amount with currency, and non-negative limits")
+    ErrCouponExpired       = errors.New("coupon has expired")
+    ErrCouponNotApplicable = errors.New("coupon does not apply to this course")
+    ErrCouponExhausted     = errors.New("coupon has no redemptions left")
+    ErrCouponLimitReached  = errors.New("coupon usage limit reached for this user")
+)
+
+// Prerequisite errors
+var (
+    ErrPrerequisitesNotMet  = errors.New("missing prerequisites")
+    ErrInvalidPrerequisites = errors.New("prerequisites must be distinct existing courses other than the course itself")
+    ErrPrerequisiteCycle    = errors.New("prerequisites would form a cycle")
+)
+
+// Category errors
+var (
+    ErrCategoryNotFound    = errors.New("category not found")
+    ErrDuplicateCategory   = errors.New("a category with this slug already exists")
+    ErrInvalidCategoryData = errors.New("category requires a lower-case slug of letters, digits and dashes, a display name of at most 100 characters and an icon of at most 200")
+    ErrCategoryCycle       = errors.New("a category cannot be its own ancestor")
+    ErrCategoryInUse       = errors.New("category still has courses or subcategories")
+)
+
+// Instructor errors
+var (
+    ErrInstructorNotFound       = errors.New("instructor not found")
+    ErrInvalidInstructorProfile = errors.New("instructor profile requires a display name of at most 100 characters, a bio of at most 5000 and an http(s) photo URL")
+    ErrUnknownInstructor        = errors.New("every instructor ID must belong to an instructor profile")
+)
+
+// Course image errors
+var (
+    ErrImageTooLarge    = errors.New("images may be at most 5 MB and 8000 pixels on each side")
+    ErrUnsupportedImage = errors.New("images must be JPEG, PNG or GIF")
+)
+
+// Course import errors
+var (
+    ErrUnsupportedFormat    = errors.New("format must be csv, json or ndjson")
+    ErrMissingExternalKey   = errors.New("external_key is required")
+    ErrDuplicateExternalKey = errors.New("external_key appears more than once in the import")
+    ErrTooManyImportRows    = errors.New("imports may have at most 5000 rows")
+)
+
+// Course material errors
+var (
+    ErrMaterialNotFound      = errors.New("material not found")
+    ErrInvalidMaterial       = errors.New("materials need a file name of at most 200 characters with a supported extension matching the file contents")
+    ErrMaterialTooLarge      = errors.New("materials may be at most 100 MB")
+    ErrMaterialQuotaExceeded = errors.New("course storage quota exceeded")
+    ErrInvalidDownloadLink   = errors.New("download link is invalid or has expired")
+)
+
+// Seat license errors
+var (
+    ErrLicenseNotFound  = errors.New("license not found")
+    ErrInvalidLicense   = errors.New("licenses need a course, at least one seat and 1 to 20 distinct managers; a license without an organization has exactly one manager")
+    ErrNoLicenseSeats   = errors.New("no seats left on the license")
+    ErrInvalidBulkUsers = errors.New("bulk enrollment needs 1 to 500 positive user IDs")
+)
+
+// Calendar-related errors
+var (
+    ErrCalendarTokenNotFound = errors.New("calendar feed not found")
+    ErrCalendarTokenExists   = errors.New("calendar feed already exists")
 )
 
 // Authentication/Authorization errors
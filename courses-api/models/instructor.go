@@ -0,0 +1,39 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// InstructorProfile is the public profile of a users-api account that teaches
+// courses. Having a profile is what makes a user an instructor.
+type InstructorProfile struct {
+	UserID      int       `bson:"_id" json:"user_id"`
+	DisplayName string    `bson:"display_name" json:"display_name"`
+	Bio         string    `bson:"bio" json:"bio"`
+	PhotoURL    string    `bson:"photo_url,omitempty" json:"photo_url,omitempty"`
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// InstructorPage is an instructor's profile together with the courses they teach.
+type InstructorPage struct {
+	InstructorProfile
+	Courses []Course `json:"courses"`
+}
+
+func (p *InstructorProfile) Validate() error {
+	p.DisplayName = strings.TrimSpace(p.DisplayName)
+	p.Bio = strings.TrimSpace(p.Bio)
+	if p.DisplayName == "" || len(p.DisplayName) > 100 || len(p.Bio) > 5000 {
+		return ErrInvalidInstructorProfile
+	}
+	if p.PhotoURL != "" {
+		u, err := url.Parse(p.PhotoURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidInstructorProfile
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstructorProfileValidate(t *testing.T) {
+	profile := InstructorProfile{DisplayName: "  Ada Lovelace ", PhotoURL: "https://example.com/ada.png"}
+	assert.NoError(t, profile.Validate())
+	assert.Equal(t, "Ada Lovelace", profile.DisplayName)
+
+	assert.Equal(t, ErrInvalidInstructorProfile, (&InstructorProfile{}).Validate())
+	assert.Equal(t, ErrInvalidInstructorProfile, (&InstructorProfile{DisplayName: "Ada", PhotoURL: "javascript:alert(1)"}).Validate())
+}
+
+func TestCourseIsTaughtBy(t *testing.T) {
+	course := Course{InstructorIDs: []int{3, 7}}
+	assert.True(t, course.IsTaughtBy(7))
+	assert.False(t, course.IsTaughtBy(4))
+}
@@ -2,22 +2,235 @@ package models
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Transactor runs fn atomically; repository calls made with the context
+// passed to fn take part in the transaction.
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type CourseRepository interface {
 	Create(ctx context.Context, course *Course) error
 	FindAll(ctx context.Context) ([]Course, error)
 	FindByID(ctx context.Context, id primitive.ObjectID) (*Course, error)
-	Update(ctx context.Context, course *Course) error
+	FindByExternalKey(ctx context.Context, key string) (*Course, error)
+	ForEach(ctx context.Context, fn func(Course) error) error
+	UpdateWithSeats(ctx context.Context, course *Course, seatDelta int) (*Course, error)
 	Delete(ctx context.Context, id primitive.ObjectID) error
-	CheckAvailability(ctx context.Context, courseIDs []primitive.ObjectID) (map[string]int, error)
+	CheckAvailability(ctx context.Context, courseIDs []primitive.ObjectID) (map[string]SeatAvailability, error)
 	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]Course, error)
+	FindPublished(ctx context.Context) ([]Course, error)
+	FindByInstructorID(ctx context.Context, userID int, includeUnpublished bool) ([]Course, error)
+	FindByCategory(ctx context.Context, slug string) ([]Course, error)
+	FindByTags(ctx context.Context, tags []string, includeUnpublished bool) ([]Course, error)
+	SuggestTags(ctx context.Context, prefix string, limit int) ([]TagCount, error)
+	CountByCategory(ctx context.Context, slug string) (int64, error)
+	RenameCategory(ctx context.Context, from, to string) error
+	UpdateStatus(ctx context.Context, id primitive.ObjectID, from, to string) error
+	UpdateSchedule(ctx context.Context, id primitive.ObjectID, publishAt, unpublishAt *time.Time) error
+	FindScheduleDue(ctx context.Context, now time.Time) ([]Course, error)
+	UpdateRating(ctx context.Context, id primitive.ObjectID, average float64, count int) error
+	SetImage(ctx context.Context, id primitive.ObjectID, imageURL, thumbnailURL string, keys []string) ([]string, error)
+	CountByImageKey(ctx context.Context, key string) (int64, error)
+	IncrementSeats(ctx context.Context, id primitive.ObjectID, delta int) (*Course, error)
+	ReserveSeat(ctx context.Context, id primitive.ObjectID, reserved int) (*Course, error)
+	ReserveSeats(ctx context.Context, id primitive.ObjectID, count, reserved int) (*Course, error)
+	HoldSeat(ctx context.Context, id primitive.ObjectID, reserved int) error
+	ReleaseHeldSeat(ctx context.Context, id primitive.ObjectID) error
+	ConsumeHeldSeat(ctx context.Context, id primitive.ObjectID) (*Course, error)
+	OfferSeat(ctx context.Context, id primitive.ObjectID) error
+	ReleaseOfferedSeat(ctx context.Context, id primitive.ObjectID) error
 }
 
 type EnrollmentRepository interface {
 	Create(ctx context.Context, enrollment *Enrollment) error
 	FindByUserID(ctx context.Context, userID int) ([]Enrollment, error)
 	CheckEnrollment(ctx context.Context, courseID primitive.ObjectID, userID int) (bool, error)
-} 
\ No newline at end of file
+	CountByCourseID(ctx context.Context, courseID primitive.ObjectID) (int64, error)
+	FindByCourseID(ctx context.Context, courseID primitive.ObjectID, page, limit int) ([]Enrollment, int64, error)
+	FindByCourseAndUser(ctx context.Context, courseID primitive.ObjectID, userID int) (*Enrollment, error)
+	FindByLicenseID(ctx context.Context, licenseID primitive.ObjectID) ([]Enrollment, error)
+	UpdateProgress(ctx context.Context, id primitive.ObjectID, lastLessonID primitive.ObjectID, completedAt *time.Time) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type EnrollmentCancellationRepository interface {
+	Create(ctx context.Context, cancellation *EnrollmentCancellation) error
+}
+
+type SectionRepository interface {
+	Create(ctx context.Context, section *Section) error
+	FindByCourseID(ctx context.Context, courseID primitive.ObjectID) ([]Section, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*Section, error)
+	Update(ctx context.Context, section *Section) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	Reorder(ctx context.Context, courseID primitive.ObjectID, ids []primitive.ObjectID) error
+}
+
+type LessonRepository interface {
+	Create(ctx context.Context, lesson *Lesson) error
+	FindByCourseID(ctx context.Context, courseID primitive.ObjectID) ([]Lesson, error)
+	FindBySectionID(ctx context.Context, sectionID primitive.ObjectID) ([]Lesson, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*Lesson, error)
+	Update(ctx context.Context, lesson *Lesson) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	DeleteBySectionID(ctx context.Context, sectionID primitive.ObjectID) error
+	Reorder(ctx context.Context, sectionID primitive.ObjectID, ids []primitive.ObjectID) error
+}
+
+type LessonProgressRepository interface {
+	Upsert(ctx context.Context, progress *LessonProgress) error
+	FindByEnrollmentID(ctx context.Context, enrollmentID primitive.ObjectID) ([]LessonProgress, error)
+}
+
+type CertificateRepository interface {
+	Create(ctx context.Context, certificate *Certificate) error
+	FindByCode(ctx context.Context, code string) (*Certificate, error)
+	FindByEnrollmentID(ctx context.Context, enrollmentID primitive.ObjectID) (*Certificate, error)
+	FindByUserID(ctx context.Context, userID int) ([]Certificate, error)
+}
+
+// UserDirectory looks up accounts in users-api
+type UserDirectory interface {
+	GetUser(ctx context.Context, id int) (*User, error)
+}
+
+type QuizRepository interface {
+	Create(ctx context.Context, quiz *Quiz) error
+	FindByCourseID(ctx context.Context, courseID primitive.ObjectID) ([]Quiz, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*Quiz, error)
+	Update(ctx context.Context, quiz *Quiz) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type QuizAttemptRepository interface {
+	Create(ctx context.Context, attempt *QuizAttempt) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*QuizAttempt, error)
+	FindByQuizAndUser(ctx context.Context, quizID primitive.ObjectID, userID int) ([]QuizAttempt, error)
+	FindSubmittedByQuiz(ctx context.Context, quizID primitive.ObjectID) ([]QuizAttempt, error)
+	Complete(ctx context.Context, attempt *QuizAttempt) error
+}
+
+type ReviewRepository interface {
+	Create(ctx context.Context, review *Review) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*Review, error)
+	FindByCourseAndUser(ctx context.Context, courseID primitive.ObjectID, userID int) (*Review, error)
+	Find(ctx context.Context, query ReviewQuery) ([]Review, int64, error)
+	Update(ctx context.Context, review *Review) error
+	SetHidden(ctx context.Context, id primitive.ObjectID, hidden bool) error
+	RatingSummary(ctx context.Context, courseID primitive.ObjectID) (float64, int, error)
+}
+
+type ThreadRepository interface {
+	Create(ctx context.Context, thread *Thread) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*Thread, error)
+	FindPinned(ctx context.Context, courseID primitive.ObjectID) ([]Thread, error)
+	FindPage(ctx context.Context, courseID primitive.ObjectID, before *primitive.ObjectID, limit int) ([]Thread, error)
+	Update(ctx context.Context, thread *Thread) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	SetPinned(ctx context.Context, id primitive.ObjectID, pinned bool) error
+	SetAnswer(ctx context.Context, id primitive.ObjectID, answerID *primitive.ObjectID) error
+	AddUpvote(ctx context.Context, id primitive.ObjectID, userID int) error
+	RemoveUpvote(ctx context.Context, id primitive.ObjectID, userID int) error
+	IncrementReplies(ctx context.Context, id primitive.ObjectID, delta int) error
+}
+
+type ReplyRepository interface {
+	Create(ctx context.Context, reply *Reply) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*Reply, error)
+	FindPage(ctx context.Context, threadID primitive.ObjectID, after *primitive.ObjectID, limit int) ([]Reply, error)
+	Update(ctx context.Context, reply *Reply) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	DeleteByThreadID(ctx context.Context, threadID primitive.ObjectID) error
+	SetInstructorAnswer(ctx context.Context, threadID primitive.ObjectID, replyID *primitive.ObjectID) error
+	AddUpvote(ctx context.Context, id primitive.ObjectID, userID int) error
+	RemoveUpvote(ctx context.Context, id primitive.ObjectID, userID int) error
+}
+
+type CalendarTokenRepository interface {
+	FindByUserID(ctx context.Context, userID int) (*CalendarToken, error)
+	FindByToken(ctx context.Context, token string) (*CalendarToken, error)
+	Create(ctx context.Context, token *CalendarToken) error
+	Save(ctx context.Context, token *CalendarToken) error
+}
+
+type WaitlistRepository interface {
+	Create(ctx context.Context, entry *WaitlistEntry) error
+	FindActive(ctx context.Context, courseID primitive.ObjectID, userID int) (*WaitlistEntry, error)
+	FindByCourseID(ctx context.Context, courseID primitive.ObjectID) ([]WaitlistEntry, error)
+	CountAhead(ctx context.Context, entry *WaitlistEntry) (int, error)
+	CountOpenOffers(ctx context.Context, courseID primitive.ObjectID, now time.Time) (int, error)
+	OfferNext(ctx context.Context, courseID primitive.ObjectID, now, expiresAt time.Time) (*WaitlistEntry, error)
+	UpdateStatus(ctx context.Context, id primitive.ObjectID, from, to string) error
+	FindExpiredOffers(ctx context.Context, now time.Time) ([]WaitlistEntry, error)
+}
+
+type SeatLicenseRepository interface {
+	Create(ctx context.Context, license *SeatLicense) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*SeatLicense, error)
+	FindAll(ctx context.Context) ([]SeatLicense, error)
+	FindByManager(ctx context.Context, userID int) ([]SeatLicense, error)
+	ClaimSeat(ctx context.Context, id primitive.ObjectID) error
+	ReleaseSeat(ctx context.Context, id primitive.ObjectID) error
+}
+
+type SeatHoldRepository interface {
+	Create(ctx context.Context, hold *SeatHold) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*SeatHold, error)
+	UpdateStatus(ctx context.Context, id primitive.ObjectID, from, to string) error
+	FindExpired(ctx context.Context, now time.Time) ([]SeatHold, error)
+}
+
+type OrderRepository interface {
+	Create(ctx context.Context, order *Order) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*Order, error)
+	FindByProviderRef(ctx context.Context, provider, ref string) (*Order, error)
+	FindPending(ctx context.Context, courseID primitive.ObjectID, userID int) (*Order, error)
+	FindByUserID(ctx context.Context, userID int) ([]Order, error)
+	SetProviderRef(ctx context.Context, id primitive.ObjectID, ref string) error
+	Transition(ctx context.Context, order *Order, from string) error
+}
+
+type MaterialRepository interface {
+	Create(ctx context.Context, material *Material) error
+	FindByCourseID(ctx context.Context, courseID primitive.ObjectID) ([]Material, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*Material, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	CountByKey(ctx context.Context, key string) (int64, error)
+	ReserveStorage(ctx context.Context, courseID primitive.ObjectID, size, quota int64) error
+	ReleaseStorage(ctx context.Context, courseID primitive.ObjectID, size int64) error
+	StorageUsed(ctx context.Context, courseID primitive.ObjectID) (int64, error)
+}
+
+type CouponRepository interface {
+	Create(ctx context.Context, coupon *Coupon) error
+	FindAll(ctx context.Context) ([]Coupon, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*Coupon, error)
+	FindByCode(ctx context.Context, code string) (*Coupon, error)
+	Update(ctx context.Context, coupon *Coupon) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	CountUses(ctx context.Context, couponID primitive.ObjectID, userID int) (int, error)
+	Redeem(ctx context.Context, coupon *Coupon, userID int, now time.Time) error
+	Release(ctx context.Context, couponID primitive.ObjectID, userID int) error
+	RenameCategory(ctx context.Context, from, to string) error
+}
+
+type InstructorRepository interface {
+	Save(ctx context.Context, profile *InstructorProfile) error
+	FindByUserID(ctx context.Context, userID int) (*InstructorProfile, error)
+	FindByUserIDs(ctx context.Context, userIDs []int) ([]InstructorProfile, error)
+	Delete(ctx context.Context, userID int) error
+}
+
+type CategoryRepository interface {
+	Create(ctx context.Context, category *Category) error
+	FindAll(ctx context.Context) ([]Category, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*Category, error)
+	Update(ctx context.Context, category *Category) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	Slugs(ctx context.Context) ([]string, error)
+}
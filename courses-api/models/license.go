@@ -0,0 +1,86 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Limits on licenses and bulk enrollment
+const (
+	MaxLicenseManagers = 20
+	MaxBulkEnrollUsers = 500
+)
+
+// SeatLicense is a pool of seats in a course bought in bulk by an
+// organization or a single manager. The seats are taken out of the course
+// when the license is created, and the managers hand them out to users.
+type SeatLicense struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	CourseID     primitive.ObjectID `bson:"course_id" json:"course_id"`
+	Organization string             `bson:"organization,omitempty" json:"organization,omitempty"`
+	ManagerIDs   []int              `bson:"manager_ids" json:"manager_ids"`
+	Seats        int                `bson:"seats" json:"seats"`
+	UsedSeats    int                `bson:"used_seats" json:"used_seats"`
+	CreatedBy    int                `bson:"created_by" json:"created_by"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// LicenseDetail is a license with the enrollments assigned from it.
+type LicenseDetail struct {
+	SeatLicense
+	Assignments []Enrollment `json:"assignments"`
+}
+
+// BulkEnrollResult reports what happened to one user of a bulk enrollment.
+type BulkEnrollResult struct {
+	UserID       int                 `json:"user_id"`
+	EnrollmentID *primitive.ObjectID `json:"enrollment_id,omitempty"`
+	Error        string              `json:"error,omitempty"`
+}
+
+type BulkEnrollment struct {
+	Enrolled int                `json:"enrolled"`
+	Failed   int                `json:"failed"`
+	Results  []BulkEnrollResult `json:"results"`
+}
+
+// Validate checks a new license. A license without an organization belongs
+// to its single manager.
+func (l *SeatLicense) Validate() error {
+	l.Organization = strings.TrimSpace(l.Organization)
+	if l.CourseID.IsZero() || l.Seats < 1 || utf8.RuneCountInString(l.Organization) > 200 {
+		return ErrInvalidLicense
+	}
+	if len(l.ManagerIDs) == 0 || len(l.ManagerIDs) > MaxLicenseManagers {
+		return ErrInvalidLicense
+	}
+	if l.Organization == "" && len(l.ManagerIDs) != 1 {
+		return ErrInvalidLicense
+	}
+	seen := map[int]bool{}
+	for _, id := range l.ManagerIDs {
+		if id <= 0 || seen[id] {
+			return ErrInvalidLicense
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// IsManagedBy reports whether the user may assign the license's seats.
+func (l *SeatLicense) IsManagedBy(userID int) bool {
+	for _, id := range l.ManagerIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// FreeSeats is the number of seats that can still be assigned.
+func (l *SeatLicense) FreeSeats() int {
+	return l.Seats - l.UsedSeats
+}
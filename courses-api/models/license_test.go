@@ -0,0 +1,32 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSeatLicenseValidate(t *testing.T) {
+	courseID := primitive.NewObjectID()
+
+	personal := SeatLicense{CourseID: courseID, ManagerIDs: []int{7}, Seats: 5}
+	assert.NoError(t, personal.Validate())
+	assert.True(t, personal.IsManagedBy(7))
+	assert.False(t, personal.IsManagedBy(8))
+
+	company := SeatLicense{CourseID: courseID, Organization: "  Acme  ", ManagerIDs: []int{7, 8}, Seats: 50}
+	assert.NoError(t, company.Validate())
+	assert.Equal(t, "Acme", company.Organization)
+
+	for _, license := range []SeatLicense{
+		{ManagerIDs: []int{7}, Seats: 5},
+		{CourseID: courseID, ManagerIDs: []int{7}},
+		{CourseID: courseID, Seats: 5},
+		{CourseID: courseID, ManagerIDs: []int{7, 8}, Seats: 5},
+		{CourseID: courseID, Organization: "Acme", ManagerIDs: []int{7, 7}, Seats: 5},
+		{CourseID: courseID, Organization: "Acme", ManagerIDs: []int{0}, Seats: 5},
+	} {
+		assert.Equal(t, ErrInvalidLicense, license.Validate())
+	}
+}
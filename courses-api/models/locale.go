@@ -0,0 +1,114 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the language of courses that do not set one.
+const DefaultLocale = "en"
+
+// SupportedLocales are the languages course content can be written in.
+var SupportedLocales = []string{"en", "es"}
+
+// CourseTranslation holds a course's title and description in one locale.
+// Empty fields fall back to the course's own.
+type CourseTranslation struct {
+	Title       string `bson:"title,omitempty" json:"title,omitempty"`
+	Description string `bson:"description,omitempty" json:"description,omitempty"`
+}
+
+func IsSupportedLocale(locale string) bool {
+	for _, supported := range SupportedLocales {
+		if locale == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// NegotiateLocale picks the locale to serve: lang if it is supported,
+// otherwise the preferred supported language in the Accept-Language header,
+// e.g. "es-AR,es;q=0.9,en;q=0.5". Regional variants match their language.
+// It returns "" when nothing matches, meaning each course's own locale.
+func NegotiateLocale(lang, acceptLanguage string) string {
+	if lang = strings.ToLower(strings.TrimSpace(lang)); IsSupportedLocale(lang) {
+		return lang
+	}
+
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if primary, _, ok := strings.Cut(tag, "-"); ok {
+			tag = primary
+		}
+		if !IsSupportedLocale(tag) {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		// Earlier languages win ties, as listed by the client
+		if q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	return best
+}
+
+// ContentLocale returns the locale of the course's own title and description.
+func (c *Course) ContentLocale() string {
+	if c.DefaultLocale == "" {
+		return DefaultLocale
+	}
+	return c.DefaultLocale
+}
+
+// Localize replaces the title and description with their translation into
+// locale, if the course has one, and records the locale served. Fields
+// missing from the translation keep the default locale's text.
+func (c *Course) Localize(locale string) {
+	c.Locale = c.ContentLocale()
+	if locale == "" || locale == c.Locale {
+		return
+	}
+	translation, ok := c.Translations[locale]
+	if !ok {
+		return
+	}
+	if translation.Title != "" {
+		c.Title = translation.Title
+	}
+	if translation.Description != "" {
+		c.Description = translation.Description
+	}
+	c.Locale = locale
+}
+
+// validateTranslations checks the default locale and that translations are
+// into other supported locales. Empty translations are dropped.
+func (c *Course) validateTranslations() error {
+	if c.DefaultLocale != "" && !IsSupportedLocale(c.DefaultLocale) {
+		return ErrInvalidLocale
+	}
+	for locale, translation := range c.Translations {
+		translation.Title = strings.TrimSpace(translation.Title)
+		translation.Description = strings.TrimSpace(translation.Description)
+		if !IsSupportedLocale(locale) || locale == c.ContentLocale() {
+			return ErrInvalidLocale
+		}
+		if translation.Title == "" && translation.Description == "" {
+			delete(c.Translations, locale)
+			continue
+		}
+		c.Translations[locale] = translation
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateLocale(t *testing.T) {
+	assert.Equal(t, "es", NegotiateLocale("ES", "en"))
+	assert.Equal(t, "en", NegotiateLocale("fr", "en-US,en;q=0.9"))
+	assert.Equal(t, "es", NegotiateLocale("", "fr-FR, es-AR;q=0.8, en;q=0.5"))
+	assert.Equal(t, "en", NegotiateLocale("", "en;q=0.7, es;q=0.7"))
+	assert.Equal(t, "en", NegotiateLocale("", "es;q=0, en;q=0.1"))
+	assert.Equal(t, "", NegotiateLocale("", "fr, de;q=0.5, *"))
+	assert.Equal(t, "", NegotiateLocale("", ""))
+}
+
+func TestCourseLocalizeFallsBackToDefault(t *testing.T) {
+	course := Course{
+		Title:       "Intro to Go",
+		Description: "Learn Go",
+		Translations: map[string]CourseTranslation{
+			"es": {Title: "Introducción a Go"},
+		},
+	}
+
+	spanish := course
+	spanish.Localize("es")
+	assert.Equal(t, "Introducción a Go", spanish.Title)
+	assert.Equal(t, "Learn Go", spanish.Description)
+	assert.Equal(t, "es", spanish.Locale)
+
+	english := course
+	english.Localize("")
+	assert.Equal(t, "Intro to Go", english.Title)
+	assert.Equal(t, "en", english.Locale)
+
+	spanishCourse := Course{Title: "Curso", DefaultLocale: "es"}
+	spanishCourse.Localize("en")
+	assert.Equal(t, "Curso", spanishCourse.Title)
+	assert.Equal(t, "es", spanishCourse.Locale)
+}
+
+func TestValidateTranslations(t *testing.T) {
+	course := Course{Translations: map[string]CourseTranslation{
+		"es": {Title: "  Curso  ", Description: " "},
+	}}
+	assert.NoError(t, course.validateTranslations())
+	assert.Equal(t, CourseTranslation{Title: "Curso"}, course.Translations["es"])
+
+	empty := Course{Translations: map[string]CourseTranslation{"es": {}}}
+	assert.NoError(t, empty.validateTranslations())
+	assert.Empty(t, empty.Translations)
+
+	for _, invalid := range []Course{
+		{DefaultLocale: "fr"},
+		{Translations: map[string]CourseTranslation{"fr": {Title: "Cours"}}},
+		{Translations: map[string]CourseTranslation{"en": {Title: "Course"}}},
+		{DefaultLocale: "es", Translations: map[string]CourseTranslation{"es": {Title: "Curso"}}},
+	} {
+		assert.Equal(t, ErrInvalidLocale, invalid.validateTranslations())
+	}
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Limits for course materials
+const (
+	MaxMaterialBytes      = 100 << 20
+	DefaultMaterialQuota  = 1 << 30
+	MaterialLinkTTL       = 5 * time.Minute
+	maxMaterialNameLength = 200
+	sniffedZip            = "application/zip"
+	sniffedPDF            = "application/pdf"
+)
+
+// materialTypes maps the accepted file extensions to the content type they
+// are stored and served with.
+var materialTypes = map[string]string{
+	".pdf":  "application/pdf",
+	".ppt":  "application/vnd.ms-powerpoint",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".key":  "application/vnd.apple.keynote",
+	".odp":  "application/vnd.oasis.opendocument.presentation",
+	".doc":  "application/msword",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xls":  "application/vnd.ms-excel",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".zip":  "application/zip",
+	".gz":   "application/gzip",
+	".tgz":  "application/gzip",
+	".7z":   "application/x-7z-compressed",
+	".txt":  "text/plain; charset=utf-8",
+	".md":   "text/markdown; charset=utf-8",
+	".csv":  "text/csv; charset=utf-8",
+}
+
+// Material is a file attached to a course. Key locates it in the private
+// blob store and is never exposed; downloads go through signed links.
+type Material struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	CourseID    primitive.ObjectID `bson:"course_id" json:"course_id"`
+	Name        string             `bson:"name" json:"name"`
+	ContentType string             `bson:"content_type" json:"content_type"`
+	Size        int64              `bson:"size" json:"size"`
+	Key         string             `bson:"key" json:"-"`
+	UploadedBy  int                `bson:"uploaded_by" json:"uploaded_by"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// MaterialList is a course's materials with its storage usage.
+type MaterialList struct {
+	Materials  []Material `json:"materials"`
+	UsedBytes  int64      `json:"used_bytes"`
+	QuotaBytes int64      `json:"quota_bytes"`
+}
+
+// MaterialLink is a short-lived download URL.
+type MaterialLink struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Validate checks the file name and size, and that the sniffed content
+// agrees with the extension so that, for example, HTML cannot be uploaded
+// as a PDF. It sets ContentType from the extension.
+func (m *Material) Validate(sniffed string) error {
+	m.Name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(m.Name, "\\", "/")))
+	if m.Name == "" || m.Name == "." || m.Name == "/" ||
+		utf8.RuneCountInString(m.Name) > maxMaterialNameLength || strings.ContainsAny(m.Name, "\"\r\n") {
+		return ErrInvalidMaterial
+	}
+	if m.Size <= 0 || m.Size > MaxMaterialBytes {
+		return ErrMaterialTooLarge
+	}
+
+	ext := strings.ToLower(filepath.Ext(m.Name))
+	contentType, ok := materialTypes[ext]
+	if !ok {
+		return ErrInvalidMaterial
+	}
+
+	switch ext {
+	case ".pdf":
+		ok = sniffed == sniffedPDF
+	case ".zip", ".pptx", ".docx", ".xlsx", ".key", ".odp":
+		ok = sniffed == sniffedZip
+	case ".txt", ".md", ".csv":
+		ok = strings.HasPrefix(sniffed, "text/plain")
+	default:
+		// Binary formats the sniffer does not know; only refuse markup
+		ok = !strings.HasPrefix(sniffed, "text/html") && !strings.HasPrefix(sniffed, "text/xml")
+	}
+	if !ok {
+		return ErrInvalidMaterial
+	}
+
+	m.ContentType = contentType
+	return nil
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaterialValidateMatchesExtensionAndContent(t *testing.T) {
+	material := Material{Name: "slides/../Week 1.pdf", Size: 1024}
+	assert.NoError(t, material.Validate("application/pdf"))
+	assert.Equal(t, "Week 1.pdf", material.Name)
+	assert.Equal(t, "application/pdf", material.ContentType)
+
+	pptx := Material{Name: "deck.PPTX", Size: 1024}
+	assert.NoError(t, pptx.Validate("application/zip"))
+
+	disguised := Material{Name: "notes.pdf", Size: 1024}
+	assert.Equal(t, ErrInvalidMaterial, disguised.Validate("text/html; charset=utf-8"))
+
+	legacy := Material{Name: "deck.ppt", Size: 1024}
+	assert.NoError(t, legacy.Validate("application/octet-stream"))
+	assert.Equal(t, ErrInvalidMaterial, legacy.Validate("text/html; charset=utf-8"))
+}
+
+func TestMaterialValidateRejectsBadNamesAndSizes(t *testing.T) {
+	for _, name := range []string{"", "script.exe", "page.html", "quote\".pdf"} {
+		material := Material{Name: name, Size: 10}
+		assert.Equal(t, ErrInvalidMaterial, material.Validate("application/pdf"), name)
+	}
+
+	tooLarge := Material{Name: "big.zip", Size: MaxMaterialBytes + 1}
+	assert.Equal(t, ErrMaterialTooLarge, tooLarge.Validate("application/zip"))
+
+	empty := Material{Name: "empty.txt", Size: 0}
+	assert.Equal(t, ErrMaterialTooLarge, empty.Validate("text/plain; charset=utf-8"))
+}
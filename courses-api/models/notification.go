@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Notification types
+const (
+	NotificationWaitlistOffer   = "waitlist_offer"
+	NotificationWaitlistExpired = "waitlist_offer_expired"
+)
+
+// Notification is published to the notifications queue for delivery to a user.
+type Notification struct {
+	UserID    int                    `json:"user_id"`
+	Type      string                 `json:"type"`
+	Message   string                 `json:"message"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Order states
+const (
+	OrderPending   = "pending"
+	OrderPaid      = "paid"
+	OrderFailed    = "failed"
+	OrderCancelled = "cancelled"
+	OrderRefunded  = "refunded"
+)
+
+// Order tracks the purchase of a seat in a paid course. The seat is held
+// while the payment is pending and the enrollment is only created once the
+// provider confirms the payment.
+type Order struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	CourseID      primitive.ObjectID  `bson:"course_id" json:"course_id"`
+	UserID        int                 `bson:"user_id" json:"user_id"`
+	ListPrice     int64               `bson:"list_price" json:"list_price"`
+	Discount      int64               `bson:"discount" json:"discount"`
+	Amount        int64               `bson:"amount" json:"amount"`
+	Currency      string              `bson:"currency" json:"currency"`
+	CouponID      *primitive.ObjectID `bson:"coupon_id,omitempty" json:"coupon_id,omitempty"`
+	CouponCode    string              `bson:"coupon_code,omitempty" json:"coupon_code,omitempty"`
+	Status        string              `bson:"status" json:"status"`
+	HoldID        primitive.ObjectID  `bson:"hold_id" json:"hold_id"`
+	Provider      string              `bson:"provider" json:"provider"`
+	ProviderRef   string              `bson:"provider_ref,omitempty" json:"provider_ref,omitempty"`
+	FailureReason string              `bson:"failure_reason,omitempty" json:"failure_reason,omitempty"`
+	EnrollmentID  *primitive.ObjectID `bson:"enrollment_id,omitempty" json:"enrollment_id,omitempty"`
+	CreatedAt     time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time           `bson:"updated_at" json:"updated_at"`
+	PaidAt        *time.Time          `bson:"paid_at,omitempty" json:"paid_at,omitempty"`
+}
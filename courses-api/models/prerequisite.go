@@ -0,0 +1,52 @@
+package models
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// What a user must have done in a prerequisite course. Completion is required
+// when the course has lessons to complete; otherwise enrollment is enough.
+const (
+	RequireCompletion = "completion"
+	RequireEnrollment = "enrollment"
+)
+
+// MissingPrerequisite is a prerequisite course the user has not satisfied yet.
+type MissingPrerequisite struct {
+	CourseID    primitive.ObjectID `json:"course_id"`
+	Title       string             `json:"title"`
+	Requirement string             `json:"requirement"`
+}
+
+// PrerequisitesError lists every prerequisite blocking an enrollment. It
+// matches ErrPrerequisitesNotMet with errors.Is.
+type PrerequisitesError struct {
+	Missing []MissingPrerequisite
+}
+
+func (e *PrerequisitesError) Error() string {
+	titles := make([]string, len(e.Missing))
+	for i, m := range e.Missing {
+		titles[i] = m.Title
+	}
+	return ErrPrerequisitesNotMet.Error() + ": " + strings.Join(titles, ", ")
+}
+
+func (e *PrerequisitesError) Unwrap() error {
+	return ErrPrerequisitesNotMet
+}
+
+// validatePrerequisites rejects duplicates and a course requiring itself;
+// longer cycles need the stored courses and are checked by the service.
+func (c *Course) validatePrerequisites() error {
+	seen := make(map[primitive.ObjectID]bool, len(c.Prerequisites))
+	for _, id := range c.Prerequisites {
+		if id.IsZero() || id == c.ID || seen[id] {
+			return ErrInvalidPrerequisites
+		}
+		seen[id] = true
+	}
+	return nil
+}
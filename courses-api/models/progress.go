@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Lesson progress states
+const (
+	LessonStarted   = "started"
+	LessonCompleted = "completed"
+)
+
+type LessonProgress struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	EnrollmentID primitive.ObjectID `bson:"enrollment_id" json:"enrollment_id"`
+	CourseID     primitive.ObjectID `bson:"course_id" json:"course_id"`
+	LessonID     primitive.ObjectID `bson:"lesson_id" json:"lesson_id"`
+	UserID       int                `bson:"user_id" json:"user_id"`
+	Status       string             `bson:"status" json:"status"`
+	StartedAt    time.Time          `bson:"started_at" json:"started_at"`
+	CompletedAt  *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+}
+
+// CourseProgress summarizes an enrollment's progress through a course.
+type CourseProgress struct {
+	EnrollmentID      primitive.ObjectID  `json:"enrollment_id"`
+	CourseID          primitive.ObjectID  `json:"course_id"`
+	UserID            int                 `json:"user_id"`
+	TotalLessons      int                 `json:"total_lessons"`
+	CompletedLessons  int                 `json:"completed_lessons"`
+	RequiredLessons   int                 `json:"required_lessons"`
+	CompletedRequired int                 `json:"completed_required"`
+	PercentComplete   int                 `json:"percent_complete"`
+	ResumeLessonID    *primitive.ObjectID `json:"resume_lesson_id,omitempty"`
+	Completed         bool                `json:"completed"`
+	CompletedAt       *time.Time          `json:"completed_at,omitempty"`
+}
+
+// ComputeProgress builds the progress summary for an enrollment. lessons must
+// be in syllabus order. The percentage counts required lessons only, and the
+// course is complete once every required lesson is completed.
+func ComputeProgress(enrollment *Enrollment, lessons []Lesson, progress []LessonProgress) CourseProgress {
+	result := CourseProgress{
+		EnrollmentID: enrollment.ID,
+		CourseID:     enrollment.CourseID,
+		UserID:       enrollment.UserID,
+		TotalLessons: len(lessons),
+		CompletedAt:  enrollment.CompletedAt,
+	}
+
+	completed := make(map[primitive.ObjectID]bool, len(progress))
+	for _, p := range progress {
+		if p.Status == LessonCompleted {
+			completed[p.LessonID] = true
+		}
+	}
+
+	for _, lesson := range lessons {
+		if completed[lesson.ID] {
+			result.CompletedLessons++
+		}
+		if !lesson.Optional {
+			result.RequiredLessons++
+			if completed[lesson.ID] {
+				result.CompletedRequired++
+			}
+		}
+	}
+
+	if result.RequiredLessons > 0 {
+		result.PercentComplete = result.CompletedRequired * 100 / result.RequiredLessons
+		result.Completed = result.CompletedRequired == result.RequiredLessons
+	}
+
+	result.ResumeLessonID = resumeLesson(enrollment.LastLessonID, lessons, completed)
+	return result
+}
+
+// resumeLesson points at the last lesson the student touched, or at the next
+// unfinished lesson after it once it is completed.
+func resumeLesson(last *primitive.ObjectID, lessons []Lesson, completed map[primitive.ObjectID]bool) *primitive.ObjectID {
+	if len(lessons) == 0 {
+		return nil
+	}
+
+	start := 0
+	if last != nil {
+		for i, lesson := range lessons {
+			if lesson.ID == *last {
+				if !completed[lesson.ID] {
+					id := lesson.ID
+					return &id
+				}
+				start = (i + 1) % len(lessons)
+				break
+			}
+		}
+	}
+
+	// Look ahead first, then wrap around to anything skipped earlier
+	for i := range lessons {
+		lesson := lessons[(start+i)%len(lessons)]
+		if !completed[lesson.ID] {
+			id := lesson.ID
+			return &id
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestComputeProgress(t *testing.T) {
+	lessons := []Lesson{
+		{ID: primitive.NewObjectID()},
+		{ID: primitive.NewObjectID()},
+		{ID: primitive.NewObjectID(), Optional: true},
+	}
+	enrollment := &Enrollment{ID: primitive.NewObjectID(), CourseID: primitive.NewObjectID(), UserID: 1}
+
+	completed := func(ids ...primitive.ObjectID) []LessonProgress {
+		var progress []LessonProgress
+		for _, id := range ids {
+			progress = append(progress, LessonProgress{LessonID: id, Status: LessonCompleted})
+		}
+		return progress
+	}
+
+	t.Run("no progress resumes at the first lesson", func(t *testing.T) {
+		result := ComputeProgress(enrollment, lessons, nil)
+		assert.Equal(t, 3, result.TotalLessons)
+		assert.Equal(t, 2, result.RequiredLessons)
+		assert.Equal(t, 0, result.PercentComplete)
+		assert.False(t, result.Completed)
+		assert.Equal(t, lessons[0].ID, *result.ResumeLessonID)
+	})
+
+	t.Run("started lessons do not count as completed", func(t *testing.T) {
+		progress := []LessonProgress{{LessonID: lessons[0].ID, Status: LessonStarted}}
+		result := ComputeProgress(enrollment, lessons, progress)
+		assert.Equal(t, 0, result.CompletedLessons)
+	})
+
+	t.Run("percentage counts required lessons only", func(t *testing.T) {
+		result := ComputeProgress(enrollment, lessons, completed(lessons[0].ID, lessons[2].ID))
+		assert.Equal(t, 2, result.CompletedLessons)
+		assert.Equal(t, 1, result.CompletedRequired)
+		assert.Equal(t, 50, result.PercentComplete)
+		assert.False(t, result.Completed)
+	})
+
+	t.Run("completed once all required lessons are done", func(t *testing.T) {
+		result := ComputeProgress(enrollment, lessons, completed(lessons[0].ID, lessons[1].ID))
+		assert.Equal(t, 100, result.PercentComplete)
+		assert.True(t, result.Completed)
+	})
+
+	t.Run("resume moves past a completed last lesson", func(t *testing.T) {
+		last := lessons[0].ID
+		e := *enrollment
+		e.LastLessonID = &last
+		result := ComputeProgress(&e, lessons, completed(lessons[0].ID))
+		assert.Equal(t, lessons[1].ID, *result.ResumeLessonID)
+	})
+
+	t.Run("resume wraps around to skipped lessons", func(t *testing.T) {
+		last := lessons[2].ID
+		e := *enrollment
+		e.LastLessonID = &last
+		result := ComputeProgress(&e, lessons, completed(lessons[1].ID, lessons[2].ID))
+		assert.Equal(t, lessons[0].ID, *result.ResumeLessonID)
+	})
+
+	t.Run("keeps the recorded completion time", func(t *testing.T) {
+		now := time.Now()
+		e := *enrollment
+		e.CompletedAt = &now
+		result := ComputeProgress(&e, lessons, completed(lessons[0].ID, lessons[1].ID))
+		assert.Equal(t, &now, result.CompletedAt)
+	})
+}
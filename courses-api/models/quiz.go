@@ -0,0 +1,257 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Question types
+const (
+	QuestionSingleChoice   = "single_choice"
+	QuestionMultipleChoice = "multiple_choice"
+	QuestionShortAnswer    = "short_answer"
+)
+
+// Attempt states
+const (
+	AttemptInProgress = "in_progress"
+	AttemptSubmitted  = "submitted"
+	AttemptExpired    = "expired"
+)
+
+type Quiz struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	CourseID     primitive.ObjectID `bson:"course_id" json:"course_id"`
+	Title        string             `bson:"title" json:"title"`
+	Description  string             `bson:"description" json:"description"`
+	MaxAttempts  int                `bson:"max_attempts" json:"max_attempts"`
+	TimeLimit    int                `bson:"time_limit" json:"time_limit"`
+	PassingScore int                `bson:"passing_score" json:"passing_score"`
+	Questions    []Question         `bson:"questions" json:"questions"`
+}
+
+type Question struct {
+	ID              primitive.ObjectID `bson:"id" json:"id"`
+	Type            string             `bson:"type" json:"type"`
+	Prompt          string             `bson:"prompt" json:"prompt"`
+	Points          int                `bson:"points" json:"points"`
+	Options         []QuestionOption   `bson:"options,omitempty" json:"options,omitempty"`
+	CorrectOptions  []string           `bson:"correct_options,omitempty" json:"correct_options,omitempty"`
+	AcceptedAnswers []string           `bson:"accepted_answers,omitempty" json:"accepted_answers,omitempty"`
+}
+
+type QuestionOption struct {
+	ID   string `bson:"id" json:"id"`
+	Text string `bson:"text" json:"text"`
+}
+
+type QuizAttempt struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	QuizID       primitive.ObjectID `bson:"quiz_id" json:"quiz_id"`
+	CourseID     primitive.ObjectID `bson:"course_id" json:"course_id"`
+	EnrollmentID primitive.ObjectID `bson:"enrollment_id" json:"enrollment_id"`
+	UserID       int                `bson:"user_id" json:"user_id"`
+	Number       int                `bson:"number,omitempty" json:"number,omitempty"` // 1 for the user's first attempt
+	Status       string             `bson:"status" json:"status"`
+	StartedAt    time.Time          `bson:"started_at" json:"started_at"`
+	Deadline     *time.Time         `bson:"deadline,omitempty" json:"deadline,omitempty"`
+	SubmittedAt  *time.Time         `bson:"submitted_at,omitempty" json:"submitted_at,omitempty"`
+	Answers      []Answer           `bson:"answers,omitempty" json:"answers,omitempty"`
+	Results      []QuestionResult   `bson:"results,omitempty" json:"results,omitempty"`
+	Score        int                `bson:"score" json:"score"`
+	MaxScore     int                `bson:"max_score" json:"max_score"`
+	Percent      int                `bson:"percent" json:"percent"`
+	Passed       bool               `bson:"passed" json:"passed"`
+}
+
+type Answer struct {
+	QuestionID primitive.ObjectID `bson:"question_id" json:"question_id"`
+	OptionIDs  []string           `bson:"option_ids,omitempty" json:"option_ids,omitempty"`
+	Text       string             `bson:"text,omitempty" json:"text,omitempty"`
+}
+
+type QuestionResult struct {
+	QuestionID primitive.ObjectID `bson:"question_id" json:"question_id"`
+	Correct    bool               `bson:"correct" json:"correct"`
+	Points     int                `bson:"points" json:"points"`
+}
+
+// QuestionStats aggregates submitted answers to one question
+type QuestionStats struct {
+	QuestionID   primitive.ObjectID `json:"question_id"`
+	Prompt       string             `json:"prompt"`
+	Answered     int                `json:"answered"`
+	Correct      int                `json:"correct"`
+	CorrectRate  float64            `json:"correct_rate"`
+	OptionCounts map[string]int     `json:"option_counts,omitempty"`
+}
+
+type QuizStats struct {
+	QuizID       primitive.ObjectID `json:"quiz_id"`
+	Attempts     int                `json:"attempts"`
+	AverageScore float64            `json:"average_percent"`
+	PassRate     float64            `json:"pass_rate"`
+	Questions    []QuestionStats    `json:"questions"`
+}
+
+// Validate checks the quiz definition and assigns IDs to new questions and options.
+func (q *Quiz) Validate() error {
+	if q.Title == "" || len(q.Questions) == 0 || q.MaxAttempts < 0 || q.TimeLimit < 0 ||
+		q.PassingScore < 0 || q.PassingScore > 100 {
+		return ErrInvalidQuiz
+	}
+
+	for i := range q.Questions {
+		question := &q.Questions[i]
+		if question.ID.IsZero() {
+			question.ID = primitive.NewObjectID()
+		}
+		if question.Points == 0 {
+			question.Points = 1
+		}
+		if question.Prompt == "" || question.Points < 0 {
+			return ErrInvalidQuestion
+		}
+
+		switch question.Type {
+		case QuestionSingleChoice, QuestionMultipleChoice:
+			if len(question.Options) < 2 {
+				return ErrInvalidQuestion
+			}
+			seen := make(map[string]bool, len(question.Options))
+			for j := range question.Options {
+				if question.Options[j].ID == "" {
+					question.Options[j].ID = strconv.Itoa(j + 1)
+				}
+				if question.Options[j].Text == "" || seen[question.Options[j].ID] {
+					return ErrInvalidQuestion
+				}
+				seen[question.Options[j].ID] = true
+			}
+			for _, id := range question.CorrectOptions {
+				if !seen[id] {
+					return ErrInvalidQuestion
+				}
+			}
+			if len(question.CorrectOptions) == 0 ||
+				(question.Type == QuestionSingleChoice && len(question.CorrectOptions) != 1) {
+				return ErrInvalidQuestion
+			}
+		case QuestionShortAnswer:
+			if len(question.AcceptedAnswers) == 0 {
+				return ErrInvalidQuestion
+			}
+		default:
+			return ErrInvalidQuestionType
+		}
+	}
+
+	return nil
+}
+
+// ForStudent returns a copy of the quiz with every answer key removed.
+func (q *Quiz) ForStudent() Quiz {
+	student := *q
+	student.Questions = make([]Question, len(q.Questions))
+	for i, question := range q.Questions {
+		question.CorrectOptions = nil
+		question.AcceptedAnswers = nil
+		student.Questions[i] = question
+	}
+	return student
+}
+
+// Grade scores the answers against the quiz's answer keys. Choice questions
+// are all-or-nothing; short answers match any accepted answer ignoring case
+// and extra whitespace.
+func (q *Quiz) Grade(answers []Answer) (results []QuestionResult, score, maxScore int) {
+	byQuestion := make(map[primitive.ObjectID]Answer, len(answers))
+	for _, answer := range answers {
+		byQuestion[answer.QuestionID] = answer
+	}
+
+	for _, question := range q.Questions {
+		maxScore += question.Points
+		answer, answered := byQuestion[question.ID]
+
+		correct := false
+		if answered {
+			switch question.Type {
+			case QuestionSingleChoice, QuestionMultipleChoice:
+				correct = sameOptions(question.CorrectOptions, answer.OptionIDs)
+			case QuestionShortAnswer:
+				given := normalizeAnswer(answer.Text)
+				for _, accepted := range question.AcceptedAnswers {
+					if given != "" && given == normalizeAnswer(accepted) {
+						correct = true
+						break
+					}
+				}
+			}
+		}
+
+		result := QuestionResult{QuestionID: question.ID, Correct: correct}
+		if correct {
+			result.Points = question.Points
+			score += question.Points
+		}
+		results = append(results, result)
+	}
+	return results, score, maxScore
+}
+
+// DedupeAnswers keeps the first answer to each of the quiz's questions and
+// drops repeated options, so resubmitted question IDs cannot skew grading
+// or statistics. Answers to unknown questions are dropped.
+func (q *Quiz) DedupeAnswers(answers []Answer) []Answer {
+	known := make(map[primitive.ObjectID]bool, len(q.Questions))
+	for _, question := range q.Questions {
+		known[question.ID] = true
+	}
+
+	deduped := make([]Answer, 0, len(answers))
+	for _, answer := range answers {
+		if !known[answer.QuestionID] {
+			continue
+		}
+		known[answer.QuestionID] = false
+
+		if answer.OptionIDs != nil {
+			seen := make(map[string]bool, len(answer.OptionIDs))
+			options := make([]string, 0, len(answer.OptionIDs))
+			for _, id := range answer.OptionIDs {
+				if !seen[id] {
+					seen[id] = true
+					options = append(options, id)
+				}
+			}
+			answer.OptionIDs = options
+		}
+		deduped = append(deduped, answer)
+	}
+	return deduped
+}
+
+func sameOptions(expected, given []string) bool {
+	if len(expected) != len(given) {
+		return false
+	}
+	remaining := make(map[string]bool, len(expected))
+	for _, id := range expected {
+		remaining[id] = true
+	}
+	for _, id := range given {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
+
+func normalizeAnswer(answer string) string {
+	return strings.ToLower(strings.Join(strings.Fields(answer), " "))
+}
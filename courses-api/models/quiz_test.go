@@ -0,0 +1,131 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTestQuiz() *Quiz {
+	return &Quiz{
+		Title: "Basics",
+		Questions: []Question{
+			{
+				Type:           QuestionSingleChoice,
+				Prompt:         "2 + 2?",
+				Options:        []QuestionOption{{Text: "3"}, {Text: "4"}},
+				CorrectOptions: []string{"2"},
+			},
+			{
+				Type:           QuestionMultipleChoice,
+				Prompt:         "Pick the primes",
+				Points:         2,
+				Options:        []QuestionOption{{ID: "a", Text: "2"}, {ID: "b", Text: "3"}, {ID: "c", Text: "4"}},
+				CorrectOptions: []string{"a", "b"},
+			},
+			{
+				Type:            QuestionShortAnswer,
+				Prompt:          "Capital of France?",
+				AcceptedAnswers: []string{"Paris"},
+			},
+		},
+	}
+}
+
+func TestQuizValidate(t *testing.T) {
+	t.Run("assigns ids and default points", func(t *testing.T) {
+		quiz := newTestQuiz()
+		assert.NoError(t, quiz.Validate())
+		assert.False(t, quiz.Questions[0].ID.IsZero())
+		assert.Equal(t, "1", quiz.Questions[0].Options[0].ID)
+		assert.Equal(t, 1, quiz.Questions[0].Points)
+	})
+
+	t.Run("single choice needs exactly one correct option", func(t *testing.T) {
+		quiz := newTestQuiz()
+		quiz.Questions[0].CorrectOptions = []string{"1", "2"}
+		assert.Equal(t, ErrInvalidQuestion, quiz.Validate())
+	})
+
+	t.Run("correct options must exist", func(t *testing.T) {
+		quiz := newTestQuiz()
+		quiz.Questions[1].CorrectOptions = []string{"z"}
+		assert.Equal(t, ErrInvalidQuestion, quiz.Validate())
+	})
+
+	t.Run("unknown question type", func(t *testing.T) {
+		quiz := newTestQuiz()
+		quiz.Questions[2].Type = "essay"
+		assert.Equal(t, ErrInvalidQuestionType, quiz.Validate())
+	})
+}
+
+func TestQuizForStudent(t *testing.T) {
+	quiz := newTestQuiz()
+	assert.NoError(t, quiz.Validate())
+
+	student := quiz.ForStudent()
+	for _, question := range student.Questions {
+		assert.Empty(t, question.CorrectOptions)
+		assert.Empty(t, question.AcceptedAnswers)
+	}
+	// The original keeps its answer keys
+	assert.NotEmpty(t, quiz.Questions[0].CorrectOptions)
+}
+
+func TestQuizGrade(t *testing.T) {
+	quiz := newTestQuiz()
+	assert.NoError(t, quiz.Validate())
+	ids := []primitive.ObjectID{quiz.Questions[0].ID, quiz.Questions[1].ID, quiz.Questions[2].ID}
+
+	t.Run("all correct", func(t *testing.T) {
+		results, score, maxScore := quiz.Grade([]Answer{
+			{QuestionID: ids[0], OptionIDs: []string{"2"}},
+			{QuestionID: ids[1], OptionIDs: []string{"b", "a"}},
+			{QuestionID: ids[2], Text: "  paris "},
+		})
+		assert.Equal(t, 4, score)
+		assert.Equal(t, 4, maxScore)
+		for _, result := range results {
+			assert.True(t, result.Correct)
+		}
+	})
+
+	t.Run("partial multiple choice earns nothing", func(t *testing.T) {
+		_, score, _ := quiz.Grade([]Answer{
+			{QuestionID: ids[1], OptionIDs: []string{"a"}},
+		})
+		assert.Equal(t, 0, score)
+	})
+
+	t.Run("unanswered questions count against the score", func(t *testing.T) {
+		results, score, maxScore := quiz.Grade([]Answer{
+			{QuestionID: ids[0], OptionIDs: []string{"2"}},
+		})
+		assert.Equal(t, 1, score)
+		assert.Equal(t, 4, maxScore)
+		assert.Len(t, results, 3)
+		assert.False(t, results[2].Correct)
+	})
+}
+
+func TestQuizDedupeAnswers(t *testing.T) {
+	quiz := newTestQuiz()
+	assert.NoError(t, quiz.Validate())
+	ids := []primitive.ObjectID{quiz.Questions[0].ID, quiz.Questions[1].ID}
+
+	answers := quiz.DedupeAnswers([]Answer{
+		{QuestionID: ids[1], OptionIDs: []string{"a", "a", "b"}},
+		{QuestionID: ids[1], OptionIDs: []string{"c"}},
+		{QuestionID: primitive.NewObjectID(), OptionIDs: []string{"2"}},
+		{QuestionID: ids[0], OptionIDs: []string{"2"}},
+	})
+
+	assert.Len(t, answers, 2)
+	assert.Equal(t, []string{"a", "b"}, answers[0].OptionIDs)
+	assert.Equal(t, ids[0], answers[1].QuestionID)
+
+	_, score, _ := quiz.Grade(answers)
+	assert.Equal(t, 3, score)
+}
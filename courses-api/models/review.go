@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Review sort orders
+const (
+	ReviewSortNewest  = "newest"
+	ReviewSortOldest  = "oldest"
+	ReviewSortHighest = "highest"
+	ReviewSortLowest  = "lowest"
+)
+
+type Review struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	CourseID  primitive.ObjectID `bson:"course_id" json:"course_id"`
+	UserID    int                `bson:"user_id" json:"user_id"`
+	Username  string             `bson:"username" json:"username"`
+	Rating    int                `bson:"rating" json:"rating"`
+	Comment   string             `bson:"comment" json:"comment"`
+	Hidden    bool               `bson:"hidden" json:"hidden"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// ReviewQuery selects a page of a course's reviews
+type ReviewQuery struct {
+	CourseID      primitive.ObjectID
+	Sort          string
+	Page          int
+	Limit         int
+	IncludeHidden bool
+}
+
+type ReviewPage struct {
+	Reviews []Review `json:"reviews"`
+	Total   int64    `json:"total"`
+	Page    int      `json:"page"`
+	Limit   int      `json:"limit"`
+}
+
+func (r *Review) Validate() error {
+	if r.Rating < 1 || r.Rating > 5 {
+		return ErrInvalidRating
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Enrollment statuses shown on a course roster
+const (
+	EnrollmentStatusNotStarted = "not_started"
+	EnrollmentStatusInProgress = "in_progress"
+	EnrollmentStatusCompleted  = "completed"
+)
+
+// Status reports how far the student has got through the course.
+func (e *Enrollment) Status() string {
+	switch {
+	case e.CompletedAt != nil:
+		return EnrollmentStatusCompleted
+	case e.LastLessonID != nil:
+		return EnrollmentStatusInProgress
+	default:
+		return EnrollmentStatusNotStarted
+	}
+}
+
+// RosterEntry is an enrolled student with their users-api account details.
+// Username and Email are empty when the account could not be looked up.
+type RosterEntry struct {
+	EnrollmentID primitive.ObjectID `json:"enrollment_id"`
+	UserID       int                `json:"user_id"`
+	Username     string             `json:"username"`
+	Email        string             `json:"email"`
+	EnrolledAt   time.Time          `json:"enrolled_at"`
+	Status       string             `json:"status"`
+	CompletedAt  *time.Time         `json:"completed_at,omitempty"`
+}
+
+type RosterPage struct {
+	Entries []RosterEntry `json:"entries"`
+	Total   int64         `json:"total"`
+	Page    int           `json:"page"`
+	Limit   int           `json:"limit"`
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxOccurrences caps how many sessions are expanded for a single course so a
+// misconfigured date range cannot produce an unbounded calendar.
+const maxOccurrences = 1000
+
+// Session is a weekly recurring class meeting, expressed in the course time zone.
+type Session struct {
+	Weekday   time.Weekday `bson:"weekday" json:"weekday"`
+	StartTime string       `bson:"start_time" json:"start_time"` // "15:04"
+	Duration  int          `bson:"duration" json:"duration"`     // minutes
+	Title     string       `bson:"title,omitempty" json:"title,omitempty"`
+}
+
+// Occurrence is a single concrete meeting of a course session.
+type Occurrence struct {
+	CourseID     primitive.ObjectID `json:"course_id"`
+	SessionIndex int                `json:"session_index"`
+	Title        string             `json:"title"`
+	Start        time.Time          `json:"start"`
+	End          time.Time          `json:"end"`
+}
+
+// Location returns the course time zone, defaulting to UTC.
+func (c *Course) Location() (*time.Location, error) {
+	if c.TimeZone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return nil, ErrInvalidTimeZone
+	}
+	return loc, nil
+}
+
+func (c *Course) validateCalendar() error {
+	if _, err := c.Location(); err != nil {
+		return err
+	}
+
+	if c.StartDate != nil && c.EndDate != nil && c.EndDate.Before(*c.StartDate) {
+		return ErrInvalidDates
+	}
+
+	if c.EnrollmentOpensAt != nil && c.EnrollmentClosesAt != nil && !c.EnrollmentClosesAt.After(*c.EnrollmentOpensAt) {
+		return ErrInvalidEnrollmentWindow
+	}
+
+	if len(c.Sessions) > 0 && (c.StartDate == nil || c.EndDate == nil) {
+		return ErrInvalidSession
+	}
+	for _, session := range c.Sessions {
+		if session.Weekday < time.Sunday || session.Weekday > time.Saturday || session.Duration <= 0 {
+			return ErrInvalidSession
+		}
+		if _, err := time.Parse("15:04", session.StartTime); err != nil {
+			return ErrInvalidSession
+		}
+	}
+	return nil
+}
+
+// CheckEnrollmentWindow reports whether enrollment is open at the given time.
+// Courses without a window are always open.
+func (c *Course) CheckEnrollmentWindow(now time.Time) error {
+	if c.EnrollmentOpensAt != nil && now.Before(*c.EnrollmentOpensAt) {
+		return ErrEnrollmentNotOpen
+	}
+	if c.EnrollmentClosesAt != nil && !now.Before(*c.EnrollmentClosesAt) {
+		return ErrEnrollmentClosed
+	}
+	return nil
+}
+
+// Occurrences expands the weekly sessions into concrete meetings between the
+// start and end dates (inclusive), using the wall clock of the course time
+// zone so that meetings keep their local time across daylight saving changes.
+func (c *Course) Occurrences() ([]Occurrence, error) {
+	if len(c.Sessions) == 0 || c.StartDate == nil || c.EndDate == nil {
+		return nil, nil
+	}
+	loc, err := c.Location()
+	if err != nil {
+		return nil, err
+	}
+
+	start := c.StartDate.In(loc)
+	end := c.EndDate.In(loc)
+	first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	last := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, loc)
+
+	var occurrences []Occurrence
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		for i, session := range c.Sessions {
+			if session.Weekday != day.Weekday() {
+				continue
+			}
+			clock, err := time.Parse("15:04", session.StartTime)
+			if err != nil {
+				return nil, ErrInvalidSession
+			}
+
+			begin := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+			title := c.Title
+			if session.Title != "" {
+				title = c.Title + ": " + session.Title
+			}
+			occurrences = append(occurrences, Occurrence{
+				CourseID:     c.ID,
+				SessionIndex: i,
+				Title:        title,
+				Start:        begin,
+				End:          begin.Add(time.Duration(session.Duration) * time.Minute),
+			})
+			if len(occurrences) >= maxOccurrences {
+				return occurrences, nil
+			}
+		}
+	}
+	return occurrences, nil
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func scheduledCourse() Course {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	return Course{
+		Title:     "Go",
+		StartDate: &start,
+		EndDate:   &end,
+		TimeZone:  "America/New_York",
+		Sessions: []Session{
+			{Weekday: time.Monday, StartTime: "18:30", Duration: 90},
+		},
+	}
+}
+
+func TestOccurrencesKeepLocalTimeAcrossDST(t *testing.T) {
+	course := scheduledCourse()
+
+	occurrences, err := course.Occurrences()
+	assert.NoError(t, err)
+	assert.Len(t, occurrences, 2)
+
+	// US daylight saving time starts on 2024-03-10
+	assert.Equal(t, time.Date(2024, 3, 4, 23, 30, 0, 0, time.UTC), occurrences[0].Start.UTC())
+	assert.Equal(t, time.Date(2024, 3, 11, 22, 30, 0, 0, time.UTC), occurrences[1].Start.UTC())
+	assert.Equal(t, 90*time.Minute, occurrences[1].End.Sub(occurrences[1].Start))
+}
+
+func TestValidateCalendar(t *testing.T) {
+	course := scheduledCourse()
+	assert.NoError(t, course.validateCalendar())
+
+	course.TimeZone = "Mars/Olympus"
+	assert.Equal(t, ErrInvalidTimeZone, course.validateCalendar())
+
+	course = scheduledCourse()
+	course.Sessions[0].StartTime = "25:00"
+	assert.Equal(t, ErrInvalidSession, course.validateCalendar())
+
+	course = scheduledCourse()
+	course.EndDate = nil
+	assert.Equal(t, ErrInvalidSession, course.validateCalendar())
+}
+
+func TestCheckEnrollmentWindow(t *testing.T) {
+	opens := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	closes := time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)
+	course := Course{EnrollmentOpensAt: &opens, EnrollmentClosesAt: &closes}
+
+	assert.Equal(t, ErrEnrollmentNotOpen, course.CheckEnrollmentWindow(opens.Add(-time.Second)))
+	assert.NoError(t, course.CheckEnrollmentWindow(opens))
+	assert.Equal(t, ErrEnrollmentClosed, course.CheckEnrollmentWindow(closes))
+	assert.NoError(t, (&Course{}).CheckEnrollmentWindow(time.Now()))
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Seat hold durations, in minutes
+const (
+	DefaultHoldMinutes = 10
+	MaxHoldMinutes     = 30
+)
+
+// Seat hold states
+const (
+	HoldActive    = "active"
+	HoldConfirmed = "confirmed"
+	HoldReleased  = "released"
+	HoldExpired   = "expired"
+)
+
+// SeatHold keeps a seat aside for a user during checkout without consuming it.
+type SeatHold struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	CourseID  primitive.ObjectID `bson:"course_id" json:"course_id"`
+	UserID    int                `bson:"user_id" json:"user_id"`
+	Status    string             `bson:"status" json:"status"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+}
+
+// SeatAvailability reports a course's seats. Held seats are still counted in
+// AvailableSeats; OpenSeats is what can be taken right now.
+type SeatAvailability struct {
+	AvailableSeats int `json:"available_seats"`
+	HeldSeats      int `json:"held_seats"`
+	OpenSeats      int `json:"open_seats"`
+}
+
+// IsActive reports whether the hold still reserves its seat.
+func (h *SeatHold) IsActive(now time.Time) bool {
+	return h.Status == HoldActive && now.Before(h.ExpiresAt)
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"strings"
+)
+
+const (
+	MaxCourseTags = 20
+	maxTagLength  = 40
+)
+
+// TagCount is an existing tag and the number of courses using it.
+type TagCount struct {
+	Tag   string `bson:"_id" json:"tag"`
+	Count int    `bson:"count" json:"count"`
+}
+
+// NormalizeTag lower-cases a tag and joins its words with dashes, so
+// "Machine Learning" and "machine-learning" are the same tag.
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+}
+
+// normalizeTags normalizes and de-duplicates the course's tags, keeping
+// their order. Letters, digits and "-+#." are allowed, as in "c++" or "node.js".
+func (c *Course) normalizeTags() error {
+	if len(c.Tags) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(c.Tags))
+	tags := make([]string, 0, len(c.Tags))
+	for _, tag := range c.Tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || len(tag) > maxTagLength || !isTagText(tag) {
+			return ErrInvalidTags
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > MaxCourseTags {
+		return ErrInvalidTags
+	}
+	c.Tags = tags
+	return nil
+}
+
+func isTagText(tag string) bool {
+	for _, r := range tag {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || strings.ContainsRune("-+#.", r) || r > 127) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	course := Course{Tags: []string{" Machine  Learning ", "machine-learning", "C++", "Node.js"}}
+	assert.NoError(t, course.normalizeTags())
+	assert.Equal(t, []string{"machine-learning", "c++", "node.js"}, course.Tags)
+}
+
+func TestNormalizeTagsRejectsInvalidTags(t *testing.T) {
+	assert.Equal(t, ErrInvalidTags, (&Course{Tags: []string{"  "}}).normalizeTags())
+	assert.Equal(t, ErrInvalidTags, (&Course{Tags: []string{"drop;table"}}).normalizeTags())
+	assert.Equal(t, ErrInvalidTags, (&Course{Tags: []string{strings.Repeat("a", 41)}}).normalizeTags())
+
+	tags := make([]string, MaxCourseTags+1)
+	for i := range tags {
+		tags[i] = strings.Repeat("t", i+1)
+	}
+	assert.Equal(t, ErrInvalidTags, (&Course{Tags: tags}).normalizeTags())
+}
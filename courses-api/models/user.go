@@ -0,0 +1,8 @@
+package models
+
+// User is the subset of a users-api account that courses-api relies on
+type User struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WaitlistClaimWindow is how long a promoted user has to enroll before the
+// seat is offered to the next person in line.
+const WaitlistClaimWindow = 24 * time.Hour
+
+// Waitlist entry states
+const (
+	WaitlistWaiting   = "waiting"
+	WaitlistOffered   = "offered"
+	WaitlistClaimed   = "claimed"
+	WaitlistExpired   = "expired"
+	WaitlistCancelled = "cancelled"
+)
+
+type WaitlistEntry struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	CourseID       primitive.ObjectID `bson:"course_id" json:"course_id"`
+	UserID         int                `bson:"user_id" json:"user_id"`
+	Status         string             `bson:"status" json:"status"`
+	JoinedAt       time.Time          `bson:"joined_at" json:"joined_at"`
+	OfferedAt      *time.Time         `bson:"offered_at,omitempty" json:"offered_at,omitempty"`
+	OfferExpiresAt *time.Time         `bson:"offer_expires_at,omitempty" json:"offer_expires_at,omitempty"`
+}
+
+// WaitlistPosition is what a user sees about their own place in line.
+// Position is 1-based and zero once a seat has been offered.
+type WaitlistPosition struct {
+	CourseID       primitive.ObjectID `json:"course_id"`
+	Status         string             `json:"status"`
+	Position       int                `json:"position"`
+	OfferExpiresAt *time.Time         `json:"offer_expires_at,omitempty"`
+}
+
+// HasOpenOffer reports whether the entry holds a seat that can still be claimed.
+func (e *WaitlistEntry) HasOpenOffer(now time.Time) bool {
+	return e.Status == WaitlistOffered && e.OfferExpiresAt != nil && now.Before(*e.OfferExpiresAt)
+}
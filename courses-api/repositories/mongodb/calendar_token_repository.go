@@ -0,0 +1,84 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CalendarTokenRepository struct {
+	db *mongo.Database
+}
+
+func NewCalendarTokenRepository(db *mongo.Database) *CalendarTokenRepository {
+	return &CalendarTokenRepository{db: db}
+}
+
+func (r *CalendarTokenRepository) collection() *mongo.Collection {
+	return r.db.Collection("calendar_tokens")
+}
+
+// EnsureIndexes creates the unique user_id index that gives each user one
+// feed, and the unique token index that feed requests are looked up by.
+func (r *CalendarTokenRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "token", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+	return err
+}
+
+func (r *CalendarTokenRepository) FindByUserID(ctx context.Context, userID int) (*models.CalendarToken, error) {
+	return r.findOne(ctx, bson.M{"user_id": userID})
+}
+
+func (r *CalendarTokenRepository) FindByToken(ctx context.Context, token string) (*models.CalendarToken, error) {
+	return r.findOne(ctx, bson.M{"token": token})
+}
+
+func (r *CalendarTokenRepository) findOne(ctx context.Context, filter bson.M) (*models.CalendarToken, error) {
+	var token models.CalendarToken
+	err := r.collection().FindOne(ctx, filter).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrCalendarTokenNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &token, nil
+}
+
+// Create stores the user's first token. A user who already has one gets
+// ErrCalendarTokenExists.
+func (r *CalendarTokenRepository) Create(ctx context.Context, token *models.CalendarToken) error {
+	if _, err := r.collection().InsertOne(ctx, token); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.ErrCalendarTokenExists
+		}
+		return models.ErrDatabaseOperation
+	}
+	return nil
+}
+
+// Save stores the user's token, replacing any previous one so a rotated token
+// immediately invalidates the old feed URL.
+func (r *CalendarTokenRepository) Save(ctx context.Context, token *models.CalendarToken) error {
+	filter := bson.M{"user_id": token.UserID}
+	update := bson.M{"$set": bson.M{
+		"token":      token.Token,
+		"created_at": token.CreatedAt,
+	}}
+	opts := options.Update().SetUpsert(true)
+	_, err := r.collection().UpdateOne(ctx, filter, update, opts)
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent save inserted the user's token first; replace it
+		_, err = r.collection().UpdateOne(ctx, filter, update, opts)
+	}
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CategoryRepository struct {
+	db *mongo.Database
+}
+
+func NewCategoryRepository(db *mongo.Database) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+func (r *CategoryRepository) collection() *mongo.Collection {
+	return r.db.Collection("categories")
+}
+
+// EnsureIndexes makes slugs unique and seeds the default categories into an
+// empty collection. Seeding is safe to run from several replicas at once.
+func (r *CategoryRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "slug", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	count, err := r.collection().CountDocuments(ctx, bson.M{})
+	if err != nil || count > 0 {
+		return err
+	}
+	now := time.Now()
+	for _, category := range models.DefaultCategories {
+		category.CreatedAt = now
+		category.UpdatedAt = now
+		_, err := r.collection().UpdateOne(ctx,
+			bson.M{"slug": category.Slug},
+			bson.M{"$setOnInsert": category},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	result, err := r.collection().InsertOne(ctx, category)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.ErrDuplicateCategory
+		}
+		return models.ErrDatabaseOperation
+	}
+	category.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *CategoryRepository) FindAll(ctx context.Context) ([]models.Category, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "display_name", Value: 1}})
+	cursor, err := r.collection().Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	categories := []models.Category{}
+	if err = cursor.All(ctx, &categories); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return categories, nil
+}
+
+func (r *CategoryRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Category, error) {
+	var category models.Category
+	err := r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&category)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrCategoryNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &category, nil
+}
+
+func (r *CategoryRepository) Update(ctx context.Context, category *models.Category) error {
+	update := bson.M{"$set": bson.M{
+		"slug":         category.Slug,
+		"display_name": category.DisplayName,
+		"icon":         category.Icon,
+		"parent_id":    category.ParentID,
+		"updated_at":   category.UpdatedAt,
+	}}
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": category.ID}, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.ErrDuplicateCategory
+		}
+		return txError(err)
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrCategoryNotFound
+	}
+	return nil
+}
+
+func (r *CategoryRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection().DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.DeletedCount == 0 {
+		return models.ErrCategoryNotFound
+	}
+	return nil
+}
+
+// Slugs lists every category slug; it backs the category cache.
+func (r *CategoryRepository) Slugs(ctx context.Context) ([]string, error) {
+	values, err := r.collection().Distinct(ctx, "slug", bson.M{})
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	slugs := make([]string, 0, len(values))
+	for _, v := range values {
+		if slug, ok := v.(string); ok {
+			slugs = append(slugs, slug)
+		}
+	}
+	return slugs, nil
+}
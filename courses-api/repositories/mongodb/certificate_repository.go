@@ -0,0 +1,85 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CertificateRepository struct {
+	db *mongo.Database
+}
+
+func NewCertificateRepository(db *mongo.Database) *CertificateRepository {
+	return &CertificateRepository{db: db}
+}
+
+func (r *CertificateRepository) collection() *mongo.Collection {
+	return r.db.Collection("certificates")
+}
+
+// EnsureIndexes creates the unique indexes that allow one certificate per
+// enrollment and keep verification codes unique.
+func (r *CertificateRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "enrollment_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "code", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	return err
+}
+
+func (r *CertificateRepository) Create(ctx context.Context, certificate *models.Certificate) error {
+	result, err := r.collection().InsertOne(ctx, certificate)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.ErrCertificateExists
+		}
+		return models.ErrDatabaseOperation
+	}
+	certificate.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *CertificateRepository) FindByCode(ctx context.Context, code string) (*models.Certificate, error) {
+	return r.findOne(ctx, bson.M{"code": code})
+}
+
+func (r *CertificateRepository) FindByEnrollmentID(ctx context.Context, enrollmentID primitive.ObjectID) (*models.Certificate, error) {
+	return r.findOne(ctx, bson.M{"enrollment_id": enrollmentID})
+}
+
+func (r *CertificateRepository) findOne(ctx context.Context, filter bson.M) (*models.Certificate, error) {
+	var certificate models.Certificate
+	err := r.collection().FindOne(ctx, filter).Decode(&certificate)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrCertificateNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &certificate, nil
+}
+
+func (r *CertificateRepository) FindByUserID(ctx context.Context, userID int) ([]models.Certificate, error) {
+	cursor, err := r.collection().Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	var certificates []models.Certificate
+	if err = cursor.All(ctx, &certificates); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return certificates, nil
+}
@@ -0,0 +1,226 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CouponRepository struct {
+	db *mongo.Database
+}
+
+func NewCouponRepository(db *mongo.Database) *CouponRepository {
+	return &CouponRepository{db: db}
+}
+
+func (r *CouponRepository) collection() *mongo.Collection {
+	return r.db.Collection("coupons")
+}
+
+// usages holds one counter per coupon and user for per-user limits.
+func (r *CouponRepository) usages() *mongo.Collection {
+	return r.db.Collection("coupon_usages")
+}
+
+// EnsureIndexes makes coupon codes unique and keeps a single usage counter
+// per coupon and user, which Redeem relies on to enforce per-user limits.
+func (r *CouponRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "code", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.usages().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "coupon_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func (r *CouponRepository) Create(ctx context.Context, coupon *models.Coupon) error {
+	result, err := r.collection().InsertOne(ctx, coupon)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.ErrDuplicateCoupon
+		}
+		return models.ErrDatabaseOperation
+	}
+	coupon.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *CouponRepository) FindAll(ctx context.Context) ([]models.Coupon, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection().Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	var coupons []models.Coupon
+	if err = cursor.All(ctx, &coupons); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return coupons, nil
+}
+
+func (r *CouponRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Coupon, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *CouponRepository) FindByCode(ctx context.Context, code string) (*models.Coupon, error) {
+	return r.findOne(ctx, bson.M{"code": code})
+}
+
+func (r *CouponRepository) findOne(ctx context.Context, filter bson.M) (*models.Coupon, error) {
+	var coupon models.Coupon
+	err := r.collection().FindOne(ctx, filter).Decode(&coupon)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrCouponNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &coupon, nil
+}
+
+// Update replaces the coupon's settings; the redemption counter is left
+// alone because only Redeem and Release may change it.
+func (r *CouponRepository) Update(ctx context.Context, coupon *models.Coupon) error {
+	update := bson.M{"$set": bson.M{
+		"code":            coupon.Code,
+		"type":            coupon.Type,
+		"value":           coupon.Value,
+		"currency":        coupon.Currency,
+		"expires_at":      coupon.ExpiresAt,
+		"max_redemptions": coupon.MaxRedemptions,
+		"per_user_limit":  coupon.PerUserLimit,
+		"course_ids":      coupon.CourseIDs,
+		"categories":      coupon.Categories,
+		"active":          coupon.Active,
+	}}
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": coupon.ID}, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.ErrDuplicateCoupon
+		}
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrCouponNotFound
+	}
+	return nil
+}
+
+func (r *CouponRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection().DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.DeletedCount == 0 {
+		return models.ErrCouponNotFound
+	}
+	if _, err := r.usages().DeleteMany(ctx, bson.M{"coupon_id": id}); err != nil {
+		return models.ErrDatabaseOperation
+	}
+	return nil
+}
+
+func (r *CouponRepository) CountUses(ctx context.Context, couponID primitive.ObjectID, userID int) (int, error) {
+	var usage struct {
+		Count int `bson:"count"`
+	}
+	err := r.usages().FindOne(ctx, bson.M{"coupon_id": couponID, "user_id": userID}).Decode(&usage)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil
+		}
+		return 0, models.ErrDatabaseOperation
+	}
+	return usage.Count, nil
+}
+
+// Redeem takes one redemption of the coupon for the user. Both counters are
+// guarded in the update filters, so concurrent checkouts cannot exceed the
+// global or per-user limits; it should run in a transaction so a failed
+// per-user check also undoes the global increment.
+func (r *CouponRepository) Redeem(ctx context.Context, coupon *models.Coupon, userID int, now time.Time) error {
+	filter := bson.M{
+		"_id":    coupon.ID,
+		"active": true,
+		"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"expires_at": nil},
+				bson.M{"expires_at": bson.M{"$gt": now}},
+			}},
+			bson.M{"$or": bson.A{
+				bson.M{"max_redemptions": 0},
+				bson.M{"$expr": bson.M{"$lt": bson.A{"$redemptions", "$max_redemptions"}}},
+			}},
+		},
+	}
+	result, err := r.collection().UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"redemptions": 1}})
+	if err != nil {
+		return txError(err)
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrCouponExhausted
+	}
+
+	usageFilter := bson.M{"coupon_id": coupon.ID, "user_id": userID}
+	if coupon.PerUserLimit > 0 {
+		// At the limit the filter no longer matches, and the upsert collides
+		// with the existing counter on the unique index
+		usageFilter["count"] = bson.M{"$lt": coupon.PerUserLimit}
+	}
+	_, err = r.usages().UpdateOne(ctx, usageFilter,
+		bson.M{"$inc": bson.M{"count": 1}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.ErrCouponLimitReached
+		}
+		return txError(err)
+	}
+	return nil
+}
+
+// Release gives back a redemption taken by an order that did not complete.
+func (r *CouponRepository) Release(ctx context.Context, couponID primitive.ObjectID, userID int) error {
+	_, err := r.collection().UpdateOne(ctx,
+		bson.M{"_id": couponID, "redemptions": bson.M{"$gt": 0}},
+		bson.M{"$inc": bson.M{"redemptions": -1}},
+	)
+	if err != nil {
+		return txError(err)
+	}
+	_, err = r.usages().UpdateOne(ctx,
+		bson.M{"coupon_id": couponID, "user_id": userID, "count": bson.M{"$gt": 0}},
+		bson.M{"$inc": bson.M{"count": -1}},
+	)
+	if err != nil {
+		return txError(err)
+	}
+	return nil
+}
+
+// RenameCategory keeps coupon category restrictions pointing at a renamed category.
+func (r *CouponRepository) RenameCategory(ctx context.Context, from, to string) error {
+	_, err := r.collection().UpdateMany(ctx,
+		bson.M{"categories": from},
+		bson.M{"$set": bson.M{"categories.$": to}},
+	)
+	if err != nil {
+		return txError(err)
+	}
+	return nil
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"courses-api/models"
 	"errors"
+	"regexp"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type CourseRepository struct {
@@ -22,6 +25,16 @@ func (r *CourseRepository) collection() *mongo.Collection {
 	return r.db.Collection("courses")
 }
 
+// EnsureIndexes makes external keys unique among the courses that have one.
+func (r *CourseRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "external_key", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"external_key": bson.M{"$type": "string"}}),
+	})
+	return err
+}
+
 func (r *CourseRepository) Create(ctx context.Context, course *models.Course) error {
 	// Check for duplicates
 	filter := bson.M{"title": course.Title, "instructor": course.Instructor}
@@ -30,7 +43,7 @@ func (r *CourseRepository) Create(ctx context.Context, course *models.Course) er
 		return err
 	}
 	if count > 0 {
-		return errors.New("a course with the same title and instructor already exists")
+		return models.ErrDuplicateCourse
 	}
 
 	result, err := r.collection().InsertOne(ctx, course)
@@ -67,19 +80,110 @@ func (r *CourseRepository) FindByID(ctx context.Context, id primitive.ObjectID)
 	return &course, nil
 }
 
-func (r *CourseRepository) Update(ctx context.Context, course *models.Course) error {
-	filter := bson.M{"_id": course.ID}
-	update := bson.M{"$set": course}
-	result, err := r.collection().UpdateOne(ctx, filter, update)
+func (r *CourseRepository) FindByExternalKey(ctx context.Context, key string) (*models.Course, error) {
+	var course models.Course
+	err := r.collection().FindOne(ctx, bson.M{"external_key": key}).Decode(&course)
 	if err != nil {
-		return err
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrCourseNotFound
+		}
+		return nil, models.ErrDatabaseOperation
 	}
-	if result.MatchedCount == 0 {
-		return errors.New("course not found")
+	return &course, nil
+}
+
+// ForEach calls fn with every course in creation order without loading the
+// whole catalog into memory, stopping at the first error fn returns.
+func (r *CourseRepository) ForEach(ctx context.Context, fn func(models.Course) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := r.collection().Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var course models.Course
+		if err := cursor.Decode(&course); err != nil {
+			return models.ErrDatabaseOperation
+		}
+		if err := fn(course); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return models.ErrDatabaseOperation
 	}
 	return nil
 }
 
+// UpdateWithSeats saves the course and adds seatDelta to its seats in one
+// write, so capacity edits add to or take from whatever concurrent
+// enrollments left rather than overwriting it. Seats are only removed while
+// at least as many remain open besides the held ones.
+func (r *CourseRepository) UpdateWithSeats(ctx context.Context, course *models.Course, seatDelta int) (*models.Course, error) {
+	filter := bson.M{"_id": course.ID}
+	if seatDelta < 0 {
+		filter["$expr"] = seatsExceed(-seatDelta - 1)
+	}
+	update, err := courseUpdate(course)
+	if err != nil {
+		return nil, err
+	}
+	if seatDelta != 0 {
+		update["$inc"] = bson.M{"available_seats": seatDelta}
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated models.Course
+	err = r.collection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		if seatDelta < 0 {
+			if _, err := r.FindByID(ctx, course.ID); err == nil {
+				return nil, models.ErrSeatsInUse
+			}
+		}
+		return nil, errors.New("course not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// courseUpdate builds the $set for a full course update, leaving out the
+// seat counters, which only the atomic seat and offer operations may change, the
+// rating summary, which only UpdateRating refreshes, and the uploaded image
+// keys, which only SetImage records.
+func courseUpdate(course *models.Course) (bson.M, error) {
+	data, err := bson.Marshal(course)
+	if err != nil {
+		return nil, err
+	}
+	var set bson.M
+	if err := bson.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	delete(set, "available_seats")
+	delete(set, "held_seats")
+	delete(set, "offered_seats")
+	delete(set, "average_rating")
+	delete(set, "review_count")
+	delete(set, "image_keys")
+	update := bson.M{"$set": set}
+	// Emptied lists are omitted from the document, so clear them explicitly
+	unset := bson.M{}
+	for _, field := range []string{"prerequisites", "instructor_ids", "tags", "thumbnail_url", "translations"} {
+		if _, ok := set[field]; !ok {
+			unset[field] = ""
+		}
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return update, nil
+}
+
 func (r *CourseRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
 	result, err := r.collection().DeleteOne(ctx, bson.M{"_id": id})
 	if err != nil {
@@ -91,7 +195,7 @@ func (r *CourseRepository) Delete(ctx context.Context, id primitive.ObjectID) er
 	return nil
 }
 
-func (r *CourseRepository) CheckAvailability(ctx context.Context, courseIDs []primitive.ObjectID) (map[string]int, error) {
+func (r *CourseRepository) CheckAvailability(ctx context.Context, courseIDs []primitive.ObjectID) (map[string]models.SeatAvailability, error) {
 	filter := bson.M{
 		"_id": bson.M{"$in": courseIDs},
 		"available_seats": bson.M{"$gt": 0},
@@ -103,13 +207,17 @@ func (r *CourseRepository) CheckAvailability(ctx context.Context, courseIDs []pr
 	}
 	defer cursor.Close(ctx)
 
-	availability := make(map[string]int)
-	var course models.Course
+	availability := make(map[string]models.SeatAvailability)
 	for cursor.Next(ctx) {
+		var course models.Course
 		if err := cursor.Decode(&course); err != nil {
 			return nil, err
 		}
-		availability[course.ID.Hex()] = course.AvailableSeats
+		availability[course.ID.Hex()] = models.SeatAvailability{
+			AvailableSeats: course.AvailableSeats,
+			HeldSeats:      course.HeldSeats,
+			OpenSeats:      course.OpenSeats(),
+		}
 	}
 
 	return availability, nil
@@ -128,4 +236,390 @@ func (r *CourseRepository) FindByIDs(ctx context.Context, ids []primitive.Object
 		return nil, models.ErrDatabaseOperation
 	}
 	return courses, nil
-}
\ No newline at end of file
+}
+
+// publishedFilter matches published courses, including legacy courses stored
+// without a status.
+func publishedFilter() bson.M {
+	return bson.M{"status": bson.M{"$in": bson.A{models.CourseStatusPublished, nil}}}
+}
+
+func (r *CourseRepository) FindPublished(ctx context.Context) ([]models.Course, error) {
+	cursor, err := r.collection().Find(ctx, publishedFilter())
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	var courses []models.Course
+	if err = cursor.All(ctx, &courses); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return courses, nil
+}
+
+// FindByInstructorID returns the courses the user teaches, optionally
+// including drafts and archived ones.
+func (r *CourseRepository) FindByInstructorID(ctx context.Context, userID int, includeUnpublished bool) ([]models.Course, error) {
+	filter := bson.M{}
+	if !includeUnpublished {
+		filter = publishedFilter()
+	}
+	filter["instructor_ids"] = userID
+	cursor, err := r.collection().Find(ctx, filter)
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	courses := []models.Course{}
+	if err = cursor.All(ctx, &courses); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return courses, nil
+}
+
+func (r *CourseRepository) FindByCategory(ctx context.Context, slug string) ([]models.Course, error) {
+	cursor, err := r.collection().Find(ctx, bson.M{"category": slug})
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	var courses []models.Course
+	if err = cursor.All(ctx, &courses); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return courses, nil
+}
+
+// FindByTags returns the courses that have every one of the given tags.
+func (r *CourseRepository) FindByTags(ctx context.Context, tags []string, includeUnpublished bool) ([]models.Course, error) {
+	filter := bson.M{}
+	if !includeUnpublished {
+		filter = publishedFilter()
+	}
+	filter["tags"] = bson.M{"$all": tags}
+
+	cursor, err := r.collection().Find(ctx, filter)
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	courses := []models.Course{}
+	if err = cursor.All(ctx, &courses); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return courses, nil
+}
+
+// SuggestTags returns the most used tags of published courses that start
+// with prefix.
+func (r *CourseRepository) SuggestTags(ctx context.Context, prefix string, limit int) ([]models.TagCount, error) {
+	match := bson.M{"tags": bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: publishedFilter()}},
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+	cursor, err := r.collection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	tags := []models.TagCount{}
+	if err = cursor.All(ctx, &tags); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return tags, nil
+}
+
+func (r *CourseRepository) CountByCategory(ctx context.Context, slug string) (int64, error) {
+	count, err := r.collection().CountDocuments(ctx, bson.M{"category": slug})
+	if err != nil {
+		return 0, models.ErrDatabaseOperation
+	}
+	return count, nil
+}
+
+// RenameCategory moves every course from one category slug to another.
+func (r *CourseRepository) RenameCategory(ctx context.Context, from, to string) error {
+	_, err := r.collection().UpdateMany(ctx, bson.M{"category": from}, bson.M{"$set": bson.M{"category": to}})
+	if err != nil {
+		return txError(err)
+	}
+	return nil
+}
+
+// UpdateStatus moves a course from one status to another. The update only
+// applies if the course is still in the expected status, so concurrent
+// transitions (e.g. the scheduler running on several replicas) apply once.
+func (r *CourseRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, from, to string) error {
+	filter := bson.M{"_id": id, "status": from}
+	if from == models.CourseStatusPublished {
+		filter = publishedFilter()
+		filter["_id"] = id
+	}
+
+	// Consume the schedule entry that the transition fulfils
+	unset := bson.M{"unpublish_at": ""}
+	if to == models.CourseStatusPublished {
+		unset = bson.M{"publish_at": ""}
+	}
+
+	update := bson.M{
+		"$set":   bson.M{"status": to},
+		"$unset": unset,
+	}
+	result, err := r.collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrInvalidTransition
+	}
+	return nil
+}
+
+func (r *CourseRepository) UpdateSchedule(ctx context.Context, id primitive.ObjectID, publishAt, unpublishAt *time.Time) error {
+	set := bson.M{}
+	unset := bson.M{}
+	if publishAt != nil {
+		set["publish_at"] = publishAt
+	} else {
+		unset["publish_at"] = ""
+	}
+	if unpublishAt != nil {
+		set["unpublish_at"] = unpublishAt
+	} else {
+		unset["unpublish_at"] = ""
+	}
+
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrCourseNotFound
+	}
+	return nil
+}
+
+// FindScheduleDue returns draft courses whose publish time has passed and
+// published courses whose unpublish time has passed.
+func (r *CourseRepository) FindScheduleDue(ctx context.Context, now time.Time) ([]models.Course, error) {
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": models.CourseStatusDraft, "publish_at": bson.M{"$lte": now}},
+			bson.M{"status": models.CourseStatusPublished, "unpublish_at": bson.M{"$lte": now}},
+		},
+	}
+	cursor, err := r.collection().Find(ctx, filter)
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	var courses []models.Course
+	if err = cursor.All(ctx, &courses); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return courses, nil
+}
+
+// UpdateRating stores the review aggregates without touching other fields.
+func (r *CourseRepository) UpdateRating(ctx context.Context, id primitive.ObjectID, average float64, count int) error {
+	update := bson.M{"$set": bson.M{"average_rating": average, "review_count": count}}
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrCourseNotFound
+	}
+	return nil
+}
+
+// SetImage stores the URLs and blob keys of a newly uploaded image and
+// returns the keys of the image it replaced.
+func (r *CourseRepository) SetImage(ctx context.Context, id primitive.ObjectID, imageURL, thumbnailURL string, keys []string) ([]string, error) {
+	update := bson.M{"$set": bson.M{"image_url": imageURL, "thumbnail_url": thumbnailURL, "image_keys": keys}}
+	if len(keys) == 0 {
+		update = bson.M{
+			"$set":   bson.M{"image_url": imageURL},
+			"$unset": bson.M{"thumbnail_url": "", "image_keys": ""},
+		}
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+	var previous models.Course
+	err := r.collection().FindOneAndUpdate(ctx, bson.M{"_id": id}, update, opts).Decode(&previous)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrCourseNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return previous.ImageKeys, nil
+}
+
+// CountByImageKey counts the courses that use an uploaded image blob, which
+// clones share with the course they were copied from.
+func (r *CourseRepository) CountByImageKey(ctx context.Context, key string) (int64, error) {
+	count, err := r.collection().CountDocuments(ctx, bson.M{"image_keys": key})
+	if err != nil {
+		return 0, models.ErrDatabaseOperation
+	}
+	return count, nil
+}
+
+// IncrementSeats atomically adjusts the available seats and returns the
+// updated course.
+func (r *CourseRepository) IncrementSeats(ctx context.Context, id primitive.ObjectID, delta int) (*models.Course, error) {
+	update := bson.M{"$inc": bson.M{"available_seats": delta}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var course models.Course
+	err := r.collection().FindOneAndUpdate(ctx, bson.M{"_id": id}, update, opts).Decode(&course)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrCourseNotFound
+		}
+		return nil, txError(err)
+	}
+	return &course, nil
+}
+
+// ReserveSeat takes one seat only if more than reserved seats remain besides
+// the held ones, so concurrent enrollments can never take seats held for
+// checkout or offered to waitlisted users.
+func (r *CourseRepository) ReserveSeat(ctx context.Context, id primitive.ObjectID, reserved int) (*models.Course, error) {
+	filter := bson.M{"_id": id, "$expr": seatsExceed(reserved)}
+	update := bson.M{"$inc": bson.M{"available_seats": -1}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var course models.Course
+	err := r.collection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&course)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrNoAvailableSeats
+		}
+		return nil, txError(err)
+	}
+	return &course, nil
+}
+
+// ReserveSeats takes count seats at once under the same rule as ReserveSeat,
+// so either all of them are taken or none.
+func (r *CourseRepository) ReserveSeats(ctx context.Context, id primitive.ObjectID, count, reserved int) (*models.Course, error) {
+	filter := bson.M{"_id": id, "$expr": seatsExceed(reserved + count - 1)}
+	update := bson.M{"$inc": bson.M{"available_seats": -count}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var course models.Course
+	err := r.collection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&course)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrNoAvailableSeats
+		}
+		return nil, txError(err)
+	}
+	return &course, nil
+}
+
+// seatsExceed matches courses with more than reserved seats that are
+// neither taken nor held.
+func seatsExceed(reserved int) bson.M {
+	return bson.M{"$gt": bson.A{
+		"$available_seats",
+		bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$held_seats", 0}}, reserved}},
+	}}
+}
+
+// HoldSeat sets one open seat aside without consuming it.
+func (r *CourseRepository) HoldSeat(ctx context.Context, id primitive.ObjectID, reserved int) error {
+	filter := bson.M{"_id": id, "$expr": seatsExceed(reserved)}
+	update := bson.M{"$inc": bson.M{"held_seats": 1}}
+	result, err := r.collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return txError(err)
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrNoAvailableSeats
+	}
+	return nil
+}
+
+// ReleaseHeldSeat returns a held seat to the open pool.
+func (r *CourseRepository) ReleaseHeldSeat(ctx context.Context, id primitive.ObjectID) error {
+	filter := bson.M{"_id": id, "held_seats": bson.M{"$gt": 0}}
+	update := bson.M{"$inc": bson.M{"held_seats": -1}}
+	if _, err := r.collection().UpdateOne(ctx, filter, update); err != nil {
+		return txError(err)
+	}
+	return nil
+}
+
+// ConsumeHeldSeat turns a held seat into a taken one.
+func (r *CourseRepository) ConsumeHeldSeat(ctx context.Context, id primitive.ObjectID) (*models.Course, error) {
+	filter := bson.M{"_id": id, "available_seats": bson.M{"$gt": 0}, "held_seats": bson.M{"$gt": 0}}
+	update := bson.M{"$inc": bson.M{"available_seats": -1, "held_seats": -1}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var course models.Course
+	err := r.collection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&course)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrNoAvailableSeats
+		}
+		return nil, txError(err)
+	}
+	return &course, nil
+}
+
+// OfferSeat sets one seat aside for a waitlist offer if a seat remains that
+// is neither taken, held nor already offered, so concurrent promotions can
+// never offer the same seat twice.
+func (r *CourseRepository) OfferSeat(ctx context.Context, id primitive.ObjectID) error {
+	filter := bson.M{"_id": id, "$expr": bson.M{"$gt": bson.A{
+		"$available_seats",
+		bson.M{"$add": bson.A{
+			bson.M{"$ifNull": bson.A{"$held_seats", 0}},
+			bson.M{"$ifNull": bson.A{"$offered_seats", 0}},
+		}},
+	}}}
+	update := bson.M{"$inc": bson.M{"offered_seats": 1}}
+	result, err := r.collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return txError(err)
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrNoAvailableSeats
+	}
+	return nil
+}
+
+// ReleaseOfferedSeat ends an offer's claim on a seat once the offer is
+// claimed, declined or expired.
+func (r *CourseRepository) ReleaseOfferedSeat(ctx context.Context, id primitive.ObjectID) error {
+	filter := bson.M{"_id": id, "offered_seats": bson.M{"$gt": 0}}
+	update := bson.M{"$inc": bson.M{"offered_seats": -1}}
+	if _, err := r.collection().UpdateOne(ctx, filter, update); err != nil {
+		return txError(err)
+	}
+	return nil
+}
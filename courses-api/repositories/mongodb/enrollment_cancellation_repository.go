@@ -0,0 +1,30 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type EnrollmentCancellationRepository struct {
+	db *mongo.Database
+}
+
+func NewEnrollmentCancellationRepository(db *mongo.Database) *EnrollmentCancellationRepository {
+	return &EnrollmentCancellationRepository{db: db}
+}
+
+func (r *EnrollmentCancellationRepository) collection() *mongo.Collection {
+	return r.db.Collection("enrollment_cancellations")
+}
+
+func (r *EnrollmentCancellationRepository) Create(ctx context.Context, cancellation *models.EnrollmentCancellation) error {
+	result, err := r.collection().InsertOne(ctx, cancellation)
+	if err != nil {
+		return txError(err)
+	}
+	cancellation.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
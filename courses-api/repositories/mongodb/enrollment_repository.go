@@ -3,10 +3,12 @@ package mongodb
 import (
 	"context"
 	"courses-api/models"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type EnrollmentRepository struct {
@@ -21,10 +23,23 @@ func (r *EnrollmentRepository) collection() *mongo.Collection {
 	return r.db.Collection("enrollments")
 }
 
+// EnsureIndexes creates the unique (course_id, user_id) index that prevents
+// duplicate enrollments across replicas.
+func (r *EnrollmentRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "course_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
 func (r *EnrollmentRepository) Create(ctx context.Context, enrollment *models.Enrollment) error {
 	result, err := r.collection().InsertOne(ctx, enrollment)
 	if err != nil {
-		return models.ErrDatabaseOperation
+		if mongo.IsDuplicateKeyError(err) {
+			return models.ErrAlreadyEnrolled
+		}
+		return txError(err)
 	}
 	enrollment.ID = result.InsertedID.(primitive.ObjectID)
 	return nil
@@ -53,4 +68,96 @@ func (r *EnrollmentRepository) CheckEnrollment(ctx context.Context, courseID pri
 		return false, models.ErrDatabaseOperation
 	}
 	return count > 0, nil
-} 
\ No newline at end of file
+}
+
+func (r *EnrollmentRepository) CountByCourseID(ctx context.Context, courseID primitive.ObjectID) (int64, error) {
+	count, err := r.collection().CountDocuments(ctx, bson.M{"course_id": courseID})
+	if err != nil {
+		return 0, models.ErrDatabaseOperation
+	}
+	return count, nil
+}
+
+// FindByCourseID returns one page of a course's enrollments, oldest first,
+// and the total number of enrollments in the course.
+func (r *EnrollmentRepository) FindByCourseID(ctx context.Context, courseID primitive.ObjectID, page, limit int) ([]models.Enrollment, int64, error) {
+	filter := bson.M{"course_id": courseID}
+	total, err := r.collection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, models.ErrDatabaseOperation
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "date", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	enrollments := []models.Enrollment{}
+	if err = cursor.All(ctx, &enrollments); err != nil {
+		return nil, 0, models.ErrDatabaseOperation
+	}
+	return enrollments, total, nil
+}
+
+func (r *EnrollmentRepository) FindByCourseAndUser(ctx context.Context, courseID primitive.ObjectID, userID int) (*models.Enrollment, error) {
+	var enrollment models.Enrollment
+	err := r.collection().FindOne(ctx, bson.M{
+		"course_id": courseID,
+		"user_id":   userID,
+	}).Decode(&enrollment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrEnrollmentNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &enrollment, nil
+}
+
+func (r *EnrollmentRepository) FindByLicenseID(ctx context.Context, licenseID primitive.ObjectID) ([]models.Enrollment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+	cursor, err := r.collection().Find(ctx, bson.M{"license_id": licenseID}, opts)
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	enrollments := []models.Enrollment{}
+	if err = cursor.All(ctx, &enrollments); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return enrollments, nil
+}
+
+// UpdateProgress records the resume pointer and, once set, the completion time.
+func (r *EnrollmentRepository) UpdateProgress(ctx context.Context, id primitive.ObjectID, lastLessonID primitive.ObjectID, completedAt *time.Time) error {
+	set := bson.M{"last_lesson_id": lastLessonID}
+	if completedAt != nil {
+		set["completed_at"] = completedAt
+	}
+
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrEnrollmentNotFound
+	}
+	return nil
+}
+
+func (r *EnrollmentRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection().DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return txError(err)
+	}
+	if result.DeletedCount == 0 {
+		return models.ErrEnrollmentNotFound
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type InstructorRepository struct {
+	db *mongo.Database
+}
+
+func NewInstructorRepository(db *mongo.Database) *InstructorRepository {
+	return &InstructorRepository{db: db}
+}
+
+func (r *InstructorRepository) collection() *mongo.Collection {
+	return r.db.Collection("instructors")
+}
+
+// Save creates the profile of the user it belongs to or updates its fields.
+// created_at is only written when the profile is inserted.
+func (r *InstructorRepository) Save(ctx context.Context, profile *models.InstructorProfile) error {
+	update := bson.M{
+		"$set": bson.M{
+			"display_name": profile.DisplayName,
+			"bio":          profile.Bio,
+			"photo_url":    profile.PhotoURL,
+			"updated_at":   profile.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{"created_at": profile.CreatedAt},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := r.collection().FindOneAndUpdate(ctx, bson.M{"_id": profile.UserID}, update, opts).Decode(profile)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	return nil
+}
+
+func (r *InstructorRepository) FindByUserID(ctx context.Context, userID int) (*models.InstructorProfile, error) {
+	var profile models.InstructorProfile
+	err := r.collection().FindOne(ctx, bson.M{"_id": userID}).Decode(&profile)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrInstructorNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &profile, nil
+}
+
+func (r *InstructorRepository) FindByUserIDs(ctx context.Context, userIDs []int) ([]models.InstructorProfile, error) {
+	cursor, err := r.collection().Find(ctx, bson.M{"_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	var profiles []models.InstructorProfile
+	if err = cursor.All(ctx, &profiles); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return profiles, nil
+}
+
+func (r *InstructorRepository) Delete(ctx context.Context, userID int) error {
+	result, err := r.collection().DeleteOne(ctx, bson.M{"_id": userID})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.DeletedCount == 0 {
+		return models.ErrInstructorNotFound
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LessonRepository struct {
+	db *mongo.Database
+}
+
+func NewLessonRepository(db *mongo.Database) *LessonRepository {
+	return &LessonRepository{db: db}
+}
+
+func (r *LessonRepository) collection() *mongo.Collection {
+	return r.db.Collection("lessons")
+}
+
+func (r *LessonRepository) Create(ctx context.Context, lesson *models.Lesson) error {
+	// Append new lessons at the end of their section
+	position, err := nextPosition(ctx, r.collection(), "lessons:"+lesson.SectionID.Hex(), bson.M{"section_id": lesson.SectionID})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	lesson.Position = position
+
+	result, err := r.collection().InsertOne(ctx, lesson)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	lesson.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *LessonRepository) FindByCourseID(ctx context.Context, courseID primitive.ObjectID) ([]models.Lesson, error) {
+	return r.find(ctx, bson.M{"course_id": courseID})
+}
+
+func (r *LessonRepository) FindBySectionID(ctx context.Context, sectionID primitive.ObjectID) ([]models.Lesson, error) {
+	return r.find(ctx, bson.M{"section_id": sectionID})
+}
+
+func (r *LessonRepository) find(ctx context.Context, filter bson.M) ([]models.Lesson, error) {
+	opts := options.Find().SetSort(bson.M{"position": 1})
+	cursor, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	var lessons []models.Lesson
+	if err = cursor.All(ctx, &lessons); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return lessons, nil
+}
+
+func (r *LessonRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Lesson, error) {
+	var lesson models.Lesson
+	err := r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&lesson)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrLessonNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &lesson, nil
+}
+
+func (r *LessonRepository) Update(ctx context.Context, lesson *models.Lesson) error {
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": lesson.ID}, bson.M{"$set": lesson})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrLessonNotFound
+	}
+	return nil
+}
+
+func (r *LessonRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection().DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.DeletedCount == 0 {
+		return models.ErrLessonNotFound
+	}
+	return nil
+}
+
+func (r *LessonRepository) DeleteBySectionID(ctx context.Context, sectionID primitive.ObjectID) error {
+	if _, err := r.collection().DeleteMany(ctx, bson.M{"section_id": sectionID}); err != nil {
+		return models.ErrDatabaseOperation
+	}
+	return nil
+}
+
+// Reorder sets each lesson's position to its index in ids.
+func (r *LessonRepository) Reorder(ctx context.Context, sectionID primitive.ObjectID, ids []primitive.ObjectID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(ids))
+	for i, id := range ids {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id, "section_id": sectionID}).
+			SetUpdate(bson.M{"$set": bson.M{"position": i}}))
+	}
+
+	if _, err := r.collection().BulkWrite(ctx, writes); err != nil {
+		return models.ErrDatabaseOperation
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MaterialRepository struct {
+	db *mongo.Database
+}
+
+func NewMaterialRepository(db *mongo.Database) *MaterialRepository {
+	return &MaterialRepository{db: db}
+}
+
+func (r *MaterialRepository) collection() *mongo.Collection {
+	return r.db.Collection("materials")
+}
+
+// storage holds one byte counter per course, keyed by course ID.
+func (r *MaterialRepository) storage() *mongo.Collection {
+	return r.db.Collection("material_storage")
+}
+
+func (r *MaterialRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "course_id", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	return err
+}
+
+func (r *MaterialRepository) Create(ctx context.Context, material *models.Material) error {
+	result, err := r.collection().InsertOne(ctx, material)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	material.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *MaterialRepository) FindByCourseID(ctx context.Context, courseID primitive.ObjectID) ([]models.Material, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection().Find(ctx, bson.M{"course_id": courseID}, opts)
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	materials := []models.Material{}
+	if err = cursor.All(ctx, &materials); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return materials, nil
+}
+
+func (r *MaterialRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Material, error) {
+	var material models.Material
+	err := r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&material)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrMaterialNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &material, nil
+}
+
+func (r *MaterialRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection().DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.DeletedCount == 0 {
+		return models.ErrMaterialNotFound
+	}
+	return nil
+}
+
+// CountByKey counts the materials stored in a blob, which clones share with
+// the course they were copied from.
+func (r *MaterialRepository) CountByKey(ctx context.Context, key string) (int64, error) {
+	count, err := r.collection().CountDocuments(ctx, bson.M{"key": key})
+	if err != nil {
+		return 0, models.ErrDatabaseOperation
+	}
+	return count, nil
+}
+
+// ReserveStorage atomically adds size bytes to the course's usage unless
+// that would exceed the quota.
+func (r *MaterialRepository) ReserveStorage(ctx context.Context, courseID primitive.ObjectID, size, quota int64) error {
+	if size > quota {
+		return models.ErrMaterialQuotaExceeded
+	}
+	// Over the quota the filter no longer matches, and the upsert collides
+	// with the existing counter on _id
+	_, err := r.storage().UpdateOne(ctx,
+		bson.M{"_id": courseID, "bytes": bson.M{"$lte": quota - size}},
+		bson.M{"$inc": bson.M{"bytes": size}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.ErrMaterialQuotaExceeded
+		}
+		return models.ErrDatabaseOperation
+	}
+	return nil
+}
+
+// ReleaseStorage gives back bytes reserved for a deleted or failed upload.
+func (r *MaterialRepository) ReleaseStorage(ctx context.Context, courseID primitive.ObjectID, size int64) error {
+	_, err := r.storage().UpdateOne(ctx,
+		bson.M{"_id": courseID, "bytes": bson.M{"$gte": size}},
+		bson.M{"$inc": bson.M{"bytes": -size}},
+	)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	return nil
+}
+
+func (r *MaterialRepository) StorageUsed(ctx context.Context, courseID primitive.ObjectID) (int64, error) {
+	var usage struct {
+		Bytes int64 `bson:"bytes"`
+	}
+	err := r.storage().FindOne(ctx, bson.M{"_id": courseID}).Decode(&usage)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil
+		}
+		return 0, models.ErrDatabaseOperation
+	}
+	return usage.Bytes, nil
+}
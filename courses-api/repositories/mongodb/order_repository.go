@@ -0,0 +1,115 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OrderRepository struct {
+	db *mongo.Database
+}
+
+func NewOrderRepository(db *mongo.Database) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+func (r *OrderRepository) collection() *mongo.Collection {
+	return r.db.Collection("orders")
+}
+
+func (r *OrderRepository) Create(ctx context.Context, order *models.Order) error {
+	result, err := r.collection().InsertOne(ctx, order)
+	if err != nil {
+		return txError(err)
+	}
+	order.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *OrderRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Order, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *OrderRepository) FindByProviderRef(ctx context.Context, provider, ref string) (*models.Order, error) {
+	return r.findOne(ctx, bson.M{"provider": provider, "provider_ref": ref})
+}
+
+func (r *OrderRepository) FindPending(ctx context.Context, courseID primitive.ObjectID, userID int) (*models.Order, error) {
+	return r.findOne(ctx, bson.M{
+		"course_id": courseID,
+		"user_id":   userID,
+		"status":    models.OrderPending,
+	})
+}
+
+func (r *OrderRepository) findOne(ctx context.Context, filter bson.M) (*models.Order, error) {
+	var order models.Order
+	err := r.collection().FindOne(ctx, filter).Decode(&order)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrOrderNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &order, nil
+}
+
+func (r *OrderRepository) FindByUserID(ctx context.Context, userID int) ([]models.Order, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection().Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	var orders []models.Order
+	if err = cursor.All(ctx, &orders); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return orders, nil
+}
+
+func (r *OrderRepository) SetProviderRef(ctx context.Context, id primitive.ObjectID, ref string) error {
+	update := bson.M{"$set": bson.M{"provider_ref": ref, "updated_at": time.Now()}}
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrOrderNotFound
+	}
+	return nil
+}
+
+// Transition stores the order's new status and outcome fields only if it is
+// still in the from status, so that a payment is never processed twice when
+// the provider and the webhook race or a webhook is delivered again.
+func (r *OrderRepository) Transition(ctx context.Context, order *models.Order, from string) error {
+	order.UpdatedAt = time.Now()
+	set := bson.M{
+		"status":         order.Status,
+		"failure_reason": order.FailureReason,
+		"updated_at":     order.UpdatedAt,
+	}
+	if order.PaidAt != nil {
+		set["paid_at"] = order.PaidAt
+	}
+	if order.EnrollmentID != nil {
+		set["enrollment_id"] = order.EnrollmentID
+	}
+
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": order.ID, "status": from}, bson.M{"$set": set})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrOrderNotPending
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// nextPosition claims the next position at the end of an ordered list, such
+// as a course's sections, from the list's counter. Counters only move
+// forward, so positions stay unique across deletes and concurrent creates.
+// A list without a counter yet starts after the highest position stored in
+// items under filter.
+func nextPosition(ctx context.Context, items *mongo.Collection, key string, filter bson.M) (int, error) {
+	counters := items.Database().Collection("counters")
+	for {
+		var counter struct {
+			Next int `bson:"next"`
+		}
+		err := counters.FindOneAndUpdate(ctx, bson.M{"_id": key}, bson.M{"$inc": bson.M{"next": 1}}).Decode(&counter)
+		if err == nil {
+			return counter.Next, nil
+		}
+		if err != mongo.ErrNoDocuments {
+			return 0, err
+		}
+
+		var last struct {
+			Position int `bson:"position"`
+		}
+		seed := 0
+		opts := options.FindOne().SetSort(bson.M{"position": -1}).SetProjection(bson.M{"position": 1})
+		err = items.FindOne(ctx, filter, opts).Decode(&last)
+		if err == nil {
+			seed = last.Position + 1
+		} else if err != mongo.ErrNoDocuments {
+			return 0, err
+		}
+
+		// Concurrent seeders agree on the seed, and $max never takes back
+		// positions already claimed
+		_, err = counters.UpdateOne(ctx, bson.M{"_id": key}, bson.M{"$max": bson.M{"next": seed}}, options.Update().SetUpsert(true))
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return 0, err
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LessonProgressRepository struct {
+	db *mongo.Database
+}
+
+func NewLessonProgressRepository(db *mongo.Database) *LessonProgressRepository {
+	return &LessonProgressRepository{db: db}
+}
+
+func (r *LessonProgressRepository) collection() *mongo.Collection {
+	return r.db.Collection("lesson_progress")
+}
+
+// EnsureIndexes creates the unique (enrollment_id, lesson_id) index that
+// keeps concurrent updates of a lesson from inserting duplicate rows.
+func (r *LessonProgressRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "enrollment_id", Value: 1}, {Key: "lesson_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// Upsert stores the progress of one lesson for an enrollment, keeping the
+// original start time when the lesson was already started. Completed
+// lessons are left as they are, so a concurrent "started" cannot undo a
+// completion.
+func (r *LessonProgressRepository) Upsert(ctx context.Context, progress *models.LessonProgress) error {
+	key := bson.M{
+		"enrollment_id": progress.EnrollmentID,
+		"lesson_id":     progress.LessonID,
+	}
+	filter := bson.M{
+		"enrollment_id": progress.EnrollmentID,
+		"lesson_id":     progress.LessonID,
+		"status":        bson.M{"$ne": models.LessonCompleted},
+	}
+
+	set := bson.M{"status": progress.Status}
+	if progress.CompletedAt != nil {
+		set["completed_at"] = progress.CompletedAt
+	}
+	update := bson.M{
+		"$set": set,
+		"$setOnInsert": bson.M{
+			"course_id":  progress.CourseID,
+			"user_id":    progress.UserID,
+			"started_at": progress.StartedAt,
+		},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := r.collection().FindOneAndUpdate(ctx, filter, update, opts).Decode(progress)
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent upsert inserted the row first; this one now updates it
+		err = r.collection().FindOneAndUpdate(ctx, filter, update, opts).Decode(progress)
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		// The row exists but is completed already
+		err = r.collection().FindOne(ctx, key).Decode(progress)
+	}
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	return nil
+}
+
+func (r *LessonProgressRepository) FindByEnrollmentID(ctx context.Context, enrollmentID primitive.ObjectID) ([]models.LessonProgress, error) {
+	cursor, err := r.collection().Find(ctx, bson.M{"enrollment_id": enrollmentID})
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	var progress []models.LessonProgress
+	if err = cursor.All(ctx, &progress); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return progress, nil
+}
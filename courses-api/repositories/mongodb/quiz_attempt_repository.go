@@ -0,0 +1,110 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type QuizAttemptRepository struct {
+	db *mongo.Database
+}
+
+func NewQuizAttemptRepository(db *mongo.Database) *QuizAttemptRepository {
+	return &QuizAttemptRepository{db: db}
+}
+
+func (r *QuizAttemptRepository) collection() *mongo.Collection {
+	return r.db.Collection("quiz_attempts")
+}
+
+// EnsureIndexes creates the unique (quiz_id, user_id, number) index that
+// lets each attempt number be claimed once. Attempts stored before they
+// were numbered are left out of it.
+func (r *QuizAttemptRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "quiz_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "number", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"number": bson.M{"$gt": 0}}),
+	})
+	return err
+}
+
+// Create stores a new attempt. Claiming an attempt number that is already
+// taken reports ErrAttemptLimitReached.
+func (r *QuizAttemptRepository) Create(ctx context.Context, attempt *models.QuizAttempt) error {
+	result, err := r.collection().InsertOne(ctx, attempt)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.ErrAttemptLimitReached
+		}
+		return models.ErrDatabaseOperation
+	}
+	attempt.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *QuizAttemptRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.QuizAttempt, error) {
+	var attempt models.QuizAttempt
+	err := r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&attempt)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrAttemptNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &attempt, nil
+}
+
+func (r *QuizAttemptRepository) FindByQuizAndUser(ctx context.Context, quizID primitive.ObjectID, userID int) ([]models.QuizAttempt, error) {
+	opts := options.Find().SetSort(bson.M{"started_at": 1})
+	return r.find(ctx, bson.M{"quiz_id": quizID, "user_id": userID}, opts)
+}
+
+func (r *QuizAttemptRepository) FindSubmittedByQuiz(ctx context.Context, quizID primitive.ObjectID) ([]models.QuizAttempt, error) {
+	return r.find(ctx, bson.M{"quiz_id": quizID, "status": models.AttemptSubmitted})
+}
+
+func (r *QuizAttemptRepository) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]models.QuizAttempt, error) {
+	cursor, err := r.collection().Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	var attempts []models.QuizAttempt
+	if err = cursor.All(ctx, &attempts); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return attempts, nil
+}
+
+// Complete stores the outcome of an attempt. Only attempts still in progress
+// are updated, so an attempt cannot be submitted twice.
+func (r *QuizAttemptRepository) Complete(ctx context.Context, attempt *models.QuizAttempt) error {
+	filter := bson.M{"_id": attempt.ID, "status": models.AttemptInProgress}
+	update := bson.M{"$set": bson.M{
+		"status":       attempt.Status,
+		"submitted_at": attempt.SubmittedAt,
+		"answers":      attempt.Answers,
+		"results":      attempt.Results,
+		"score":        attempt.Score,
+		"max_score":    attempt.MaxScore,
+		"percent":      attempt.Percent,
+		"passed":       attempt.Passed,
+	}}
+
+	result, err := r.collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrAttemptClosed
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type QuizRepository struct {
+	db *mongo.Database
+}
+
+func NewQuizRepository(db *mongo.Database) *QuizRepository {
+	return &QuizRepository{db: db}
+}
+
+func (r *QuizRepository) collection() *mongo.Collection {
+	return r.db.Collection("quizzes")
+}
+
+func (r *QuizRepository) Create(ctx context.Context, quiz *models.Quiz) error {
+	result, err := r.collection().InsertOne(ctx, quiz)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	quiz.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *QuizRepository) FindByCourseID(ctx context.Context, courseID primitive.ObjectID) ([]models.Quiz, error) {
+	cursor, err := r.collection().Find(ctx, bson.M{"course_id": courseID})
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	var quizzes []models.Quiz
+	if err = cursor.All(ctx, &quizzes); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return quizzes, nil
+}
+
+func (r *QuizRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Quiz, error) {
+	var quiz models.Quiz
+	err := r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&quiz)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrQuizNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &quiz, nil
+}
+
+func (r *QuizRepository) Update(ctx context.Context, quiz *models.Quiz) error {
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": quiz.ID}, bson.M{"$set": quiz})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrQuizNotFound
+	}
+	return nil
+}
+
+func (r *QuizRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection().DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.DeletedCount == 0 {
+		return models.ErrQuizNotFound
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReplyRepository struct {
+	db *mongo.Database
+}
+
+func NewReplyRepository(db *mongo.Database) *ReplyRepository {
+	return &ReplyRepository{db: db}
+}
+
+func (r *ReplyRepository) collection() *mongo.Collection {
+	return r.db.Collection("thread_replies")
+}
+
+func (r *ReplyRepository) Create(ctx context.Context, reply *models.Reply) error {
+	if reply.UpvoterIDs == nil {
+		reply.UpvoterIDs = []int{}
+	}
+	result, err := r.collection().InsertOne(ctx, reply)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	reply.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *ReplyRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Reply, error) {
+	var reply models.Reply
+	err := r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&reply)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrReplyNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &reply, nil
+}
+
+// FindPage returns replies in posting order, created after the cursor.
+func (r *ReplyRepository) FindPage(ctx context.Context, threadID primitive.ObjectID, after *primitive.ObjectID, limit int) ([]models.Reply, error) {
+	filter := bson.M{"thread_id": threadID}
+	if after != nil {
+		filter["_id"] = bson.M{"$gt": *after}
+	}
+	opts := options.Find().SetSort(bson.M{"_id": 1}).SetLimit(int64(limit))
+
+	cursor, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	replies := []models.Reply{}
+	if err = cursor.All(ctx, &replies); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return replies, nil
+}
+
+func (r *ReplyRepository) Update(ctx context.Context, reply *models.Reply) error {
+	update := bson.M{"$set": bson.M{
+		"body":       reply.Body,
+		"updated_at": reply.UpdatedAt,
+	}}
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": reply.ID}, update)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrReplyNotFound
+	}
+	return nil
+}
+
+func (r *ReplyRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection().DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.DeletedCount == 0 {
+		return models.ErrReplyNotFound
+	}
+	return nil
+}
+
+func (r *ReplyRepository) DeleteByThreadID(ctx context.Context, threadID primitive.ObjectID) error {
+	if _, err := r.collection().DeleteMany(ctx, bson.M{"thread_id": threadID}); err != nil {
+		return models.ErrDatabaseOperation
+	}
+	return nil
+}
+
+// SetInstructorAnswer marks replyID as the thread's answer, clearing any
+// previous mark. A nil replyID only clears the mark.
+func (r *ReplyRepository) SetInstructorAnswer(ctx context.Context, threadID primitive.ObjectID, replyID *primitive.ObjectID) error {
+	unmark := bson.M{"$set": bson.M{"instructor_answer": false}}
+	if _, err := r.collection().UpdateMany(ctx, bson.M{"thread_id": threadID, "instructor_answer": true}, unmark); err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if replyID == nil {
+		return nil
+	}
+
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": *replyID, "thread_id": threadID}, bson.M{"$set": bson.M{"instructor_answer": true}})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrReplyNotFound
+	}
+	return nil
+}
+
+// AddUpvote records one upvote per user; repeated upvotes are ignored.
+func (r *ReplyRepository) AddUpvote(ctx context.Context, id primitive.ObjectID, userID int) error {
+	filter := bson.M{"_id": id, "upvoter_ids": bson.M{"$ne": userID}}
+	update := bson.M{"$addToSet": bson.M{"upvoter_ids": userID}, "$inc": bson.M{"upvotes": 1}}
+	return r.toggleVote(ctx, id, filter, update)
+}
+
+func (r *ReplyRepository) RemoveUpvote(ctx context.Context, id primitive.ObjectID, userID int) error {
+	filter := bson.M{"_id": id, "upvoter_ids": userID}
+	update := bson.M{"$pull": bson.M{"upvoter_ids": userID}, "$inc": bson.M{"upvotes": -1}}
+	return r.toggleVote(ctx, id, filter, update)
+}
+
+func (r *ReplyRepository) toggleVote(ctx context.Context, id primitive.ObjectID, filter, update bson.M) error {
+	result, err := r.collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	// Nothing to change: either the vote was already in place or the reply is gone
+	count, err := r.collection().CountDocuments(ctx, bson.M{"_id": id})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if count == 0 {
+		return models.ErrReplyNotFound
+	}
+	return nil
+}
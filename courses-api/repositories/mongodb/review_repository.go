@@ -0,0 +1,165 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+	"math"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReviewRepository struct {
+	db *mongo.Database
+}
+
+func NewReviewRepository(db *mongo.Database) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+func (r *ReviewRepository) collection() *mongo.Collection {
+	return r.db.Collection("reviews")
+}
+
+// EnsureIndexes creates the unique (course_id, user_id) index that limits
+// each user to one review per course.
+func (r *ReviewRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "course_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func (r *ReviewRepository) Create(ctx context.Context, review *models.Review) error {
+	result, err := r.collection().InsertOne(ctx, review)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.ErrAlreadyReviewed
+		}
+		return models.ErrDatabaseOperation
+	}
+	review.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *ReviewRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Review, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *ReviewRepository) FindByCourseAndUser(ctx context.Context, courseID primitive.ObjectID, userID int) (*models.Review, error) {
+	return r.findOne(ctx, bson.M{"course_id": courseID, "user_id": userID})
+}
+
+func (r *ReviewRepository) findOne(ctx context.Context, filter bson.M) (*models.Review, error) {
+	var review models.Review
+	err := r.collection().FindOne(ctx, filter).Decode(&review)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrReviewNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &review, nil
+}
+
+// Find returns one page of reviews and the total number of matching reviews.
+func (r *ReviewRepository) Find(ctx context.Context, query models.ReviewQuery) ([]models.Review, int64, error) {
+	filter := bson.M{"course_id": query.CourseID}
+	if !query.IncludeHidden {
+		filter["hidden"] = false
+	}
+
+	total, err := r.collection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, models.ErrDatabaseOperation
+	}
+
+	var sort bson.D
+	switch query.Sort {
+	case models.ReviewSortOldest:
+		sort = bson.D{{Key: "created_at", Value: 1}}
+	case models.ReviewSortHighest:
+		sort = bson.D{{Key: "rating", Value: -1}, {Key: "created_at", Value: -1}}
+	case models.ReviewSortLowest:
+		sort = bson.D{{Key: "rating", Value: 1}, {Key: "created_at", Value: -1}}
+	default:
+		sort = bson.D{{Key: "created_at", Value: -1}}
+	}
+
+	opts := options.Find().
+		SetSort(sort).
+		SetSkip(int64((query.Page - 1) * query.Limit)).
+		SetLimit(int64(query.Limit))
+	cursor, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	reviews := []models.Review{}
+	if err = cursor.All(ctx, &reviews); err != nil {
+		return nil, 0, models.ErrDatabaseOperation
+	}
+	return reviews, total, nil
+}
+
+func (r *ReviewRepository) Update(ctx context.Context, review *models.Review) error {
+	update := bson.M{"$set": bson.M{
+		"rating":     review.Rating,
+		"comment":    review.Comment,
+		"updated_at": review.UpdatedAt,
+	}}
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": review.ID}, update)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrReviewNotFound
+	}
+	return nil
+}
+
+func (r *ReviewRepository) SetHidden(ctx context.Context, id primitive.ObjectID, hidden bool) error {
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"hidden": hidden}})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrReviewNotFound
+	}
+	return nil
+}
+
+// RatingSummary returns the average rating (rounded to two decimals) and the
+// number of visible reviews of a course.
+func (r *ReviewRepository) RatingSummary(ctx context.Context, courseID primitive.ObjectID) (float64, int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"course_id": courseID, "hidden": false}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"average": bson.M{"$avg": "$rating"},
+			"count":   bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := r.collection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, 0, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	var summary []struct {
+		Average float64 `bson:"average"`
+		Count   int     `bson:"count"`
+	}
+	if err = cursor.All(ctx, &summary); err != nil {
+		return 0, 0, models.ErrDatabaseOperation
+	}
+	if len(summary) == 0 {
+		return 0, 0, nil
+	}
+	return math.Round(summary[0].Average*100) / 100, summary[0].Count, nil
+}
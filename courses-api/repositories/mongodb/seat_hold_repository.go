@@ -0,0 +1,92 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SeatHoldRepository struct {
+	db *mongo.Database
+}
+
+func NewSeatHoldRepository(db *mongo.Database) *SeatHoldRepository {
+	return &SeatHoldRepository{db: db}
+}
+
+func (r *SeatHoldRepository) collection() *mongo.Collection {
+	return r.db.Collection("seat_holds")
+}
+
+// EnsureIndexes allows a single active hold per user and course.
+func (r *SeatHoldRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "course_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"status": models.HoldActive}),
+	})
+	return err
+}
+
+func (r *SeatHoldRepository) Create(ctx context.Context, hold *models.SeatHold) error {
+	result, err := r.collection().InsertOne(ctx, hold)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.ErrHoldExists
+		}
+		return txError(err)
+	}
+	hold.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *SeatHoldRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.SeatHold, error) {
+	var hold models.SeatHold
+	err := r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&hold)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrHoldNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &hold, nil
+}
+
+// UpdateStatus moves a hold from one status to another, failing if another
+// request or the sweeper changed it first.
+func (r *SeatHoldRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, from, to string) error {
+	result, err := r.collection().UpdateOne(ctx,
+		bson.M{"_id": id, "status": from},
+		bson.M{"$set": bson.M{"status": to}},
+	)
+	if err != nil {
+		return txError(err)
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrHoldNotFound
+	}
+	return nil
+}
+
+func (r *SeatHoldRepository) FindExpired(ctx context.Context, now time.Time) ([]models.SeatHold, error) {
+	cursor, err := r.collection().Find(ctx, bson.M{
+		"status":     models.HoldActive,
+		"expires_at": bson.M{"$lte": now},
+	})
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	var holds []models.SeatHold
+	if err = cursor.All(ctx, &holds); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return holds, nil
+}
@@ -0,0 +1,102 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SeatLicenseRepository struct {
+	db *mongo.Database
+}
+
+func NewSeatLicenseRepository(db *mongo.Database) *SeatLicenseRepository {
+	return &SeatLicenseRepository{db: db}
+}
+
+func (r *SeatLicenseRepository) collection() *mongo.Collection {
+	return r.db.Collection("seat_licenses")
+}
+
+func (r *SeatLicenseRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "manager_ids", Value: 1}},
+	})
+	return err
+}
+
+func (r *SeatLicenseRepository) Create(ctx context.Context, license *models.SeatLicense) error {
+	result, err := r.collection().InsertOne(ctx, license)
+	if err != nil {
+		return txError(err)
+	}
+	license.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *SeatLicenseRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.SeatLicense, error) {
+	var license models.SeatLicense
+	err := r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&license)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrLicenseNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &license, nil
+}
+
+func (r *SeatLicenseRepository) FindAll(ctx context.Context) ([]models.SeatLicense, error) {
+	return r.find(ctx, bson.M{})
+}
+
+// FindByManager returns the licenses whose seats the user may assign.
+func (r *SeatLicenseRepository) FindByManager(ctx context.Context, userID int) ([]models.SeatLicense, error) {
+	return r.find(ctx, bson.M{"manager_ids": userID})
+}
+
+func (r *SeatLicenseRepository) find(ctx context.Context, filter bson.M) ([]models.SeatLicense, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	licenses := []models.SeatLicense{}
+	if err = cursor.All(ctx, &licenses); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return licenses, nil
+}
+
+// ClaimSeat takes one of the license's seats only if one is left, so
+// concurrent assignments can never exceed the pool.
+func (r *SeatLicenseRepository) ClaimSeat(ctx context.Context, id primitive.ObjectID) error {
+	filter := bson.M{"_id": id, "$expr": bson.M{"$lt": bson.A{"$used_seats", "$seats"}}}
+	result, err := r.collection().UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"used_seats": 1}})
+	if err != nil {
+		return txError(err)
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrNoLicenseSeats
+	}
+	return nil
+}
+
+// ReleaseSeat gives an assigned seat back to the license.
+func (r *SeatLicenseRepository) ReleaseSeat(ctx context.Context, id primitive.ObjectID) error {
+	filter := bson.M{"_id": id, "used_seats": bson.M{"$gt": 0}}
+	result, err := r.collection().UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"used_seats": -1}})
+	if err != nil {
+		return txError(err)
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrLicenseNotFound
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SectionRepository struct {
+	db *mongo.Database
+}
+
+func NewSectionRepository(db *mongo.Database) *SectionRepository {
+	return &SectionRepository{db: db}
+}
+
+func (r *SectionRepository) collection() *mongo.Collection {
+	return r.db.Collection("sections")
+}
+
+func (r *SectionRepository) Create(ctx context.Context, section *models.Section) error {
+	// Append new sections at the end of the syllabus
+	position, err := nextPosition(ctx, r.collection(), "sections:"+section.CourseID.Hex(), bson.M{"course_id": section.CourseID})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	section.Position = position
+
+	result, err := r.collection().InsertOne(ctx, section)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	section.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *SectionRepository) FindByCourseID(ctx context.Context, courseID primitive.ObjectID) ([]models.Section, error) {
+	opts := options.Find().SetSort(bson.M{"position": 1})
+	cursor, err := r.collection().Find(ctx, bson.M{"course_id": courseID}, opts)
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	var sections []models.Section
+	if err = cursor.All(ctx, &sections); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return sections, nil
+}
+
+func (r *SectionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Section, error) {
+	var section models.Section
+	err := r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&section)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrSectionNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &section, nil
+}
+
+func (r *SectionRepository) Update(ctx context.Context, section *models.Section) error {
+	update := bson.M{"$set": bson.M{"title": section.Title}}
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": section.ID}, update)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrSectionNotFound
+	}
+	return nil
+}
+
+func (r *SectionRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection().DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.DeletedCount == 0 {
+		return models.ErrSectionNotFound
+	}
+	return nil
+}
+
+// Reorder sets each section's position to its index in ids.
+func (r *SectionRepository) Reorder(ctx context.Context, courseID primitive.ObjectID, ids []primitive.ObjectID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(ids))
+	for i, id := range ids {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id, "course_id": courseID}).
+			SetUpdate(bson.M{"$set": bson.M{"position": i}}))
+	}
+
+	if _, err := r.collection().BulkWrite(ctx, writes); err != nil {
+		return models.ErrDatabaseOperation
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ThreadRepository struct {
+	db *mongo.Database
+}
+
+func NewThreadRepository(db *mongo.Database) *ThreadRepository {
+	return &ThreadRepository{db: db}
+}
+
+func (r *ThreadRepository) collection() *mongo.Collection {
+	return r.db.Collection("threads")
+}
+
+func (r *ThreadRepository) Create(ctx context.Context, thread *models.Thread) error {
+	if thread.UpvoterIDs == nil {
+		thread.UpvoterIDs = []int{}
+	}
+	result, err := r.collection().InsertOne(ctx, thread)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	thread.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *ThreadRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Thread, error) {
+	var thread models.Thread
+	err := r.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&thread)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrThreadNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &thread, nil
+}
+
+func (r *ThreadRepository) FindPinned(ctx context.Context, courseID primitive.ObjectID) ([]models.Thread, error) {
+	opts := options.Find().SetSort(bson.M{"_id": -1})
+	return r.find(ctx, bson.M{"course_id": courseID, "pinned": true}, opts)
+}
+
+// FindPage returns unpinned threads, newest first, created before the cursor.
+func (r *ThreadRepository) FindPage(ctx context.Context, courseID primitive.ObjectID, before *primitive.ObjectID, limit int) ([]models.Thread, error) {
+	filter := bson.M{"course_id": courseID, "pinned": false}
+	if before != nil {
+		filter["_id"] = bson.M{"$lt": *before}
+	}
+	opts := options.Find().SetSort(bson.M{"_id": -1}).SetLimit(int64(limit))
+	return r.find(ctx, filter, opts)
+}
+
+func (r *ThreadRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]models.Thread, error) {
+	cursor, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	threads := []models.Thread{}
+	if err = cursor.All(ctx, &threads); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return threads, nil
+}
+
+func (r *ThreadRepository) Update(ctx context.Context, thread *models.Thread) error {
+	update := bson.M{"$set": bson.M{
+		"title":      thread.Title,
+		"body":       thread.Body,
+		"updated_at": thread.UpdatedAt,
+	}}
+	return r.updateOne(ctx, bson.M{"_id": thread.ID}, update)
+}
+
+func (r *ThreadRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection().DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.DeletedCount == 0 {
+		return models.ErrThreadNotFound
+	}
+	return nil
+}
+
+func (r *ThreadRepository) SetPinned(ctx context.Context, id primitive.ObjectID, pinned bool) error {
+	return r.updateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"pinned": pinned}})
+}
+
+func (r *ThreadRepository) SetAnswer(ctx context.Context, id primitive.ObjectID, answerID *primitive.ObjectID) error {
+	update := bson.M{"$unset": bson.M{"answer_id": ""}}
+	if answerID != nil {
+		update = bson.M{"$set": bson.M{"answer_id": answerID}}
+	}
+	return r.updateOne(ctx, bson.M{"_id": id}, update)
+}
+
+// AddUpvote records one upvote per user; repeated upvotes are ignored.
+func (r *ThreadRepository) AddUpvote(ctx context.Context, id primitive.ObjectID, userID int) error {
+	filter := bson.M{"_id": id, "upvoter_ids": bson.M{"$ne": userID}}
+	update := bson.M{"$addToSet": bson.M{"upvoter_ids": userID}, "$inc": bson.M{"upvotes": 1}}
+	return r.toggleVote(ctx, id, filter, update)
+}
+
+func (r *ThreadRepository) RemoveUpvote(ctx context.Context, id primitive.ObjectID, userID int) error {
+	filter := bson.M{"_id": id, "upvoter_ids": userID}
+	update := bson.M{"$pull": bson.M{"upvoter_ids": userID}, "$inc": bson.M{"upvotes": -1}}
+	return r.toggleVote(ctx, id, filter, update)
+}
+
+func (r *ThreadRepository) toggleVote(ctx context.Context, id primitive.ObjectID, filter, update bson.M) error {
+	result, err := r.collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	// Nothing to change: either the vote was already in place or the thread is gone
+	count, err := r.collection().CountDocuments(ctx, bson.M{"_id": id})
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if count == 0 {
+		return models.ErrThreadNotFound
+	}
+	return nil
+}
+
+// IncrementReplies adjusts the reply count and bumps the thread's activity time.
+func (r *ThreadRepository) IncrementReplies(ctx context.Context, id primitive.ObjectID, delta int) error {
+	update := bson.M{"$inc": bson.M{"reply_count": delta}}
+	if delta > 0 {
+		update["$currentDate"] = bson.M{"last_activity_at": true}
+	}
+	return r.updateOne(ctx, bson.M{"_id": id}, update)
+}
+
+func (r *ThreadRepository) updateOne(ctx context.Context, filter, update bson.M) error {
+	result, err := r.collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrThreadNotFound
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// operationError reports models.ErrDatabaseOperation while keeping the
+// driver error, so that transient transaction errors such as write conflicts
+// are still recognised and retried by the driver.
+type operationError struct {
+	cause error
+}
+
+func (e *operationError) Error() string {
+	return models.ErrDatabaseOperation.Error()
+}
+
+func (e *operationError) Unwrap() error {
+	return e.cause
+}
+
+// txError is used by repository methods that run inside transactions.
+func txError(err error) error {
+	return &operationError{cause: err}
+}
+
+// Transactor runs functions inside MongoDB transactions. Transactions need
+// the server to run as a replica set.
+type Transactor struct {
+	client *mongo.Client
+}
+
+func NewTransactor(db *mongo.Database) *Transactor {
+	return &Transactor{client: db.Client()}
+}
+
+// WithTransaction runs fn in a transaction, retrying it on transient errors.
+// Repository calls inside fn must use the context they are given.
+func (t *Transactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := t.client.StartSession()
+	if err != nil {
+		return models.ErrDatabaseOperation
+	}
+	defer session.EndSession(ctx)
+
+	var fnErr error
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		fnErr = fn(sc)
+		return nil, fnErr
+	})
+	if err == nil {
+		return nil
+	}
+
+	// Domain errors from fn are passed through; driver and commit errors are not
+	if _, ok := err.(*operationError); ok || err != fnErr {
+		return models.ErrDatabaseOperation
+	}
+	return err
+}
@@ -0,0 +1,176 @@
+package mongodb
+
+import (
+	"context"
+	"courses-api/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WaitlistRepository struct {
+	db *mongo.Database
+}
+
+func NewWaitlistRepository(db *mongo.Database) *WaitlistRepository {
+	return &WaitlistRepository{db: db}
+}
+
+func (r *WaitlistRepository) collection() *mongo.Collection {
+	return r.db.Collection("waitlist")
+}
+
+// queueOrder is the FIFO order of the waitlist; _id breaks ties between
+// entries created in the same instant.
+var queueOrder = bson.D{{Key: "joined_at", Value: 1}, {Key: "_id", Value: 1}}
+
+func activeStatuses() bson.M {
+	return bson.M{"$in": []string{models.WaitlistWaiting, models.WaitlistOffered}}
+}
+
+// EnsureIndexes creates the unique (course_id, user_id) index over active
+// entries, so a user holds at most one waiting or offered place per course
+// while their expired and enrolled entries stay on record.
+func (r *WaitlistRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "course_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"status": activeStatuses()}),
+	})
+	return err
+}
+
+func (r *WaitlistRepository) Create(ctx context.Context, entry *models.WaitlistEntry) error {
+	result, err := r.collection().InsertOne(ctx, entry)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.ErrAlreadyWaitlisted
+		}
+		return models.ErrDatabaseOperation
+	}
+	entry.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindActive returns the user's waiting or offered entry for the course.
+func (r *WaitlistRepository) FindActive(ctx context.Context, courseID primitive.ObjectID, userID int) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := r.collection().FindOne(ctx, bson.M{
+		"course_id": courseID,
+		"user_id":   userID,
+		"status":    activeStatuses(),
+	}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrWaitlistEntryNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &entry, nil
+}
+
+// FindByCourseID returns the active entries of a course in queue order.
+func (r *WaitlistRepository) FindByCourseID(ctx context.Context, courseID primitive.ObjectID) ([]models.WaitlistEntry, error) {
+	filter := bson.M{"course_id": courseID, "status": activeStatuses()}
+	cursor, err := r.collection().Find(ctx, filter, options.Find().SetSort(queueOrder))
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.WaitlistEntry
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return entries, nil
+}
+
+// CountAhead counts the waiting entries queued before the given one.
+func (r *WaitlistRepository) CountAhead(ctx context.Context, entry *models.WaitlistEntry) (int, error) {
+	count, err := r.collection().CountDocuments(ctx, bson.M{
+		"course_id": entry.CourseID,
+		"status":    models.WaitlistWaiting,
+		"$or": []bson.M{
+			{"joined_at": bson.M{"$lt": entry.JoinedAt}},
+			{"joined_at": entry.JoinedAt, "_id": bson.M{"$lt": entry.ID}},
+		},
+	})
+	if err != nil {
+		return 0, models.ErrDatabaseOperation
+	}
+	return int(count), nil
+}
+
+// CountOpenOffers counts unexpired offers, each of which holds a seat.
+func (r *WaitlistRepository) CountOpenOffers(ctx context.Context, courseID primitive.ObjectID, now time.Time) (int, error) {
+	count, err := r.collection().CountDocuments(ctx, bson.M{
+		"course_id":        courseID,
+		"status":           models.WaitlistOffered,
+		"offer_expires_at": bson.M{"$gt": now},
+	})
+	if err != nil {
+		return 0, models.ErrDatabaseOperation
+	}
+	return int(count), nil
+}
+
+// OfferNext atomically moves the first waiting entry to offered, so two
+// replicas promoting at once never pick the same user.
+func (r *WaitlistRepository) OfferNext(ctx context.Context, courseID primitive.ObjectID, now, expiresAt time.Time) (*models.WaitlistEntry, error) {
+	filter := bson.M{"course_id": courseID, "status": models.WaitlistWaiting}
+	update := bson.M{"$set": bson.M{
+		"status":           models.WaitlistOffered,
+		"offered_at":       now,
+		"offer_expires_at": expiresAt,
+	}}
+	opts := options.FindOneAndUpdate().
+		SetSort(queueOrder).
+		SetReturnDocument(options.After)
+
+	var entry models.WaitlistEntry
+	err := r.collection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.ErrWaitlistEntryNotFound
+		}
+		return nil, models.ErrDatabaseOperation
+	}
+	return &entry, nil
+}
+
+// UpdateStatus moves an entry from one status to another, failing if another
+// request changed it first.
+func (r *WaitlistRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, from, to string) error {
+	result, err := r.collection().UpdateOne(ctx,
+		bson.M{"_id": id, "status": from},
+		bson.M{"$set": bson.M{"status": to}},
+	)
+	if err != nil {
+		return txError(err)
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrWaitlistEntryNotFound
+	}
+	return nil
+}
+
+func (r *WaitlistRepository) FindExpiredOffers(ctx context.Context, now time.Time) ([]models.WaitlistEntry, error) {
+	cursor, err := r.collection().Find(ctx, bson.M{
+		"status":           models.WaitlistOffered,
+		"offer_expires_at": bson.M{"$lte": now},
+	})
+	if err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.WaitlistEntry
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, models.ErrDatabaseOperation
+	}
+	return entries, nil
+}
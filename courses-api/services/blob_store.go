@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"io"
+	"mime"
+	"os"
+	"strings"
+	"time"
+)
+
+// BlobStore keeps uploaded files and reports the URL they are served from.
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (string, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// SignedBlobStore keeps private files that are only handed out through
+// short-lived signed URLs, which download the file under the given name.
+type SignedBlobStore interface {
+	BlobStore
+	SignedURL(key, filename string, ttl time.Duration) (string, error)
+}
+
+// NewBlobStore picks the store from BLOB_STORE: "s3" for an S3-compatible
+// bucket, anything else for the local filesystem.
+func NewBlobStore() BlobStore {
+	if strings.EqualFold(os.Getenv("BLOB_STORE"), "s3") {
+		return NewS3BlobStore(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		})
+	}
+
+	dir := os.Getenv("UPLOAD_DIR")
+	if dir == "" {
+		dir = "uploads"
+	}
+	baseURL := os.Getenv("UPLOAD_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080/uploads"
+	}
+	return NewLocalBlobStore(dir, baseURL)
+}
+
+// NewMaterialStore returns the private store for course materials. It must
+// not share a public bucket or directory with NewBlobStore.
+func NewMaterialStore() SignedBlobStore {
+	if strings.EqualFold(os.Getenv("BLOB_STORE"), "s3") {
+		bucket := os.Getenv("S3_MATERIALS_BUCKET")
+		if bucket == "" {
+			bucket = "materials"
+		}
+		return NewS3BlobStore(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    bucket,
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		})
+	}
+
+	dir := os.Getenv("MATERIALS_DIR")
+	if dir == "" {
+		dir = "materials"
+	}
+	baseURL := os.Getenv("MATERIALS_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080/files"
+	}
+	signingKey := os.Getenv("MATERIALS_SIGNING_KEY")
+	if signingKey == "" {
+		signingKey = os.Getenv("JWT_SECRET")
+	}
+	return NewPrivateLocalBlobStore(dir, baseURL, []byte(signingKey))
+}
+
+// attachmentDisposition builds a Content-Disposition that downloads the
+// file under its original name.
+func attachmentDisposition(filename string) string {
+	return mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+}
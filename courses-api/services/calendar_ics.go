@@ -0,0 +1,69 @@
+package services
+
+import (
+	"bytes"
+	"courses-api/models"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const icsTimeFormat = "20060102T150405Z"
+
+// renderICS writes the occurrences as an RFC 5545 calendar. Times are
+// emitted in UTC so no VTIMEZONE definitions are needed.
+func renderICS(name string, occurrences []models.Occurrence, stamp time.Time) []byte {
+	var buf bytes.Buffer
+	line := func(s string) {
+		buf.WriteString(foldICSLine(s))
+		buf.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//UCCedemy//Courses//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escapeICSText(name))
+	for _, o := range occurrences {
+		line("BEGIN:VEVENT")
+		line(fmt.Sprintf("UID:%s-%d-%s@uccedemy", o.CourseID.Hex(), o.SessionIndex, o.Start.UTC().Format("20060102")))
+		line("DTSTAMP:" + stamp.UTC().Format(icsTimeFormat))
+		line("DTSTART:" + o.Start.UTC().Format(icsTimeFormat))
+		line("DTEND:" + o.End.UTC().Format(icsTimeFormat))
+		line("SUMMARY:" + escapeICSText(o.Title))
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return buf.Bytes()
+}
+
+func escapeICSText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// foldICSLine splits content lines longer than 75 octets, continuing them on
+// lines that start with a space, without breaking UTF-8 sequences.
+func foldICSLine(s string) string {
+	if len(s) <= 75 {
+		return s
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CalendarService struct {
+	tokenRepo      models.CalendarTokenRepository
+	enrollmentRepo models.EnrollmentRepository
+	courseRepo     models.CourseRepository
+	baseURL        string
+}
+
+func NewCalendarService(
+	tokenRepo models.CalendarTokenRepository,
+	enrollmentRepo models.EnrollmentRepository,
+	courseRepo models.CourseRepository,
+) *CalendarService {
+	baseURL := os.Getenv("PUBLIC_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+	return &CalendarService{
+		tokenRepo:      tokenRepo,
+		enrollmentRepo: enrollmentRepo,
+		courseRepo:     courseRepo,
+		baseURL:        baseURL,
+	}
+}
+
+// GetFeedURL returns the user's subscription URL, creating a token on first use.
+func (s *CalendarService) GetFeedURL(ctx context.Context, userID int) (string, error) {
+	token, err := s.tokenRepo.FindByUserID(ctx, userID)
+	if err == models.ErrCalendarTokenNotFound {
+		token, err = newCalendarToken(userID)
+		if err != nil {
+			return "", err
+		}
+		err = s.tokenRepo.Create(ctx, token)
+		if err == models.ErrCalendarTokenExists {
+			// A concurrent first request created the feed; share it
+			token, err = s.tokenRepo.FindByUserID(ctx, userID)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return s.feedURL(token.Token), nil
+}
+
+// RotateFeedURL issues a new token, revoking the previous feed URL.
+func (s *CalendarService) RotateFeedURL(ctx context.Context, userID int) (string, error) {
+	token, err := newCalendarToken(userID)
+	if err != nil {
+		return "", err
+	}
+	if err := s.tokenRepo.Save(ctx, token); err != nil {
+		return "", err
+	}
+	return s.feedURL(token.Token), nil
+}
+
+func newCalendarToken(userID int) (*models.CalendarToken, error) {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	return &models.CalendarToken{
+		UserID:    userID,
+		Token:     hex.EncodeToString(buf),
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// RenderFeed builds the .ics calendar of every session of the courses the
+// token owner is enrolled in.
+func (s *CalendarService) RenderFeed(ctx context.Context, token string) ([]byte, error) {
+	owner, err := s.tokenRepo.FindByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollments, err := s.enrollmentRepo.FindByUserID(ctx, owner.UserID)
+	if err != nil {
+		return nil, err
+	}
+	courseIDs := make([]primitive.ObjectID, 0, len(enrollments))
+	for _, enrollment := range enrollments {
+		courseIDs = append(courseIDs, enrollment.CourseID)
+	}
+
+	var occurrences []models.Occurrence
+	if len(courseIDs) > 0 {
+		courses, err := s.courseRepo.FindByIDs(ctx, courseIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, course := range courses {
+			// A course saved with a bad schedule should not break the whole feed
+			courseOccurrences, err := course.Occurrences()
+			if err != nil {
+				continue
+			}
+			occurrences = append(occurrences, courseOccurrences...)
+		}
+	}
+	sort.Slice(occurrences, func(i, j int) bool {
+		return occurrences[i].Start.Before(occurrences[j].Start)
+	})
+
+	return renderICS("UCCedemy courses", occurrences, time.Now()), nil
+}
+
+func (s *CalendarService) feedURL(token string) string {
+	return fmt.Sprintf("%s/calendar/%s.ics", s.baseURL, token)
+}
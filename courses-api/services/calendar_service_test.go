@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryCalendarTokenRepo struct {
+	models.CalendarTokenRepository
+	mu     sync.Mutex
+	tokens map[int]models.CalendarToken
+}
+
+func (r *memoryCalendarTokenRepo) FindByUserID(ctx context.Context, userID int) (*models.CalendarToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[userID]
+	if !ok {
+		return nil, models.ErrCalendarTokenNotFound
+	}
+	return &token, nil
+}
+
+func (r *memoryCalendarTokenRepo) Create(ctx context.Context, token *models.CalendarToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tokens[token.UserID]; ok {
+		return models.ErrCalendarTokenExists
+	}
+	r.tokens[token.UserID] = *token
+	return nil
+}
+
+func TestGetFeedURLConcurrentFirstUse(t *testing.T) {
+	service := NewCalendarService(&memoryCalendarTokenRepo{tokens: map[int]models.CalendarToken{}}, nil, nil)
+
+	urls := make([]string, 20)
+	var wg sync.WaitGroup
+	for i := range urls {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			url, err := service.GetFeedURL(context.Background(), 7)
+			assert.NoError(t, err)
+			urls[i] = url
+		}(i)
+	}
+	wg.Wait()
+
+	for _, url := range urls {
+		assert.Equal(t, urls[0], url)
+	}
+}
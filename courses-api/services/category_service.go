@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CategoryService struct {
+	categoryRepo models.CategoryRepository
+	courseRepo   models.CourseRepository
+	couponRepo   models.CouponRepository
+	tx           models.Transactor
+	cache        *models.CategoryCache
+	messageQueue MessageQueue
+}
+
+func NewCategoryService(
+	categoryRepo models.CategoryRepository,
+	courseRepo models.CourseRepository,
+	couponRepo models.CouponRepository,
+	tx models.Transactor,
+	cache *models.CategoryCache,
+	messageQueue MessageQueue,
+) *CategoryService {
+	return &CategoryService{
+		categoryRepo: categoryRepo,
+		courseRepo:   courseRepo,
+		couponRepo:   couponRepo,
+		tx:           tx,
+		cache:        cache,
+		messageQueue: messageQueue,
+	}
+}
+
+func (s *CategoryService) GetCategories(ctx context.Context) ([]models.Category, error) {
+	return s.categoryRepo.FindAll(ctx)
+}
+
+func (s *CategoryService) GetCategory(ctx context.Context, id primitive.ObjectID) (*models.Category, error) {
+	return s.categoryRepo.FindByID(ctx, id)
+}
+
+func (s *CategoryService) CreateCategory(ctx context.Context, category *models.Category) error {
+	category.ID = primitive.NilObjectID
+	if err := category.Validate(); err != nil {
+		return err
+	}
+	if err := s.checkParent(ctx, category); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	category.CreatedAt = now
+	category.UpdatedAt = now
+	if err := s.categoryRepo.Create(ctx, category); err != nil {
+		return err
+	}
+	s.cache.Invalidate()
+	return nil
+}
+
+// UpdateCategory saves a category. Changing its slug renames it on every
+// course and coupon in the same transaction, then re-indexes the courses.
+func (s *CategoryService) UpdateCategory(ctx context.Context, category *models.Category) error {
+	if err := category.Validate(); err != nil {
+		return err
+	}
+	previous, err := s.categoryRepo.FindByID(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkParent(ctx, category); err != nil {
+		return err
+	}
+
+	category.CreatedAt = previous.CreatedAt
+	category.UpdatedAt = time.Now()
+	renamed := category.Slug != previous.Slug
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.Update(ctx, category); err != nil {
+			return err
+		}
+		if !renamed {
+			return nil
+		}
+		if err := s.courseRepo.RenameCategory(ctx, previous.Slug, category.Slug); err != nil {
+			return err
+		}
+		return s.couponRepo.RenameCategory(ctx, previous.Slug, category.Slug)
+	})
+	if err != nil {
+		return err
+	}
+	s.cache.Invalidate()
+
+	if renamed {
+		s.reindexCourses(ctx, category.Slug)
+	}
+	return nil
+}
+
+// DeleteCategory removes a category that no course or subcategory uses.
+func (s *CategoryService) DeleteCategory(ctx context.Context, id primitive.ObjectID) error {
+	category, err := s.categoryRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.courseRepo.CountByCategory(ctx, category.Slug)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return models.ErrCategoryInUse
+	}
+	categories, err := s.categoryRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range categories {
+		if c.ParentID != nil && *c.ParentID == id {
+			return models.ErrCategoryInUse
+		}
+	}
+
+	if err := s.categoryRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.cache.Invalidate()
+	return nil
+}
+
+// checkParent verifies that the parent exists and that the category would
+// not end up among its own ancestors.
+func (s *CategoryService) checkParent(ctx context.Context, category *models.Category) error {
+	if category.ParentID == nil {
+		return nil
+	}
+
+	categories, err := s.categoryRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	parents := make(map[primitive.ObjectID]*primitive.ObjectID, len(categories))
+	for _, c := range categories {
+		parents[c.ID] = c.ParentID
+	}
+
+	if _, ok := parents[*category.ParentID]; !ok {
+		return models.ErrCategoryNotFound
+	}
+	// Bounded in case the stored hierarchy is already inconsistent
+	for id, depth := category.ParentID, 0; id != nil && depth <= len(categories); id, depth = parents[*id], depth+1 {
+		if *id == category.ID {
+			return models.ErrCategoryCycle
+		}
+	}
+	return nil
+}
+
+func (s *CategoryService) reindexCourses(ctx context.Context, slug string) {
+	courses, err := s.courseRepo.FindByCategory(ctx, slug)
+	if err != nil {
+		log.Printf("Error loading courses to re-index for category %s: %v", slug, err)
+		return
+	}
+	for i := range courses {
+		if err := indexCourse(s.messageQueue, &courses[i]); err != nil {
+			log.Printf("Error re-indexing course %s: %v", courses[i].ID.Hex(), err)
+		}
+	}
+}
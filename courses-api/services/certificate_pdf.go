@@ -0,0 +1,54 @@
+package services
+
+import (
+	"bytes"
+	"courses-api/models"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// renderCertificatePDF draws a single landscape A4 page for the certificate.
+func renderCertificatePDF(certificate *models.Certificate, verifyURL string) ([]byte, error) {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	pdf.SetTitle("Certificate of Completion - "+certificate.CourseTitle, true)
+	pdf.AddPage()
+
+	// The core fonts only cover Latin-1, so translate accented names
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	width, height := pdf.GetPageSize()
+	pdf.SetLineWidth(1.5)
+	pdf.Rect(10, 10, width-20, height-20, "D")
+
+	pdf.SetFont("Helvetica", "B", 32)
+	pdf.SetY(40)
+	pdf.CellFormat(0, 16, "Certificate of Completion", "", 1, "C", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 16)
+	pdf.Ln(8)
+	pdf.CellFormat(0, 10, "This certifies that", "", 1, "C", false, 0, "")
+
+	pdf.SetFont("Helvetica", "B", 26)
+	pdf.CellFormat(0, 16, tr(certificate.StudentName), "", 1, "C", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 16)
+	pdf.CellFormat(0, 10, "has successfully completed the course", "", 1, "C", false, 0, "")
+
+	pdf.SetFont("Helvetica", "B", 22)
+	pdf.CellFormat(0, 14, tr(certificate.CourseTitle), "", 1, "C", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 14)
+	pdf.CellFormat(0, 10, tr("Instructor: "+certificate.Instructor), "", 1, "C", false, 0, "")
+	pdf.CellFormat(0, 10, "Date: "+certificate.IssuedAt.Format("January 2, 2006"), "", 1, "C", false, 0, "")
+
+	pdf.SetY(height - 40)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, "Verification code: "+certificate.Code, "", 1, "C", false, 0, "")
+	pdf.CellFormat(0, 6, verifyURL, "", 1, "C", false, 0, verifyURL)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
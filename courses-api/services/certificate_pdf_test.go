@@ -0,0 +1,24 @@
+package services
+
+import (
+	"bytes"
+	"courses-api/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderCertificatePDF(t *testing.T) {
+	certificate := &models.Certificate{
+		Code:        "ABCD-EFGH-IJKL-MNOP",
+		StudentName: "María Pérez",
+		CourseTitle: "Introducción a Go",
+		Instructor:  "Juan",
+		IssuedAt:    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	pdf, err := renderCertificatePDF(certificate, "http://localhost:8080/certificates/ABCD-EFGH-IJKL-MNOP/verify")
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")))
+}
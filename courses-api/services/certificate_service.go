@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CertificateService struct {
+	certificateRepo models.CertificateRepository
+	enrollmentRepo  models.EnrollmentRepository
+	courseRepo      models.CourseRepository
+	users           models.UserDirectory
+	baseURL         string
+}
+
+func NewCertificateService(
+	certificateRepo models.CertificateRepository,
+	enrollmentRepo models.EnrollmentRepository,
+	courseRepo models.CourseRepository,
+	users models.UserDirectory,
+) *CertificateService {
+	// Public URL used in verification links and Open Badges documents
+	baseURL := os.Getenv("PUBLIC_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+	return &CertificateService{
+		certificateRepo: certificateRepo,
+		enrollmentRepo:  enrollmentRepo,
+		courseRepo:      courseRepo,
+		users:           users,
+		baseURL:         strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// IssueCertificate returns the certificate for a completed enrollment,
+// creating it on first request.
+func (s *CertificateService) IssueCertificate(ctx context.Context, courseID primitive.ObjectID, userID int) (*models.Certificate, error) {
+	enrollment, err := s.enrollmentRepo.FindByCourseAndUser(ctx, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment.CompletedAt == nil {
+		return nil, models.ErrCourseNotCompleted
+	}
+
+	existing, err := s.certificateRepo.FindByEnrollmentID(ctx, enrollment.ID)
+	if err == nil {
+		return existing, nil
+	}
+	if err != models.ErrCertificateNotFound {
+		return nil, err
+	}
+
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.users.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := s.newCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate := &models.Certificate{
+		Code:         code,
+		EnrollmentID: enrollment.ID,
+		CourseID:     courseID,
+		UserID:       userID,
+		StudentName:  user.Username,
+		StudentEmail: user.Email,
+		CourseTitle:  course.Title,
+		Instructor:   course.Instructor,
+		IssuedAt:     time.Now().UTC(),
+	}
+	if err := s.certificateRepo.Create(ctx, certificate); err != nil {
+		if err == models.ErrCertificateExists {
+			// Completion and a client request raced; both get the same certificate
+			return s.certificateRepo.FindByEnrollmentID(ctx, enrollment.ID)
+		}
+		return nil, err
+	}
+	return certificate, nil
+}
+
+// OnCourseCompleted issues the certificate as soon as an enrollment completes.
+func (s *CertificateService) OnCourseCompleted(ctx context.Context, courseID primitive.ObjectID, userID int) error {
+	_, err := s.IssueCertificate(ctx, courseID, userID)
+	return err
+}
+
+func (s *CertificateService) GetUserCertificates(ctx context.Context, userID int) ([]models.Certificate, error) {
+	certificates, err := s.certificateRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if certificates == nil {
+		certificates = []models.Certificate{}
+	}
+	return certificates, nil
+}
+
+func (s *CertificateService) VerifyCertificate(ctx context.Context, code string) (*models.CertificateVerification, error) {
+	certificate, err := s.certificateRepo.FindByCode(ctx, normalizeCode(code))
+	if err != nil {
+		return nil, err
+	}
+	verification := certificate.Verification()
+	return &verification, nil
+}
+
+// RenderPDF renders the certificate for its owner or an admin.
+func (s *CertificateService) RenderPDF(ctx context.Context, code string, userID int, admin bool) ([]byte, error) {
+	certificate, err := s.certificateRepo.FindByCode(ctx, normalizeCode(code))
+	if err != nil {
+		return nil, err
+	}
+	if !admin && certificate.UserID != userID {
+		return nil, models.ErrCertificateNotFound
+	}
+	return renderCertificatePDF(certificate, s.verifyURL(certificate.Code))
+}
+
+// OpenBadge returns an Open Badges 2.0 assertion for the certificate, with
+// the badge class and issuer embedded.
+func (s *CertificateService) OpenBadge(ctx context.Context, code string) (map[string]interface{}, error) {
+	certificate, err := s.certificateRepo.FindByCode(ctx, normalizeCode(code))
+	if err != nil {
+		return nil, err
+	}
+
+	// Recipients are identified by a salted hash of their email address
+	salt := certificate.Code
+	hash := sha256.Sum256([]byte(strings.ToLower(certificate.StudentEmail) + salt))
+
+	badgeURL := fmt.Sprintf("%s/certificates/%s/badge", s.baseURL, certificate.Code)
+	return map[string]interface{}{
+		"@context": "https://w3id.org/openbadges/v2",
+		"type":     "Assertion",
+		"id":       badgeURL,
+		"recipient": map[string]interface{}{
+			"type":     "email",
+			"hashed":   true,
+			"salt":     salt,
+			"identity": "sha256$" + hex.EncodeToString(hash[:]),
+		},
+		"badge": map[string]interface{}{
+			"type":        "BadgeClass",
+			"id":          fmt.Sprintf("%s/courses/%s", s.baseURL, certificate.CourseID.Hex()),
+			"name":        certificate.CourseTitle,
+			"description": fmt.Sprintf("Completed %s taught by %s", certificate.CourseTitle, certificate.Instructor),
+			"criteria": map[string]interface{}{
+				"narrative": "Complete every required lesson of the course.",
+			},
+			"issuer": map[string]interface{}{
+				"type": "Profile",
+				"id":   s.baseURL,
+				"name": "UCCedemy",
+				"url":  s.baseURL,
+			},
+		},
+		"verification": map[string]interface{}{
+			"type": "hosted",
+		},
+		"issuedOn": certificate.IssuedAt.Format(time.RFC3339),
+		"evidence": s.verifyURL(certificate.Code),
+	}, nil
+}
+
+func (s *CertificateService) verifyURL(code string) string {
+	return fmt.Sprintf("%s/certificates/%s/verify", s.baseURL, code)
+}
+
+// newCode generates a verification code such as "ABCD-EFGH-IJKL-MNOP" that
+// is not already in use.
+func (s *CertificateService) newCode(ctx context.Context) (string, error) {
+	for i := 0; i < 5; i++ {
+		buf := make([]byte, 10)
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		raw := base32.StdEncoding.EncodeToString(buf)
+		code := strings.Join([]string{raw[0:4], raw[4:8], raw[8:12], raw[12:16]}, "-")
+
+		_, err := s.certificateRepo.FindByCode(ctx, code)
+		if err == models.ErrCertificateNotFound {
+			return code, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", models.ErrDatabaseOperation
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
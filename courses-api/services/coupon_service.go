@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CouponService struct {
+	couponRepo models.CouponRepository
+}
+
+func NewCouponService(couponRepo models.CouponRepository) *CouponService {
+	return &CouponService{couponRepo: couponRepo}
+}
+
+func (s *CouponService) CreateCoupon(ctx context.Context, coupon *models.Coupon) error {
+	if err := coupon.Validate(); err != nil {
+		return err
+	}
+	coupon.ID = primitive.NilObjectID
+	coupon.Redemptions = 0
+	coupon.CreatedAt = time.Now()
+	return s.couponRepo.Create(ctx, coupon)
+}
+
+func (s *CouponService) GetCoupons(ctx context.Context) ([]models.Coupon, error) {
+	return s.couponRepo.FindAll(ctx)
+}
+
+func (s *CouponService) GetCoupon(ctx context.Context, id primitive.ObjectID) (*models.Coupon, error) {
+	return s.couponRepo.FindByID(ctx, id)
+}
+
+func (s *CouponService) UpdateCoupon(ctx context.Context, coupon *models.Coupon) error {
+	if err := coupon.Validate(); err != nil {
+		return err
+	}
+	existing, err := s.couponRepo.FindByID(ctx, coupon.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.couponRepo.Update(ctx, coupon); err != nil {
+		return err
+	}
+	coupon.Redemptions = existing.Redemptions
+	coupon.CreatedAt = existing.CreatedAt
+	return nil
+}
+
+func (s *CouponService) DeleteCoupon(ctx context.Context, id primitive.ObjectID) error {
+	return s.couponRepo.Delete(ctx, id)
+}
+
+// applyCoupon checks that the coupon can be used by the user on the course and
+// returns its discount. The limits are only advisory here; Redeem enforces
+// them atomically at checkout.
+func applyCoupon(ctx context.Context, couponRepo models.CouponRepository, code string, course *models.Course, userID int) (*models.Coupon, int64, error) {
+	coupon, err := couponRepo.FindByCode(ctx, models.NormalizeCouponCode(code))
+	if err != nil {
+		return nil, 0, err
+	}
+	if !coupon.Active {
+		return nil, 0, models.ErrCouponNotFound
+	}
+	if coupon.IsExpired(time.Now()) {
+		return nil, 0, models.ErrCouponExpired
+	}
+	if !coupon.AppliesTo(course) {
+		return nil, 0, models.ErrCouponNotApplicable
+	}
+	if coupon.MaxRedemptions > 0 && coupon.Redemptions >= coupon.MaxRedemptions {
+		return nil, 0, models.ErrCouponExhausted
+	}
+	if coupon.PerUserLimit > 0 {
+		uses, err := couponRepo.CountUses(ctx, coupon.ID, userID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if uses >= coupon.PerUserLimit {
+			return nil, 0, models.ErrCouponLimitReached
+		}
+	}
+
+	discount, err := coupon.Discount(course.Price, course.Currency)
+	if err != nil {
+		return nil, 0, err
+	}
+	return coupon, discount, nil
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CourseCloneService copies a course for a new cohort.
+type CourseCloneService struct {
+	courseRepo     models.CourseRepository
+	sectionRepo    models.SectionRepository
+	lessonRepo     models.LessonRepository
+	quizRepo       models.QuizRepository
+	materialRepo   models.MaterialRepository
+	enrollmentRepo models.EnrollmentRepository
+	tx             models.Transactor
+	messageQueue   MessageQueue
+	materialQuota  int64
+}
+
+func NewCourseCloneService(courseRepo models.CourseRepository, sectionRepo models.SectionRepository, lessonRepo models.LessonRepository, quizRepo models.QuizRepository, materialRepo models.MaterialRepository, enrollmentRepo models.EnrollmentRepository, tx models.Transactor, mq MessageQueue, materialQuota int64) *CourseCloneService {
+	return &CourseCloneService{
+		courseRepo:     courseRepo,
+		sectionRepo:    sectionRepo,
+		lessonRepo:     lessonRepo,
+		quizRepo:       quizRepo,
+		materialRepo:   materialRepo,
+		enrollmentRepo: enrollmentRepo,
+		tx:             tx,
+		messageQueue:   mq,
+		materialQuota:  materialQuota,
+	}
+}
+
+// CloneCourse copies a course with its curriculum, quizzes and materials
+// into a new draft. Enrollments, reviews and discussions stay with the
+// original. Admins and the course's instructors may clone it.
+func (s *CourseCloneService) CloneCourse(ctx context.Context, id primitive.ObjectID, req models.CloneRequest, userID int, admin bool) (*models.Course, error) {
+	original, err := s.courseRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !admin && !original.IsTaughtBy(userID) {
+		return nil, models.ErrForbidden
+	}
+
+	seats := req.Seats
+	if seats == 0 {
+		// The original capacity is what is left plus what was taken
+		enrolled, err := s.enrollmentRepo.CountByCourseID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		seats = original.AvailableSeats + int(enrolled)
+	}
+
+	clone, err := req.Apply(original, seats)
+	if err != nil {
+		return nil, err
+	}
+	if err := clone.Validate(); err != nil {
+		return nil, err
+	}
+
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		// Create rejects a title and instructor that are already taken
+		if err := s.courseRepo.Create(ctx, clone); err != nil {
+			return err
+		}
+		if err := s.copyCurriculum(ctx, id, clone.ID); err != nil {
+			return err
+		}
+		if err := s.copyQuizzes(ctx, id, clone.ID); err != nil {
+			return err
+		}
+		return s.copyMaterials(ctx, id, clone.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clone, indexCourse(s.messageQueue, clone)
+}
+
+func (s *CourseCloneService) copyCurriculum(ctx context.Context, from, to primitive.ObjectID) error {
+	sections, err := s.sectionRepo.FindByCourseID(ctx, from)
+	if err != nil {
+		return err
+	}
+	for _, section := range sections {
+		lessons, err := s.lessonRepo.FindBySectionID(ctx, section.ID)
+		if err != nil {
+			return err
+		}
+
+		copied := models.Section{CourseID: to, Title: section.Title}
+		if err := s.sectionRepo.Create(ctx, &copied); err != nil {
+			return err
+		}
+		for _, lesson := range lessons {
+			lesson.ID = primitive.NilObjectID
+			lesson.CourseID = to
+			lesson.SectionID = copied.ID
+			if err := s.lessonRepo.Create(ctx, &lesson); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *CourseCloneService) copyQuizzes(ctx context.Context, from, to primitive.ObjectID) error {
+	quizzes, err := s.quizRepo.FindByCourseID(ctx, from)
+	if err != nil {
+		return err
+	}
+	for _, quiz := range quizzes {
+		quiz.ID = primitive.NilObjectID
+		quiz.CourseID = to
+		if err := s.quizRepo.Create(ctx, &quiz); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyMaterials points the clone at the same stored files; they are only
+// deleted once no course refers to them.
+func (s *CourseCloneService) copyMaterials(ctx context.Context, from, to primitive.ObjectID) error {
+	materials, err := s.materialRepo.FindByCourseID(ctx, from)
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, material := range materials {
+		total += material.Size
+	}
+	if total == 0 {
+		return nil
+	}
+	if err := s.materialRepo.ReserveStorage(ctx, to, total, s.materialQuota); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, material := range materials {
+		material.ID = primitive.NilObjectID
+		material.CourseID = to
+		material.CreatedAt = now
+		if err := s.materialRepo.Create(ctx, &material); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bytes"
+	"courses-api/models"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+)
+
+// decodeCourseImage checks that data is a JPEG, PNG or GIF of acceptable
+// size and decodes it. The format is sniffed from the bytes rather than
+// trusted from the client.
+func decodeCourseImage(data []byte) (image.Image, string, error) {
+	if len(data) > models.MaxCourseImageBytes {
+		return nil, "", models.ErrImageTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	if !models.IsAllowedImageType(contentType) {
+		return nil, "", models.ErrUnsupportedImage
+	}
+
+	// Check the dimensions before decoding so a small file cannot expand
+	// into a huge bitmap
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", models.ErrUnsupportedImage
+	}
+	if config.Width <= 0 || config.Height <= 0 ||
+		config.Width > models.MaxCourseImageSide || config.Height > models.MaxCourseImageSide {
+		return nil, "", models.ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", models.ErrUnsupportedImage
+	}
+	return img, contentType, nil
+}
+
+// encodeThumbnail scales the image down to the given width, keeping its
+// aspect ratio, and encodes it as JPEG. Narrower images keep their size.
+func encodeThumbnail(img image.Image, width int) ([]byte, error) {
+	bounds := img.Bounds()
+	if bounds.Dx() > width {
+		height := bounds.Dy() * width / bounds.Dx()
+		if height < 1 {
+			height = 1
+		}
+		img = resizeBox(img, width, height)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resizeBox downscales by averaging the source pixels that fall into each
+// destination pixel. Transparent areas are flattened onto white for JPEG.
+func resizeBox(img image.Image, width, height int) *image.RGBA {
+	bounds := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(src, src.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Over)
+
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcH/height, (y+1)*srcH/height
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcW/width, (x+1)*srcW/width
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += int(p[0])
+					g += int(p[1])
+					b += int(p[2])
+					a += int(p[3])
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
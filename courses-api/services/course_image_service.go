@@ -0,0 +1,119 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"courses-api/models"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CourseImageService stores uploaded course images with a thumbnail and
+// points the course at them.
+type CourseImageService struct {
+	repo         models.CourseRepository
+	store        BlobStore
+	messageQueue MessageQueue
+}
+
+func NewCourseImageService(repo models.CourseRepository, store BlobStore, mq MessageQueue) *CourseImageService {
+	return &CourseImageService{repo: repo, store: store, messageQueue: mq}
+}
+
+// UploadImage validates and stores the image and its thumbnail, then sets
+// them on the course. Only admins and the course's instructors may upload.
+func (s *CourseImageService) UploadImage(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool, data []byte) (*models.Course, error) {
+	if _, err := s.editableCourse(ctx, courseID, userID, admin); err != nil {
+		return nil, err
+	}
+
+	img, contentType, err := decodeCourseImage(data)
+	if err != nil {
+		return nil, err
+	}
+	thumbnail, err := encodeThumbnail(img, models.CourseThumbnailWidth)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every upload gets fresh keys so cached copies of the old image never
+	// linger under the new URL
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	base := "courses/" + courseID.Hex() + "/" + hex.EncodeToString(buf)
+	imageKey := base + models.ImageExtension(contentType)
+	thumbnailKey := base + "_thumb.jpg"
+
+	imageURL, err := s.store.Put(ctx, imageKey, bytes.NewReader(data), int64(len(data)), contentType)
+	if err != nil {
+		return nil, err
+	}
+	thumbnailURL, err := s.store.Put(ctx, thumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), "image/jpeg")
+	if err != nil {
+		s.deleteBlobs(ctx, []string{imageKey})
+		return nil, err
+	}
+
+	previous, err := s.repo.SetImage(ctx, courseID, imageURL, thumbnailURL, []string{imageKey, thumbnailKey})
+	if err != nil {
+		s.deleteBlobs(ctx, []string{imageKey, thumbnailKey})
+		return nil, err
+	}
+	s.deleteBlobs(ctx, previous)
+
+	return s.reindex(ctx, courseID)
+}
+
+// DeleteImage clears the course image and removes uploaded files.
+func (s *CourseImageService) DeleteImage(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool) (*models.Course, error) {
+	if _, err := s.editableCourse(ctx, courseID, userID, admin); err != nil {
+		return nil, err
+	}
+
+	previous, err := s.repo.SetImage(ctx, courseID, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	s.deleteBlobs(ctx, previous)
+
+	return s.reindex(ctx, courseID)
+}
+
+func (s *CourseImageService) editableCourse(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool) (*models.Course, error) {
+	course, err := s.repo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if !admin && !course.IsTaughtBy(userID) {
+		return nil, models.ErrForbidden
+	}
+	return course, nil
+}
+
+func (s *CourseImageService) reindex(ctx context.Context, courseID primitive.ObjectID) (*models.Course, error) {
+	course, err := s.repo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return course, indexCourse(s.messageQueue, course)
+}
+
+// deleteBlobs removes replaced files unless a cloned course still uses
+// them. Failures only leave orphaned files behind, so they are logged
+// rather than returned.
+func (s *CourseImageService) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		count, err := s.repo.CountByImageKey(ctx, key)
+		if err != nil || count > 0 {
+			continue
+		}
+		if err := s.store.Delete(ctx, key); err != nil {
+			log.Printf("Error deleting blob %s: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"bytes"
+	"courses-api/models"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pngImage(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 50, B: 10, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestDecodeCourseImageSniffsType(t *testing.T) {
+	_, contentType, err := decodeCourseImage(pngImage(t, 10, 10))
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+
+	_, _, err = decodeCourseImage([]byte("<html><body>not an image</body></html>"))
+	assert.Equal(t, models.ErrUnsupportedImage, err)
+}
+
+func TestDecodeCourseImageRejectsLargeFiles(t *testing.T) {
+	_, _, err := decodeCourseImage(make([]byte, models.MaxCourseImageBytes+1))
+	assert.Equal(t, models.ErrImageTooLarge, err)
+}
+
+func TestEncodeThumbnailKeepsAspectRatio(t *testing.T) {
+	img, _, err := decodeCourseImage(pngImage(t, 1200, 600))
+	require.NoError(t, err)
+
+	data, err := encodeThumbnail(img, 400)
+	require.NoError(t, err)
+	thumb, err := jpeg.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 400, thumb.Bounds().Dx())
+	assert.Equal(t, 200, thumb.Bounds().Dy())
+
+	r, g, b, _ := thumb.At(200, 100).RGBA()
+	assert.InDelta(t, 200, r>>8, 8)
+	assert.InDelta(t, 50, g>>8, 8)
+	assert.InDelta(t, 10, b>>8, 8)
+}
+
+func TestEncodeThumbnailLeavesSmallImages(t *testing.T) {
+	img, _, err := decodeCourseImage(pngImage(t, 100, 80))
+	require.NoError(t, err)
+
+	data, err := encodeThumbnail(img, 400)
+	require.NoError(t, err)
+	thumb, err := jpeg.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 100, 80), thumb.Bounds())
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"errors"
+	"io"
+	"log"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExportCourses streams every course to w in the given format.
+func (s *CourseService) ExportCourses(ctx context.Context, format string, w io.Writer) error {
+	encoder, err := newCourseEncoder(format, w)
+	if err != nil {
+		return err
+	}
+	err = s.repo.ForEach(ctx, func(course models.Course) error {
+		return encoder.Encode(&course)
+	})
+	if err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// ImportCourses creates or updates a course for each row, matching existing
+// courses by external key. Every row is validated like a course created
+// through the API, and failures are reported per row without stopping the
+// import. Unless dryRun is set, each saved course is sent to search once.
+func (s *CourseService) ImportCourses(ctx context.Context, format string, r io.Reader, dryRun bool) (*models.ImportResult, error) {
+	decoder, err := newCourseDecoder(format, r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ImportResult{DryRun: dryRun, Rows: []models.ImportRow{}}
+	seen := map[string]bool{}
+	for row := 1; ; row++ {
+		course, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		if err == nil && row > models.MaxImportRows {
+			err = models.ErrTooManyImportRows
+		}
+
+		var skipped *rowError
+		if err != nil && !errors.As(err, &skipped) {
+			// The rest of the input cannot be read; report what was done so far
+			result.Failed++
+			result.Rows = append(result.Rows, models.ImportRow{Row: row, Action: models.ImportError, Error: err.Error()})
+			break
+		}
+
+		line := models.ImportRow{Row: row}
+		if err == nil {
+			line.ExternalKey = strings.TrimSpace(course.ExternalKey)
+			line.Action, err = s.importCourse(ctx, course, seen, dryRun)
+		}
+		if err != nil {
+			line.Action = models.ImportError
+			line.Error = err.Error()
+			result.Failed++
+		} else {
+			if !course.ID.IsZero() {
+				id := course.ID
+				line.CourseID = &id
+			}
+			if line.Action == models.ImportCreate {
+				result.Created++
+			} else {
+				result.Updated++
+			}
+		}
+		result.Rows = append(result.Rows, line)
+	}
+	return result, nil
+}
+
+// importCourse validates and saves one imported course, returning whether
+// it was (or in a dry run would be) created or updated.
+func (s *CourseService) importCourse(ctx context.Context, course *models.Course, seen map[string]bool, dryRun bool) (string, error) {
+	course.ExternalKey = strings.TrimSpace(course.ExternalKey)
+	if course.ExternalKey == "" {
+		return "", models.ErrMissingExternalKey
+	}
+	if seen[course.ExternalKey] {
+		return "", models.ErrDuplicateExternalKey
+	}
+	seen[course.ExternalKey] = true
+
+	existing, err := s.repo.FindByExternalKey(ctx, course.ExternalKey)
+	if err != nil && err != models.ErrCourseNotFound {
+		return "", err
+	}
+
+	// Imports carry catalog data only; counters and uploads stay as they are
+	action := models.ImportCreate
+	var previousInstructors []int
+	course.ID = primitive.NilObjectID
+	course.HeldSeats = 0
+	course.OfferedSeats = 0
+	course.AverageRating = 0
+	course.ReviewCount = 0
+	course.ThumbnailURL = ""
+	course.ImageKeys = nil
+	if existing != nil {
+		action = models.ImportUpdate
+		previousInstructors = existing.InstructorIDs
+		course.ID = existing.ID
+		course.AverageRating = existing.AverageRating
+		course.ReviewCount = existing.ReviewCount
+		if course.ImageURL == existing.ImageURL {
+			course.ThumbnailURL = existing.ThumbnailURL
+		}
+		if course.Status == "" {
+			course.Status = existing.CurrentStatus()
+		}
+		// CSV rows carry no translations; keep the ones already written
+		if course.Translations == nil {
+			course.Translations = existing.Translations
+			if course.DefaultLocale == "" {
+				course.DefaultLocale = existing.DefaultLocale
+			}
+		}
+	} else if course.Status == "" {
+		course.Status = models.CourseStatusDraft
+	}
+
+	if err := s.resolveInstructors(ctx, course, previousInstructors); err != nil {
+		return "", err
+	}
+	if err := course.Validate(); err != nil {
+		return "", err
+	}
+	if err := checkPrerequisiteGraph(ctx, s.repo, course); err != nil {
+		return "", err
+	}
+	if dryRun {
+		return action, nil
+	}
+
+	// Seat changes apply to the existing course's count, so enrollments
+	// made since it was read keep their seats
+	seatDelta := 0
+	if existing == nil {
+		err = s.repo.Create(ctx, course)
+	} else {
+		seatDelta = course.AvailableSeats - existing.AvailableSeats
+		var updated *models.Course
+		if updated, err = s.repo.UpdateWithSeats(ctx, course, seatDelta); err == nil {
+			course.AvailableSeats = updated.AvailableSeats
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if seatDelta > 0 && s.onSeatsReleased != nil {
+		if err := s.onSeatsReleased.OnSeatsReleased(ctx, course.ID); err != nil {
+			log.Printf("Error promoting waitlist for course %s: %v", course.ID.Hex(), err)
+		}
+	}
+	// The course is saved either way; a failed search update is caught up
+	// by the next change to the course
+	if err := s.syncSearchIndex(course); err != nil {
+		log.Printf("Error publishing search update for course %s: %v", course.ID.Hex(), err)
+	}
+	return action, nil
+}
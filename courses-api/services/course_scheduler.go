@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// CourseScheduler periodically applies scheduled publish and unpublish times.
+type CourseScheduler struct {
+	courseService *CourseService
+	interval      time.Duration
+}
+
+func NewCourseScheduler(courseService *CourseService, interval time.Duration) *CourseScheduler {
+	return &CourseScheduler{
+		courseService: courseService,
+		interval:      interval,
+	}
+}
+
+// Run blocks until ctx is cancelled, applying due schedules on every tick.
+func (s *CourseScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.courseService.ApplySchedules(ctx, now); err != nil {
+				log.Printf("Error applying course schedules: %v", err)
+			}
+		}
+	}
+}
@@ -3,59 +3,277 @@ package services
 import (
 	"context"
 	"courses-api/models"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type CourseService struct {
-    repo           models.CourseRepository
-    enrollmentRepo models.EnrollmentRepository
-    messageQueue   MessageQueue
+    repo            models.CourseRepository
+    enrollmentRepo  models.EnrollmentRepository
+    instructorRepo  models.InstructorRepository
+    messageQueue    MessageQueue
+    onSeatsReleased SeatReleaseHandler
 }
 
-func NewCourseService(repo models.CourseRepository, enrollmentRepo models.EnrollmentRepository, mq MessageQueue) *CourseService {
+func NewCourseService(repo models.CourseRepository, enrollmentRepo models.EnrollmentRepository, instructorRepo models.InstructorRepository, mq MessageQueue, onSeatsReleased SeatReleaseHandler) *CourseService {
     return &CourseService{
-        repo:           repo,
-        enrollmentRepo: enrollmentRepo,
-        messageQueue:   mq,
+        repo:            repo,
+        enrollmentRepo:  enrollmentRepo,
+        instructorRepo:  instructorRepo,
+        messageQueue:    mq,
+        onSeatsReleased: onSeatsReleased,
     }
 }
 
-func (s *CourseService) CreateCourse(ctx context.Context, course *models.Course) error {
+// CreateCourse adds a course. Admins may create any course; instructors
+// always become one of the instructors of the courses they create.
+func (s *CourseService) CreateCourse(ctx context.Context, course *models.Course, userID int, admin bool) error {
+    if !admin {
+        if _, err := s.instructorRepo.FindByUserID(ctx, userID); err != nil {
+            if err == models.ErrInstructorNotFound {
+                return models.ErrForbidden
+            }
+            return err
+        }
+        if !course.IsTaughtBy(userID) {
+            course.InstructorIDs = append(course.InstructorIDs, userID)
+        }
+
+        // Only admins publish or schedule courses
+        course.Status = models.CourseStatusDraft
+        course.PublishAt = nil
+        course.UnpublishAt = nil
+    }
+
+    if err := s.resolveInstructors(ctx, course, nil); err != nil {
+        return err
+    }
     if err := course.Validate(); err != nil {
         return err
     }
+    if err := checkPrerequisiteGraph(ctx, s.repo, course); err != nil {
+        return err
+    }
+
+    // New courses stay hidden until they are published
+    if course.Status == "" {
+        course.Status = models.CourseStatusDraft
+    }
+
+    // Rating aggregates are maintained from reviews only
+    course.AverageRating = 0
+    course.ReviewCount = 0
+
+    // Held seats are only changed by checkout holds, offered seats by the
+    // waitlist
+    course.HeldSeats = 0
+    course.OfferedSeats = 0
 
     if err := s.repo.Create(ctx, course); err != nil {
         return err
     }
 
     // Publish course creation event
-    return s.messageQueue.PublishCourseUpdate(course, "upsert")
+    return s.syncSearchIndex(course)
 }
 
-func (s *CourseService) GetAllCourses(ctx context.Context) ([]models.Course, error) {
-    return s.repo.FindAll(ctx)
+// GetAllCourses returns published courses, or every course when
+// includeUnpublished is set (admins). When tags are given, only courses
+// with all of them are returned.
+func (s *CourseService) GetAllCourses(ctx context.Context, includeUnpublished bool, tags []string) ([]models.Course, error) {
+    if len(tags) > 0 {
+        normalized := make([]string, len(tags))
+        for i, tag := range tags {
+            normalized[i] = models.NormalizeTag(tag)
+        }
+        return s.repo.FindByTags(ctx, normalized, includeUnpublished)
+    }
+    if includeUnpublished {
+        return s.repo.FindAll(ctx)
+    }
+    return s.repo.FindPublished(ctx)
+}
+
+// SuggestTags autocompletes tags from those already used by published courses.
+func (s *CourseService) SuggestTags(ctx context.Context, prefix string, limit int) ([]models.TagCount, error) {
+    if limit <= 0 || limit > 50 {
+        limit = 10
+    }
+    return s.repo.SuggestTags(ctx, models.NormalizeTag(prefix), limit)
 }
 
 func (s *CourseService) GetCourse(ctx context.Context, id primitive.ObjectID) (*models.Course, error) {
     return s.repo.FindByID(ctx, id)
 }
 
-func (s *CourseService) UpdateCourse(ctx context.Context, course *models.Course) error {
+// GetVisibleCourse returns the course if it is published or the caller is an
+// admin; unpublished courses are reported as not found to everyone else.
+func (s *CourseService) GetVisibleCourse(ctx context.Context, id primitive.ObjectID, admin bool) (*models.Course, error) {
+    course, err := s.repo.FindByID(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+    if !admin && !course.IsPublished() {
+        return nil, models.ErrCourseNotFound
+    }
+    return course, nil
+}
+
+// UpdateCourse saves changes made by an admin or one of the course's
+// instructors. Instructors cannot remove themselves from the course, nor
+// change its status or publishing schedule.
+func (s *CourseService) UpdateCourse(ctx context.Context, course *models.Course, userID int, admin bool) error {
+    previous, err := s.repo.FindByID(ctx, course.ID)
+    if err != nil {
+        return err
+    }
+    if !admin && (!previous.IsTaughtBy(userID) || !course.IsTaughtBy(userID)) {
+        return models.ErrForbidden
+    }
+    if !admin {
+        course.Status = previous.Status
+        course.PublishAt = previous.PublishAt
+        course.UnpublishAt = previous.UnpublishAt
+    }
+    // Leaving the status out keeps the current one rather than reading as
+    // published to the search index
+    if course.Status == "" {
+        course.Status = previous.CurrentStatus()
+    }
+
+    if err := s.resolveInstructors(ctx, course, previous.InstructorIDs); err != nil {
+        return err
+    }
     if err := course.Validate(); err != nil {
         return err
     }
+    if err := checkPrerequisiteGraph(ctx, s.repo, course); err != nil {
+        return err
+    }
 
-    if err := s.repo.Update(ctx, course); err != nil {
+    // Capacity edits apply as a change to the seats read above, so seats
+    // taken meanwhile stay taken
+    seatDelta := course.AvailableSeats - previous.AvailableSeats
+    updated, err := s.repo.UpdateWithSeats(ctx, course, seatDelta)
+    if err != nil {
         return err
     }
+    // Counters are never taken from the request; index the stored ones
+    course.AvailableSeats = updated.AvailableSeats
+    course.HeldSeats = updated.HeldSeats
+    course.OfferedSeats = updated.OfferedSeats
+    course.AverageRating = updated.AverageRating
+    course.ReviewCount = updated.ReviewCount
+
+    // Extra capacity goes to the waitlist before anyone else
+    if seatDelta > 0 && s.onSeatsReleased != nil {
+        if err := s.onSeatsReleased.OnSeatsReleased(ctx, course.ID); err != nil {
+            return err
+        }
+    }
 
     // Publish course update event
-    return s.messageQueue.PublishCourseUpdate(course, "upsert")
+    return s.syncSearchIndex(course)
 }
 
-func (s *CourseService) DeleteCourse(ctx context.Context, id primitive.ObjectID) error {
+// ChangeStatus moves a course to a new lifecycle state if the transition is allowed.
+func (s *CourseService) ChangeStatus(ctx context.Context, id primitive.ObjectID, status string) (*models.Course, error) {
+    if !models.IsValidCourseStatus(status) {
+        return nil, models.ErrInvalidCourseStatus
+    }
+
+    course, err := s.repo.FindByID(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+    if !course.CanTransitionTo(status) {
+        return nil, models.ErrInvalidTransition
+    }
+
+    if err := s.repo.UpdateStatus(ctx, id, course.CurrentStatus(), status); err != nil {
+        return nil, err
+    }
+
+    updated, err := s.repo.FindByID(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+    return updated, s.syncSearchIndex(updated)
+}
+
+// ScheduleCourse sets or clears the automatic publish and unpublish times.
+func (s *CourseService) ScheduleCourse(ctx context.Context, id primitive.ObjectID, publishAt, unpublishAt *time.Time) (*models.Course, error) {
+    if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+        return nil, models.ErrInvalidSchedule
+    }
+
+    if err := s.repo.UpdateSchedule(ctx, id, publishAt, unpublishAt); err != nil {
+        return nil, err
+    }
+    return s.repo.FindByID(ctx, id)
+}
+
+// ApplySchedules publishes draft courses whose publish time has passed and
+// archives published courses whose unpublish time has passed.
+func (s *CourseService) ApplySchedules(ctx context.Context, now time.Time) error {
+    courses, err := s.repo.FindScheduleDue(ctx, now)
+    if err != nil {
+        return err
+    }
+
+    for _, course := range courses {
+        to := models.CourseStatusPublished
+        if course.CurrentStatus() == models.CourseStatusPublished {
+            to = models.CourseStatusArchived
+        }
+
+        // Another replica may already have applied this transition
+        if err := s.repo.UpdateStatus(ctx, course.ID, course.CurrentStatus(), to); err != nil {
+            if err == models.ErrInvalidTransition {
+                continue
+            }
+            return err
+        }
+
+        course.Status = to
+        if to == models.CourseStatusPublished {
+            course.PublishAt = nil
+        } else {
+            course.UnpublishAt = nil
+        }
+        if err := s.syncSearchIndex(&course); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// syncSearchIndex keeps the search index limited to published courses.
+func (s *CourseService) syncSearchIndex(course *models.Course) error {
+    return indexCourse(s.messageQueue, course)
+}
+
+func indexCourse(messageQueue MessageQueue, course *models.Course) error {
+    if course.IsPublished() {
+        return messageQueue.PublishCourseUpdate(course, "upsert")
+    }
+    return messageQueue.PublishCourseDelete(course.ID)
+}
+
+// DeleteCourse removes a course if the caller is an admin or teaches it.
+func (s *CourseService) DeleteCourse(ctx context.Context, id primitive.ObjectID, userID int, admin bool) error {
+    if !admin {
+        course, err := s.repo.FindByID(ctx, id)
+        if err != nil {
+            return err
+        }
+        if !course.IsTaughtBy(userID) {
+            return models.ErrForbidden
+        }
+    }
+
     if err := s.repo.Delete(ctx, id); err != nil {
         return err
     }
@@ -64,7 +282,44 @@ func (s *CourseService) DeleteCourse(ctx context.Context, id primitive.ObjectID)
     return s.messageQueue.PublishCourseDelete(id)
 }
 
-func (s *CourseService) CheckAvailability(ctx context.Context, courseIDs []primitive.ObjectID) (map[string]int, error) {
+// resolveInstructors checks that instructors added to the course have
+// profiles, and keeps the Instructor display name in line with them.
+// Instructors already on the course are kept even if their profile is gone.
+func (s *CourseService) resolveInstructors(ctx context.Context, course *models.Course, previous []int) error {
+    if len(course.InstructorIDs) == 0 {
+        return nil
+    }
+
+    profiles, err := s.instructorRepo.FindByUserIDs(ctx, course.InstructorIDs)
+    if err != nil {
+        return err
+    }
+    names := make(map[int]string, len(profiles))
+    for _, profile := range profiles {
+        names[profile.UserID] = profile.DisplayName
+    }
+    existing := &models.Course{InstructorIDs: previous}
+
+    seen := make(map[int]bool, len(course.InstructorIDs))
+    var displayNames []string
+    for _, id := range course.InstructorIDs {
+        name, ok := names[id]
+        if seen[id] || (!ok && !existing.IsTaughtBy(id)) {
+            return models.ErrUnknownInstructor
+        }
+        seen[id] = true
+        if ok {
+            displayNames = append(displayNames, name)
+        }
+    }
+
+    if len(displayNames) > 0 {
+        course.Instructor = strings.Join(displayNames, ", ")
+    }
+    return nil
+}
+
+func (s *CourseService) CheckAvailability(ctx context.Context, courseIDs []primitive.ObjectID) (map[string]models.SeatAvailability, error) {
     return s.repo.CheckAvailability(ctx, courseIDs)
 }
 
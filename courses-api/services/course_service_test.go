@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type profileTable map[int]models.InstructorProfile
+
+func (t profileTable) FindByUserID(ctx context.Context, userID int) (*models.InstructorProfile, error) {
+	profile, ok := t[userID]
+	if !ok {
+		return nil, models.ErrInstructorNotFound
+	}
+	return &profile, nil
+}
+
+func (t profileTable) FindByUserIDs(ctx context.Context, userIDs []int) ([]models.InstructorProfile, error) {
+	var profiles []models.InstructorProfile
+	for _, id := range userIDs {
+		if profile, ok := t[id]; ok {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles, nil
+}
+
+func (t profileTable) Save(ctx context.Context, profile *models.InstructorProfile) error {
+	t[profile.UserID] = *profile
+	return nil
+}
+
+func (t profileTable) Delete(ctx context.Context, userID int) error {
+	delete(t, userID)
+	return nil
+}
+
+// upsertQueue keeps the courses sent to the search index.
+type upsertQueue struct {
+	discardQueue
+	upserts []models.Course
+}
+
+func (q *upsertQueue) PublishCourseUpdate(course *models.Course, action string) error {
+	q.upserts = append(q.upserts, *course)
+	return nil
+}
+
+type createdCourseRepo struct {
+	memoryCourseRepo
+}
+
+func (r *createdCourseRepo) Create(ctx context.Context, course *models.Course) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.course = *course
+	return nil
+}
+
+func TestCourseStatusIsAdminOnly(t *testing.T) {
+	const instructor = 7
+	publishAt := time.Now().Add(time.Hour)
+	newCourse := func() *models.Course {
+		return &models.Course{
+			Title:          "Go",
+			Description:    "Concurrency",
+			Duration:       10,
+			AvailableSeats: 20,
+			Category:       models.DefaultCategories[0].Slug,
+			Status:         models.CourseStatusPublished,
+			PublishAt:      &publishAt,
+		}
+	}
+	repo := &createdCourseRepo{}
+	service := NewCourseService(repo, nil, profileTable{instructor: {UserID: instructor, DisplayName: "Gopher"}}, discardQueue{}, nil)
+	ctx := context.Background()
+
+	t.Run("instructors create drafts", func(t *testing.T) {
+		require.NoError(t, service.CreateCourse(ctx, newCourse(), instructor, false))
+		assert.Equal(t, models.CourseStatusDraft, repo.course.Status)
+		assert.Nil(t, repo.course.PublishAt)
+	})
+
+	t.Run("instructors cannot publish on update", func(t *testing.T) {
+		edit := newCourse()
+		edit.ID = repo.course.ID
+		edit.InstructorIDs = []int{instructor}
+		require.NoError(t, service.UpdateCourse(ctx, edit, instructor, false))
+		assert.Equal(t, models.CourseStatusDraft, repo.course.Status)
+		assert.Nil(t, repo.course.PublishAt)
+	})
+
+	t.Run("admins set the status", func(t *testing.T) {
+		edit := newCourse()
+		edit.ID = repo.course.ID
+		edit.InstructorIDs = []int{instructor}
+		require.NoError(t, service.UpdateCourse(ctx, edit, 1, true))
+		assert.Equal(t, models.CourseStatusPublished, repo.course.Status)
+		assert.Equal(t, &publishAt, repo.course.PublishAt)
+	})
+}
+
+func TestUpdateCourseKeepsStatusWhenOmitted(t *testing.T) {
+	repo := &createdCourseRepo{}
+	repo.course = models.Course{
+		Title:          "Go",
+		Description:    "Concurrency",
+		Instructor:     "Gopher",
+		Duration:       10,
+		AvailableSeats: 20,
+		Category:       models.DefaultCategories[0].Slug,
+		Status:         models.CourseStatusDraft,
+	}
+	queue := &upsertQueue{}
+	service := NewCourseService(repo, nil, nil, queue, nil)
+
+	edit := repo.course
+	edit.Status = ""
+	edit.Description = "Channels"
+	require.NoError(t, service.UpdateCourse(context.Background(), &edit, 1, true))
+
+	assert.Equal(t, models.CourseStatusDraft, repo.course.Status)
+	assert.Empty(t, queue.upserts)
+}
+
+func TestUpdateCourseIndexesStoredRating(t *testing.T) {
+	repo := &createdCourseRepo{}
+	repo.course = models.Course{
+		Title:          "Go",
+		Description:    "Concurrency",
+		Instructor:     "Gopher",
+		Duration:       10,
+		AvailableSeats: 20,
+		Category:       models.DefaultCategories[0].Slug,
+		Status:         models.CourseStatusPublished,
+		AverageRating:  4.5,
+		ReviewCount:    8,
+	}
+	queue := &upsertQueue{}
+	service := NewCourseService(repo, nil, nil, queue, nil)
+
+	edit := repo.course
+	edit.AverageRating = 0
+	edit.ReviewCount = 0
+	require.NoError(t, service.UpdateCourse(context.Background(), &edit, 1, true))
+
+	require.Len(t, queue.upserts, 1)
+	assert.Equal(t, 4.5, queue.upserts[0].AverageRating)
+	assert.Equal(t, 8, queue.upserts[0].ReviewCount)
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"courses-api/models"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// courseColumns are the CSV columns, in export order. Lists are separated
+// by semicolons and times use RFC 3339. Sessions and other nested data are
+// only carried by the JSON formats.
+var courseColumns = []string{
+	"external_key", "id", "title", "description", "instructor", "instructor_ids",
+	"duration", "available_seats", "price", "currency", "category", "tags",
+	"image_url", "status", "start_date", "end_date", "time_zone",
+	"enrollment_opens_at", "enrollment_closes_at", "prerequisites",
+}
+
+// courseEncoder writes courses in one of the export formats.
+type courseEncoder interface {
+	Encode(course *models.Course) error
+	Close() error
+}
+
+func newCourseEncoder(format string, w io.Writer) (courseEncoder, error) {
+	switch format {
+	case models.CourseFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(courseColumns); err != nil {
+			return nil, err
+		}
+		return &csvCourseEncoder{writer: writer}, nil
+	case models.CourseFormatJSON:
+		return &jsonCourseEncoder{w: w}, nil
+	case models.CourseFormatNDJSON:
+		return &ndjsonCourseEncoder{encoder: json.NewEncoder(w)}, nil
+	}
+	return nil, models.ErrUnsupportedFormat
+}
+
+type csvCourseEncoder struct {
+	writer *csv.Writer
+}
+
+func (e *csvCourseEncoder) Encode(c *models.Course) error {
+	instructorIDs := make([]string, len(c.InstructorIDs))
+	for i, id := range c.InstructorIDs {
+		instructorIDs[i] = strconv.Itoa(id)
+	}
+	prerequisites := make([]string, len(c.Prerequisites))
+	for i, id := range c.Prerequisites {
+		prerequisites[i] = id.Hex()
+	}
+
+	return e.writer.Write([]string{
+		c.ExternalKey, c.ID.Hex(), c.Title, c.Description, c.Instructor,
+		strings.Join(instructorIDs, ";"),
+		strconv.Itoa(c.Duration), strconv.Itoa(c.AvailableSeats),
+		strconv.FormatInt(c.Price, 10), c.Currency, c.Category,
+		strings.Join(c.Tags, ";"),
+		c.ImageURL, c.Status, formatCSVTime(c.StartDate), formatCSVTime(c.EndDate), c.TimeZone,
+		formatCSVTime(c.EnrollmentOpensAt), formatCSVTime(c.EnrollmentClosesAt),
+		strings.Join(prerequisites, ";"),
+	})
+}
+
+func (e *csvCourseEncoder) Close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// jsonCourseEncoder writes a single array, one element at a time.
+type jsonCourseEncoder struct {
+	w       io.Writer
+	started bool
+}
+
+func (e *jsonCourseEncoder) Encode(c *models.Course) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	separator := ",\n"
+	if !e.started {
+		separator = "[\n"
+		e.started = true
+	}
+	_, err = io.WriteString(e.w, separator+string(data))
+	return err
+}
+
+func (e *jsonCourseEncoder) Close() error {
+	closing := "\n]\n"
+	if !e.started {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(e.w, closing)
+	return err
+}
+
+type ndjsonCourseEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonCourseEncoder) Encode(c *models.Course) error {
+	return e.encoder.Encode(c)
+}
+
+func (e *ndjsonCourseEncoder) Close() error {
+	return nil
+}
+
+func formatCSVTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// rowError marks a row that could not be parsed; the rows after it can
+// still be read.
+type rowError struct {
+	err error
+}
+
+func (e *rowError) Error() string {
+	return e.err.Error()
+}
+
+// courseDecoder reads courses in one of the import formats. Next returns
+// io.EOF after the last row, a *rowError for a row that should be reported
+// and skipped, and any other error when the input cannot be read further.
+type courseDecoder interface {
+	Next() (*models.Course, error)
+}
+
+func newCourseDecoder(format string, r io.Reader) (courseDecoder, error) {
+	switch format {
+	case models.CourseFormatCSV:
+		reader := csv.NewReader(r)
+		header, err := reader.Read()
+		if err == io.EOF {
+			return nil, fmt.Errorf("the CSV file has no header row")
+		}
+		if err != nil {
+			return nil, err
+		}
+		known := make(map[string]bool, len(courseColumns))
+		for _, column := range courseColumns {
+			known[column] = true
+		}
+		for i, column := range header {
+			header[i] = strings.ToLower(strings.TrimSpace(column))
+			if !known[header[i]] {
+				return nil, fmt.Errorf("unknown CSV column %q", column)
+			}
+		}
+		return &csvCourseDecoder{reader: reader, header: header}, nil
+	case models.CourseFormatJSON:
+		decoder := json.NewDecoder(r)
+		token, err := decoder.Token()
+		if err != nil || token != json.Delim('[') {
+			return nil, fmt.Errorf("the JSON import must be an array of courses")
+		}
+		return &jsonCourseDecoder{decoder: decoder}, nil
+	case models.CourseFormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64<<10), 4<<20)
+		return &ndjsonCourseDecoder{scanner: scanner}, nil
+	}
+	return nil, models.ErrUnsupportedFormat
+}
+
+type csvCourseDecoder struct {
+	reader *csv.Reader
+	header []string
+}
+
+func (d *csvCourseDecoder) Next() (*models.Course, error) {
+	record, err := d.reader.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		// Rows with the wrong number of fields can be skipped; anything
+		// else, such as a broken quote, leaves the reader lost
+		if parseErr, ok := err.(*csv.ParseError); ok && parseErr.Err == csv.ErrFieldCount {
+			return nil, &rowError{err: parseErr}
+		}
+		return nil, err
+	}
+
+	var course models.Course
+	for i, column := range d.header {
+		if err := setCourseColumn(&course, column, strings.TrimSpace(record[i])); err != nil {
+			return nil, &rowError{err: fmt.Errorf("%s: %w", column, err)}
+		}
+	}
+	return &course, nil
+}
+
+// setCourseColumn parses one CSV cell into the course. Empty cells leave
+// the field unset; the id column is ignored because rows are matched by
+// external key.
+func setCourseColumn(c *models.Course, column, value string) error {
+	if value == "" {
+		return nil
+	}
+	var err error
+	switch column {
+	case "external_key":
+		c.ExternalKey = value
+	case "title":
+		c.Title = value
+	case "description":
+		c.Description = value
+	case "instructor":
+		c.Instructor = value
+	case "instructor_ids":
+		for _, part := range strings.Split(value, ";") {
+			id, convErr := strconv.Atoi(strings.TrimSpace(part))
+			if convErr != nil {
+				return fmt.Errorf("invalid user ID %q", part)
+			}
+			c.InstructorIDs = append(c.InstructorIDs, id)
+		}
+	case "duration":
+		c.Duration, err = strconv.Atoi(value)
+	case "available_seats":
+		c.AvailableSeats, err = strconv.Atoi(value)
+	case "price":
+		c.Price, err = strconv.ParseInt(value, 10, 64)
+	case "currency":
+		c.Currency = value
+	case "category":
+		c.Category = value
+	case "tags":
+		c.Tags = strings.Split(value, ";")
+	case "image_url":
+		c.ImageURL = value
+	case "status":
+		c.Status = value
+	case "start_date":
+		c.StartDate, err = parseCSVTime(value)
+	case "end_date":
+		c.EndDate, err = parseCSVTime(value)
+	case "time_zone":
+		c.TimeZone = value
+	case "enrollment_opens_at":
+		c.EnrollmentOpensAt, err = parseCSVTime(value)
+	case "enrollment_closes_at":
+		c.EnrollmentClosesAt, err = parseCSVTime(value)
+	case "prerequisites":
+		for _, part := range strings.Split(value, ";") {
+			id, hexErr := primitive.ObjectIDFromHex(strings.TrimSpace(part))
+			if hexErr != nil {
+				return fmt.Errorf("invalid course ID %q", part)
+			}
+			c.Prerequisites = append(c.Prerequisites, id)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value %q", value)
+	}
+	return nil
+}
+
+func parseCSVTime(value string) (*time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+type jsonCourseDecoder struct {
+	decoder *json.Decoder
+}
+
+func (d *jsonCourseDecoder) Next() (*models.Course, error) {
+	if !d.decoder.More() {
+		if token, err := d.decoder.Token(); err != nil || token != json.Delim(']') {
+			return nil, fmt.Errorf("the JSON import must be an array of courses")
+		}
+		return nil, io.EOF
+	}
+
+	var course models.Course
+	if err := d.decoder.Decode(&course); err != nil {
+		// A value of the wrong type is consumed whole, so the next element
+		// can still be read; syntax errors cannot be recovered from
+		if _, ok := err.(*json.UnmarshalTypeError); ok {
+			return nil, &rowError{err: err}
+		}
+		return nil, err
+	}
+	return &course, nil
+}
+
+type ndjsonCourseDecoder struct {
+	scanner *bufio.Scanner
+}
+
+func (d *ndjsonCourseDecoder) Next() (*models.Course, error) {
+	for d.scanner.Scan() {
+		line := bytes.TrimSpace(d.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var course models.Course
+		if err := json.Unmarshal(line, &course); err != nil {
+			return nil, &rowError{err: err}
+		}
+		return &course, nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"courses-api/models"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type keyedCourseRepo struct {
+	models.CourseRepository
+	courses []models.Course
+}
+
+func (r *keyedCourseRepo) FindByExternalKey(ctx context.Context, key string) (*models.Course, error) {
+	for _, course := range r.courses {
+		if course.ExternalKey == key {
+			return &course, nil
+		}
+	}
+	return nil, models.ErrCourseNotFound
+}
+
+func (r *keyedCourseRepo) Create(ctx context.Context, course *models.Course) error {
+	for _, existing := range r.courses {
+		if existing.Title == course.Title && existing.Instructor == course.Instructor {
+			return errors.New("a course with the same title and instructor already exists")
+		}
+	}
+	course.ID = primitive.NewObjectID()
+	r.courses = append(r.courses, *course)
+	return nil
+}
+
+func (r *keyedCourseRepo) UpdateWithSeats(ctx context.Context, course *models.Course, seatDelta int) (*models.Course, error) {
+	for i, existing := range r.courses {
+		if existing.ID == course.ID {
+			updated := *course
+			updated.AvailableSeats = existing.AvailableSeats + seatDelta
+			updated.HeldSeats = existing.HeldSeats
+			r.courses[i] = updated
+			return &updated, nil
+		}
+	}
+	return nil, errors.New("course not found")
+}
+
+func (r *keyedCourseRepo) ForEach(ctx context.Context, fn func(models.Course) error) error {
+	for _, course := range r.courses {
+		if err := fn(course); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type recordingQueue struct {
+	discardQueue
+	updates []primitive.ObjectID
+}
+
+func (q *recordingQueue) PublishCourseUpdate(course *models.Course, action string) error {
+	q.updates = append(q.updates, course.ID)
+	return nil
+}
+
+func (q *recordingQueue) PublishCourseDelete(courseID interface{}) error {
+	q.updates = append(q.updates, courseID.(primitive.ObjectID))
+	return nil
+}
+
+const importCSV = `external_key,title,description,instructor,duration,available_seats,category,tags,status
+go-101,Go Basics,Learn Go,Ana,10,30,web-development,go;backend,published
+bad-1,No Category,Missing category,Ana,10,30,,,
+,No Key,Has no key,Ana,10,30,web-development,,
+go-101,Go Again,Duplicate key,Ana,10,30,web-development,,
+py-201,Python,Data work,Luis,x,30,data-science,,
+`
+
+func TestImportCoursesReportsEachRow(t *testing.T) {
+	repo := &keyedCourseRepo{}
+	queue := &recordingQueue{}
+	service := NewCourseService(repo, nil, nil, queue, nil)
+
+	result, err := service.ImportCourses(context.Background(), models.CourseFormatCSV, strings.NewReader(importCSV), false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 4, result.Failed)
+	require.Len(t, result.Rows, 5)
+	assert.Equal(t, models.ImportCreate, result.Rows[0].Action)
+	assert.Equal(t, models.ErrMissingExternalKey.Error(), result.Rows[2].Error)
+	assert.Equal(t, models.ErrDuplicateExternalKey.Error(), result.Rows[3].Error)
+	assert.Contains(t, result.Rows[4].Error, "duration")
+
+	require.Len(t, repo.courses, 1)
+	assert.Equal(t, []string{"go", "backend"}, repo.courses[0].Tags)
+	assert.Equal(t, []primitive.ObjectID{repo.courses[0].ID}, queue.updates)
+}
+
+func TestImportCoursesUpdatesByExternalKey(t *testing.T) {
+	existing := models.Course{
+		ID: primitive.NewObjectID(), ExternalKey: "go-101", Title: "Go Basics", Description: "Old",
+		Instructor: "Ana", Duration: 5, AvailableSeats: 10, HeldSeats: 2, Category: "web-development",
+		Status: models.CourseStatusArchived, AverageRating: 4.5, ReviewCount: 8,
+	}
+	repo := &keyedCourseRepo{courses: []models.Course{existing}}
+	queue := &recordingQueue{}
+	service := NewCourseService(repo, nil, nil, queue, nil)
+
+	input := `{"external_key":"go-101","title":"Go Basics","description":"New","instructor":"Ana","duration":12,"available_seats":40,"category":"web-development","average_rating":1}
+{"external_key":"go-102","title":"Go Advanced","description":"More Go","instructor":"Ana","duration":12,"available_seats":40,"category":"web-development"}
+`
+	result, err := service.ImportCourses(context.Background(), models.CourseFormatNDJSON, strings.NewReader(input), false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, existing.ID, *result.Rows[0].CourseID)
+
+	updated := repo.courses[0]
+	assert.Equal(t, "New", updated.Description)
+	assert.Equal(t, 4.5, updated.AverageRating)
+	assert.Equal(t, 8, updated.ReviewCount)
+	assert.Equal(t, 40, updated.AvailableSeats)
+	assert.Equal(t, 2, updated.HeldSeats)
+	assert.Equal(t, models.CourseStatusArchived, updated.Status)
+	assert.Equal(t, models.CourseStatusDraft, repo.courses[1].Status)
+	assert.Len(t, queue.updates, 2)
+}
+
+func TestImportCoursesDryRunWritesNothing(t *testing.T) {
+	repo := &keyedCourseRepo{}
+	queue := &recordingQueue{}
+	service := NewCourseService(repo, nil, nil, queue, nil)
+
+	input := `[{"external_key":"go-101","title":"Go","description":"Go","instructor":"Ana","duration":1,"available_seats":1,"category":"web-development"},
+{"external_key":"go-102","duration":"long"},
+{"external_key":"go-103","title":"Go 3","description":"Go","instructor":"Ana","duration":1,"available_seats":1,"category":"web-development"}]`
+	result, err := service.ImportCourses(context.Background(), models.CourseFormatJSON, strings.NewReader(input), true)
+	require.NoError(t, err)
+
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, models.ImportError, result.Rows[1].Action)
+	assert.Empty(t, repo.courses)
+	assert.Empty(t, queue.updates)
+}
+
+func TestImportCoursesStopsAtBrokenInput(t *testing.T) {
+	service := NewCourseService(&keyedCourseRepo{}, nil, nil, &recordingQueue{}, nil)
+
+	result, err := service.ImportCourses(context.Background(), models.CourseFormatJSON,
+		strings.NewReader(`[{"external_key":"a","title":"A","description":"A","instructor":"Ana","duration":1,"available_seats":1,"category":"web-development"}, {oops`), false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Failed)
+
+	_, err = service.ImportCourses(context.Background(), models.CourseFormatCSV, strings.NewReader("title,colour\n"), false)
+	assert.ErrorContains(t, err, "unknown CSV column")
+}
+
+func TestExportCoursesRoundTripsCSV(t *testing.T) {
+	start := time.Date(2024, 9, 1, 9, 0, 0, 0, time.UTC)
+	prerequisite := primitive.NewObjectID()
+	repo := &keyedCourseRepo{courses: []models.Course{{
+		ID: primitive.NewObjectID(), ExternalKey: "go-101", Title: "Go, the basics", Description: "Line one\nline two",
+		Instructor: "Ana", InstructorIDs: []int{3, 4}, Duration: 10, AvailableSeats: 30, Price: 1999, Currency: "USD",
+		Category: "web-development", Tags: []string{"go", "backend"}, StartDate: &start, Prerequisites: []primitive.ObjectID{prerequisite},
+	}}}
+	service := NewCourseService(repo, nil, nil, &recordingQueue{}, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, service.ExportCourses(context.Background(), models.CourseFormatCSV, &buf))
+
+	decoder, err := newCourseDecoder(models.CourseFormatCSV, &buf)
+	require.NoError(t, err)
+	course, err := decoder.Next()
+	require.NoError(t, err)
+
+	want := repo.courses[0]
+	want.ID = primitive.NilObjectID
+	assert.Equal(t, want, *course)
+}
+
+func TestExportCoursesJSON(t *testing.T) {
+	service := NewCourseService(&keyedCourseRepo{}, nil, nil, &recordingQueue{}, nil)
+	var buf bytes.Buffer
+	require.NoError(t, service.ExportCourses(context.Background(), models.CourseFormatJSON, &buf))
+	assert.Equal(t, "[]\n", buf.String())
+
+	assert.Equal(t, models.ErrUnsupportedFormat, service.ExportCourses(context.Background(), "xml", &buf))
+}
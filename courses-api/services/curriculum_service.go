@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CurriculumService struct {
+	sectionRepo    models.SectionRepository
+	lessonRepo     models.LessonRepository
+	courseRepo     models.CourseRepository
+	enrollmentRepo models.EnrollmentRepository
+}
+
+func NewCurriculumService(
+	sectionRepo models.SectionRepository,
+	lessonRepo models.LessonRepository,
+	courseRepo models.CourseRepository,
+	enrollmentRepo models.EnrollmentRepository,
+) *CurriculumService {
+	return &CurriculumService{
+		sectionRepo:    sectionRepo,
+		lessonRepo:     lessonRepo,
+		courseRepo:     courseRepo,
+		enrollmentRepo: enrollmentRepo,
+	}
+}
+
+// GetCurriculum returns the ordered sections of a course with their lessons.
+// Lesson content is only included for enrolled users, admins, and free previews.
+func (s *CurriculumService) GetCurriculum(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool) ([]models.Section, error) {
+	canAccess, err := s.canAccessContent(ctx, courseID, userID, admin)
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := s.sectionRepo.FindByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	lessons, err := s.lessonRepo.FindByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	bySection := make(map[primitive.ObjectID][]models.Lesson)
+	for _, lesson := range lessons {
+		if !canAccess && !lesson.FreePreview {
+			lesson.StripContent()
+		}
+		bySection[lesson.SectionID] = append(bySection[lesson.SectionID], lesson)
+	}
+
+	for i := range sections {
+		sections[i].Lessons = bySection[sections[i].ID]
+	}
+	if sections == nil {
+		sections = []models.Section{}
+	}
+	return sections, nil
+}
+
+// GetLesson returns a single lesson with its content if the user may see it.
+func (s *CurriculumService) GetLesson(ctx context.Context, courseID, lessonID primitive.ObjectID, userID int, admin bool) (*models.Lesson, error) {
+	canAccess, err := s.canAccessContent(ctx, courseID, userID, admin)
+	if err != nil {
+		return nil, err
+	}
+
+	lesson, err := s.findLesson(ctx, courseID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccess && !lesson.FreePreview {
+		return nil, models.ErrLessonLocked
+	}
+	return lesson, nil
+}
+
+func (s *CurriculumService) CreateSection(ctx context.Context, section *models.Section) error {
+	if err := section.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.courseRepo.FindByID(ctx, section.CourseID); err != nil {
+		return err
+	}
+	return s.sectionRepo.Create(ctx, section)
+}
+
+func (s *CurriculumService) UpdateSection(ctx context.Context, section *models.Section) error {
+	if err := section.Validate(); err != nil {
+		return err
+	}
+	existing, err := s.findSection(ctx, section.CourseID, section.ID)
+	if err != nil {
+		return err
+	}
+
+	existing.Title = section.Title
+	if err := s.sectionRepo.Update(ctx, existing); err != nil {
+		return err
+	}
+	*section = *existing
+	return nil
+}
+
+// DeleteSection removes a section together with its lessons.
+func (s *CurriculumService) DeleteSection(ctx context.Context, courseID, sectionID primitive.ObjectID) error {
+	if _, err := s.findSection(ctx, courseID, sectionID); err != nil {
+		return err
+	}
+	if err := s.lessonRepo.DeleteBySectionID(ctx, sectionID); err != nil {
+		return err
+	}
+	return s.sectionRepo.Delete(ctx, sectionID)
+}
+
+// ReorderSections sets the section order of a course. ids must list every
+// section of the course exactly once.
+func (s *CurriculumService) ReorderSections(ctx context.Context, courseID primitive.ObjectID, ids []primitive.ObjectID) error {
+	sections, err := s.sectionRepo.FindByCourseID(ctx, courseID)
+	if err != nil {
+		return err
+	}
+
+	existing := make([]primitive.ObjectID, 0, len(sections))
+	for _, section := range sections {
+		existing = append(existing, section.ID)
+	}
+	if !sameIDs(existing, ids) {
+		return models.ErrInvalidOrder
+	}
+	return s.sectionRepo.Reorder(ctx, courseID, ids)
+}
+
+func (s *CurriculumService) CreateLesson(ctx context.Context, lesson *models.Lesson) error {
+	if err := lesson.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.findSection(ctx, lesson.CourseID, lesson.SectionID); err != nil {
+		return err
+	}
+	return s.lessonRepo.Create(ctx, lesson)
+}
+
+func (s *CurriculumService) UpdateLesson(ctx context.Context, lesson *models.Lesson) error {
+	if err := lesson.Validate(); err != nil {
+		return err
+	}
+	existing, err := s.findLesson(ctx, lesson.CourseID, lesson.ID)
+	if err != nil {
+		return err
+	}
+
+	// Lessons keep their section and position; use reorder to move them
+	lesson.SectionID = existing.SectionID
+	lesson.Position = existing.Position
+	return s.lessonRepo.Update(ctx, lesson)
+}
+
+func (s *CurriculumService) DeleteLesson(ctx context.Context, courseID, lessonID primitive.ObjectID) error {
+	if _, err := s.findLesson(ctx, courseID, lessonID); err != nil {
+		return err
+	}
+	return s.lessonRepo.Delete(ctx, lessonID)
+}
+
+// ReorderLessons sets the lesson order of a section. ids must list every
+// lesson of the section exactly once.
+func (s *CurriculumService) ReorderLessons(ctx context.Context, courseID, sectionID primitive.ObjectID, ids []primitive.ObjectID) error {
+	if _, err := s.findSection(ctx, courseID, sectionID); err != nil {
+		return err
+	}
+
+	lessons, err := s.lessonRepo.FindBySectionID(ctx, sectionID)
+	if err != nil {
+		return err
+	}
+
+	existing := make([]primitive.ObjectID, 0, len(lessons))
+	for _, lesson := range lessons {
+		existing = append(existing, lesson.ID)
+	}
+	if !sameIDs(existing, ids) {
+		return models.ErrInvalidOrder
+	}
+	return s.lessonRepo.Reorder(ctx, sectionID, ids)
+}
+
+// canAccessContent reports whether the user may see full lesson content.
+// Unpublished courses are hidden from everyone but admins.
+func (s *CurriculumService) canAccessContent(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool) (bool, error) {
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return false, err
+	}
+	if admin {
+		return true, nil
+	}
+	if !course.IsPublished() {
+		return false, models.ErrCourseNotFound
+	}
+	if userID == 0 {
+		return false, nil
+	}
+	return s.enrollmentRepo.CheckEnrollment(ctx, courseID, userID)
+}
+
+func (s *CurriculumService) findSection(ctx context.Context, courseID, sectionID primitive.ObjectID) (*models.Section, error) {
+	section, err := s.sectionRepo.FindByID(ctx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	if section.CourseID != courseID {
+		return nil, models.ErrSectionNotFound
+	}
+	return section, nil
+}
+
+func (s *CurriculumService) findLesson(ctx context.Context, courseID, lessonID primitive.ObjectID) (*models.Lesson, error) {
+	lesson, err := s.lessonRepo.FindByID(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson.CourseID != courseID {
+		return nil, models.ErrLessonNotFound
+	}
+	return lesson, nil
+}
+
+// sameIDs reports whether ids contains exactly the elements of existing.
+func sameIDs(existing, ids []primitive.ObjectID) bool {
+	if len(existing) != len(ids) {
+		return false
+	}
+
+	remaining := make(map[primitive.ObjectID]bool, len(existing))
+	for _, id := range existing {
+		remaining[id] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
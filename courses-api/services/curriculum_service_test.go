@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/repositories/mongodb"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TestCurriculumPositionsMongo checks that new sections and lessons never
+// reuse a position, e.g. MONGO_TEST_URI=mongodb://localhost:27017
+func TestCurriculumPositionsMongo(t *testing.T) {
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI not set")
+	}
+
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	require.NoError(t, err)
+	defer client.Disconnect(ctx)
+
+	db := client.Database("courses_test_" + primitive.NewObjectID().Hex())
+	defer db.Drop(ctx)
+
+	sections := mongodb.NewSectionRepository(db)
+	lessons := mongodb.NewLessonRepository(db)
+	courseID := primitive.NewObjectID()
+
+	t.Run("create after delete", func(t *testing.T) {
+		created := make([]models.Section, 3)
+		for i := range created {
+			created[i] = models.Section{CourseID: courseID, Title: "Section"}
+			require.NoError(t, sections.Create(ctx, &created[i]))
+		}
+		require.NoError(t, sections.Delete(ctx, created[1].ID))
+
+		section := models.Section{CourseID: courseID, Title: "Section"}
+		require.NoError(t, sections.Create(ctx, &section))
+		assert.Equal(t, 3, section.Position)
+	})
+
+	t.Run("concurrent creates", func(t *testing.T) {
+		sectionID := primitive.NewObjectID()
+		positions := make([]int, 20)
+		var wg sync.WaitGroup
+		for i := range positions {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				lesson := models.Lesson{CourseID: courseID, SectionID: sectionID, Title: "Lesson", Type: models.LessonTypeText}
+				assert.NoError(t, lessons.Create(ctx, &lesson))
+				positions[i] = lesson.Position
+			}(i)
+		}
+		wg.Wait()
+
+		seen := make(map[int]bool, len(positions))
+		for _, position := range positions {
+			assert.False(t, seen[position], "position %d handed out twice", position)
+			seen[position] = true
+		}
+	})
+}
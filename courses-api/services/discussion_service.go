@@ -0,0 +1,404 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultDiscussionPageSize = 20
+	maxDiscussionPageSize     = 100
+)
+
+type DiscussionService struct {
+	threadRepo     models.ThreadRepository
+	replyRepo      models.ReplyRepository
+	courseRepo     models.CourseRepository
+	enrollmentRepo models.EnrollmentRepository
+	users          models.UserDirectory
+}
+
+func NewDiscussionService(
+	threadRepo models.ThreadRepository,
+	replyRepo models.ReplyRepository,
+	courseRepo models.CourseRepository,
+	enrollmentRepo models.EnrollmentRepository,
+	users models.UserDirectory,
+) *DiscussionService {
+	return &DiscussionService{
+		threadRepo:     threadRepo,
+		replyRepo:      replyRepo,
+		courseRepo:     courseRepo,
+		enrollmentRepo: enrollmentRepo,
+		users:          users,
+	}
+}
+
+// ListThreads returns a page of threads, newest first. The first page also
+// carries the pinned threads.
+func (s *DiscussionService) ListThreads(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool, cursor string, limit int) (*models.ThreadPage, error) {
+	if _, err := s.access(ctx, courseID, userID, admin); err != nil {
+		return nil, err
+	}
+
+	before, err := parseCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	limit = pageSize(limit)
+
+	page := &models.ThreadPage{}
+	if before == nil {
+		if page.Pinned, err = s.threadRepo.FindPinned(ctx, courseID); err != nil {
+			return nil, err
+		}
+	}
+
+	threads, err := s.threadRepo.FindPage(ctx, courseID, before, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(threads) > limit {
+		threads = threads[:limit]
+		page.NextCursor = threads[limit-1].ID.Hex()
+	}
+	page.Threads = threads
+	return page, nil
+}
+
+func (s *DiscussionService) GetThread(ctx context.Context, courseID, threadID primitive.ObjectID, userID int, admin bool) (*models.Thread, error) {
+	if _, err := s.access(ctx, courseID, userID, admin); err != nil {
+		return nil, err
+	}
+	return s.findThread(ctx, courseID, threadID)
+}
+
+func (s *DiscussionService) CreateThread(ctx context.Context, thread *models.Thread, admin bool) error {
+	if err := thread.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.access(ctx, thread.CourseID, thread.AuthorID, admin); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	thread.AuthorName = s.authorName(ctx, thread.AuthorID)
+	thread.Pinned = false
+	thread.Upvotes = 0
+	thread.ReplyCount = 0
+	thread.AnswerID = nil
+	thread.CreatedAt = now
+	thread.UpdatedAt = now
+	thread.LastActivityAt = now
+	return s.threadRepo.Create(ctx, thread)
+}
+
+// UpdateThread edits a thread. Authors may edit within the edit window;
+// moderators at any time.
+func (s *DiscussionService) UpdateThread(ctx context.Context, courseID, threadID primitive.ObjectID, userID int, admin bool, title, body string) (*models.Thread, error) {
+	moderator, err := s.access(ctx, courseID, userID, admin)
+	if err != nil {
+		return nil, err
+	}
+	thread, err := s.findThread(ctx, courseID, threadID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWindow(thread.AuthorID, thread.CreatedAt, userID, moderator, models.PostEditWindow, models.ErrEditWindowClosed); err != nil {
+		return nil, err
+	}
+
+	thread.Title = title
+	thread.Body = body
+	thread.UpdatedAt = time.Now()
+	if err := thread.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.threadRepo.Update(ctx, thread); err != nil {
+		return nil, err
+	}
+	return thread, nil
+}
+
+// DeleteThread removes a thread and its replies. Authors may delete within
+// the delete window; moderators at any time.
+func (s *DiscussionService) DeleteThread(ctx context.Context, courseID, threadID primitive.ObjectID, userID int, admin bool) error {
+	moderator, err := s.access(ctx, courseID, userID, admin)
+	if err != nil {
+		return err
+	}
+	thread, err := s.findThread(ctx, courseID, threadID)
+	if err != nil {
+		return err
+	}
+	if err := checkWindow(thread.AuthorID, thread.CreatedAt, userID, moderator, models.PostDeleteWindow, models.ErrDeleteWindowClosed); err != nil {
+		return err
+	}
+
+	if err := s.replyRepo.DeleteByThreadID(ctx, threadID); err != nil {
+		return err
+	}
+	return s.threadRepo.Delete(ctx, threadID)
+}
+
+func (s *DiscussionService) VoteThread(ctx context.Context, courseID, threadID primitive.ObjectID, userID int, admin bool, upvote bool) error {
+	if _, err := s.access(ctx, courseID, userID, admin); err != nil {
+		return err
+	}
+	if _, err := s.findThread(ctx, courseID, threadID); err != nil {
+		return err
+	}
+	if upvote {
+		return s.threadRepo.AddUpvote(ctx, threadID, userID)
+	}
+	return s.threadRepo.RemoveUpvote(ctx, threadID, userID)
+}
+
+// PinThread pins or unpins a thread. Only moderators may pin.
+func (s *DiscussionService) PinThread(ctx context.Context, courseID, threadID primitive.ObjectID, userID int, admin bool, pinned bool) error {
+	moderator, err := s.access(ctx, courseID, userID, admin)
+	if err != nil {
+		return err
+	}
+	if !moderator {
+		return models.ErrForbidden
+	}
+	if _, err := s.findThread(ctx, courseID, threadID); err != nil {
+		return err
+	}
+	return s.threadRepo.SetPinned(ctx, threadID, pinned)
+}
+
+// ListReplies returns a page of a thread's replies in posting order.
+func (s *DiscussionService) ListReplies(ctx context.Context, courseID, threadID primitive.ObjectID, userID int, admin bool, cursor string, limit int) (*models.ReplyPage, error) {
+	if _, err := s.access(ctx, courseID, userID, admin); err != nil {
+		return nil, err
+	}
+	if _, err := s.findThread(ctx, courseID, threadID); err != nil {
+		return nil, err
+	}
+
+	after, err := parseCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	limit = pageSize(limit)
+
+	replies, err := s.replyRepo.FindPage(ctx, threadID, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.ReplyPage{}
+	if len(replies) > limit {
+		replies = replies[:limit]
+		page.NextCursor = replies[limit-1].ID.Hex()
+	}
+	page.Replies = replies
+	return page, nil
+}
+
+func (s *DiscussionService) CreateReply(ctx context.Context, reply *models.Reply, admin bool) error {
+	if err := reply.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.access(ctx, reply.CourseID, reply.AuthorID, admin); err != nil {
+		return err
+	}
+	if _, err := s.findThread(ctx, reply.CourseID, reply.ThreadID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	reply.AuthorName = s.authorName(ctx, reply.AuthorID)
+	reply.Upvotes = 0
+	reply.InstructorAnswer = false
+	reply.CreatedAt = now
+	reply.UpdatedAt = now
+	if err := s.replyRepo.Create(ctx, reply); err != nil {
+		return err
+	}
+	return s.threadRepo.IncrementReplies(ctx, reply.ThreadID, 1)
+}
+
+func (s *DiscussionService) UpdateReply(ctx context.Context, courseID, threadID, replyID primitive.ObjectID, userID int, admin bool, body string) (*models.Reply, error) {
+	moderator, err := s.access(ctx, courseID, userID, admin)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := s.findReply(ctx, courseID, threadID, replyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWindow(reply.AuthorID, reply.CreatedAt, userID, moderator, models.PostEditWindow, models.ErrEditWindowClosed); err != nil {
+		return nil, err
+	}
+
+	reply.Body = body
+	reply.UpdatedAt = time.Now()
+	if err := reply.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.replyRepo.Update(ctx, reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *DiscussionService) DeleteReply(ctx context.Context, courseID, threadID, replyID primitive.ObjectID, userID int, admin bool) error {
+	moderator, err := s.access(ctx, courseID, userID, admin)
+	if err != nil {
+		return err
+	}
+	reply, err := s.findReply(ctx, courseID, threadID, replyID)
+	if err != nil {
+		return err
+	}
+	if err := checkWindow(reply.AuthorID, reply.CreatedAt, userID, moderator, models.PostDeleteWindow, models.ErrDeleteWindowClosed); err != nil {
+		return err
+	}
+
+	if err := s.replyRepo.Delete(ctx, replyID); err != nil {
+		return err
+	}
+	if reply.InstructorAnswer {
+		if err := s.threadRepo.SetAnswer(ctx, threadID, nil); err != nil {
+			return err
+		}
+	}
+	return s.threadRepo.IncrementReplies(ctx, threadID, -1)
+}
+
+func (s *DiscussionService) VoteReply(ctx context.Context, courseID, threadID, replyID primitive.ObjectID, userID int, admin bool, upvote bool) error {
+	if _, err := s.access(ctx, courseID, userID, admin); err != nil {
+		return err
+	}
+	if _, err := s.findReply(ctx, courseID, threadID, replyID); err != nil {
+		return err
+	}
+	if upvote {
+		return s.replyRepo.AddUpvote(ctx, replyID, userID)
+	}
+	return s.replyRepo.RemoveUpvote(ctx, replyID, userID)
+}
+
+// MarkAnswer marks a reply as the instructor-endorsed answer of its thread,
+// or clears the mark. Only moderators may mark answers.
+func (s *DiscussionService) MarkAnswer(ctx context.Context, courseID, threadID, replyID primitive.ObjectID, userID int, admin bool, answer bool) error {
+	moderator, err := s.access(ctx, courseID, userID, admin)
+	if err != nil {
+		return err
+	}
+	if !moderator {
+		return models.ErrForbidden
+	}
+	reply, err := s.findReply(ctx, courseID, threadID, replyID)
+	if err != nil {
+		return err
+	}
+
+	var answerID *primitive.ObjectID
+	if answer {
+		answerID = &reply.ID
+	} else if !reply.InstructorAnswer {
+		return nil
+	}
+
+	if err := s.replyRepo.SetInstructorAnswer(ctx, threadID, answerID); err != nil {
+		return err
+	}
+	return s.threadRepo.SetAnswer(ctx, threadID, answerID)
+}
+
+// access checks that the user may take part in the course's discussions and
+// reports whether they may moderate them, as admins and the course's
+// instructors can.
+func (s *DiscussionService) access(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool) (bool, error) {
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return false, err
+	}
+	if admin || course.IsTaughtBy(userID) {
+		return true, nil
+	}
+
+	enrolled, err := s.enrollmentRepo.CheckEnrollment(ctx, courseID, userID)
+	if err != nil {
+		return false, err
+	}
+	if !enrolled {
+		return false, models.ErrForbidden
+	}
+	return false, nil
+}
+
+func (s *DiscussionService) findThread(ctx context.Context, courseID, threadID primitive.ObjectID) (*models.Thread, error) {
+	thread, err := s.threadRepo.FindByID(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+	if thread.CourseID != courseID {
+		return nil, models.ErrThreadNotFound
+	}
+	return thread, nil
+}
+
+func (s *DiscussionService) findReply(ctx context.Context, courseID, threadID, replyID primitive.ObjectID) (*models.Reply, error) {
+	reply, err := s.replyRepo.FindByID(ctx, replyID)
+	if err != nil {
+		return nil, err
+	}
+	if reply.CourseID != courseID || reply.ThreadID != threadID {
+		return nil, models.ErrReplyNotFound
+	}
+	return reply, nil
+}
+
+func (s *DiscussionService) authorName(ctx context.Context, userID int) string {
+	user, err := s.users.GetUser(ctx, userID)
+	if err != nil {
+		log.Printf("Error looking up user %d for discussion post: %v", userID, err)
+		return ""
+	}
+	return user.Username
+}
+
+// checkWindow allows moderators to change any post, and authors to change
+// their own posts until the window closes.
+func checkWindow(authorID int, createdAt time.Time, userID int, moderator bool, window time.Duration, closed error) error {
+	if moderator {
+		return nil
+	}
+	if authorID != userID {
+		return models.ErrForbidden
+	}
+	if time.Since(createdAt) > window {
+		return closed
+	}
+	return nil
+}
+
+func parseCursor(cursor string) (*primitive.ObjectID, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	id, err := primitive.ObjectIDFromHex(cursor)
+	if err != nil {
+		return nil, models.ErrInvalidCursor
+	}
+	return &id, nil
+}
+
+func pageSize(limit int) int {
+	if limit < 1 {
+		return defaultDiscussionPageSize
+	}
+	if limit > maxDiscussionPageSize {
+		return maxDiscussionPageSize
+	}
+	return limit
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryThreadRepo struct {
+	models.ThreadRepository
+	threads map[primitive.ObjectID]*models.Thread
+}
+
+func (r *memoryThreadRepo) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Thread, error) {
+	thread, ok := r.threads[id]
+	if !ok {
+		return nil, models.ErrThreadNotFound
+	}
+	copied := *thread
+	return &copied, nil
+}
+
+func (r *memoryThreadRepo) SetPinned(ctx context.Context, id primitive.ObjectID, pinned bool) error {
+	r.threads[id].Pinned = pinned
+	return nil
+}
+
+func (r *memoryThreadRepo) Delete(ctx context.Context, id primitive.ObjectID) error {
+	delete(r.threads, id)
+	return nil
+}
+
+type memoryReplyRepo struct {
+	models.ReplyRepository
+}
+
+func (r memoryReplyRepo) DeleteByThreadID(ctx context.Context, threadID primitive.ObjectID) error {
+	return nil
+}
+
+func TestDiscussionModeration(t *testing.T) {
+	const instructor, student, author, outsider = 1, 2, 3, 4
+	course := models.Course{ID: primitive.NewObjectID(), InstructorIDs: []int{instructor}}
+	thread := &models.Thread{
+		ID:        primitive.NewObjectID(),
+		CourseID:  course.ID,
+		AuthorID:  author,
+		CreatedAt: time.Now().Add(-30 * 24 * time.Hour),
+	}
+	threads := &memoryThreadRepo{threads: map[primitive.ObjectID]*models.Thread{thread.ID: thread}}
+	service := NewDiscussionService(
+		threads,
+		memoryReplyRepo{},
+		&memoryCourseRepo{course: course},
+		&memoryEnrollmentRepo{users: map[int]bool{student: true, author: true}},
+		nil,
+	)
+	ctx := context.Background()
+
+	t.Run("students cannot pin", func(t *testing.T) {
+		assert.Equal(t, models.ErrForbidden, service.PinThread(ctx, course.ID, thread.ID, student, false, true))
+	})
+
+	t.Run("outsiders cannot take part", func(t *testing.T) {
+		assert.Equal(t, models.ErrForbidden, service.PinThread(ctx, course.ID, thread.ID, outsider, false, true))
+	})
+
+	t.Run("authors cannot delete after the window", func(t *testing.T) {
+		assert.Equal(t, models.ErrDeleteWindowClosed, service.DeleteThread(ctx, course.ID, thread.ID, author, false))
+	})
+
+	t.Run("instructors moderate their course", func(t *testing.T) {
+		assert.NoError(t, service.PinThread(ctx, course.ID, thread.ID, instructor, false, true))
+		assert.True(t, threads.threads[thread.ID].Pinned)
+		assert.NoError(t, service.DeleteThread(ctx, course.ID, thread.ID, instructor, false))
+		assert.NotContains(t, threads.threads, thread.ID)
+	})
+}
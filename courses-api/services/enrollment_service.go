@@ -3,29 +3,60 @@ package services
 import (
 	"context"
 	"courses-api/models"
+	"log"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type EnrollmentService struct {
-	enrollmentRepo models.EnrollmentRepository
-	courseRepo     models.CourseRepository
-	messageQueue   MessageQueue
+	enrollmentRepo   models.EnrollmentRepository
+	courseRepo       models.CourseRepository
+	waitlistRepo     models.WaitlistRepository
+	cancellationRepo models.EnrollmentCancellationRepository
+	licenseRepo      models.SeatLicenseRepository
+	tx               models.Transactor
+	prerequisites    PrerequisiteChecker
+	messageQueue     MessageQueue
+	onSeatsReleased  SeatReleaseHandler
 }
 
 func NewEnrollmentService(
 	enrollmentRepo models.EnrollmentRepository,
 	courseRepo models.CourseRepository,
+	waitlistRepo models.WaitlistRepository,
+	cancellationRepo models.EnrollmentCancellationRepository,
+	licenseRepo models.SeatLicenseRepository,
+	tx models.Transactor,
+	prerequisites PrerequisiteChecker,
 	messageQueue MessageQueue,
+	onSeatsReleased SeatReleaseHandler,
 ) *EnrollmentService {
 	return &EnrollmentService{
-		enrollmentRepo: enrollmentRepo,
-		courseRepo:     courseRepo,
-		messageQueue:   messageQueue,
+		enrollmentRepo:   enrollmentRepo,
+		courseRepo:       courseRepo,
+		waitlistRepo:     waitlistRepo,
+		cancellationRepo: cancellationRepo,
+		licenseRepo:      licenseRepo,
+		tx:               tx,
+		prerequisites:    prerequisites,
+		messageQueue:     messageQueue,
+		onSeatsReleased:  onSeatsReleased,
 	}
 }
 
 func (s *EnrollmentService) CreateEnrollment(ctx context.Context, enrollment *models.Enrollment) error {
+	return s.enroll(ctx, enrollment, true)
+}
+
+// EnrollUser enrolls a user on an admin's behalf. Admins may skip the
+// prerequisite check; every other rule still applies.
+func (s *EnrollmentService) EnrollUser(ctx context.Context, enrollment *models.Enrollment, overridePrerequisites bool) error {
+	return s.enroll(ctx, enrollment, !overridePrerequisites)
+}
+
+func (s *EnrollmentService) enroll(ctx context.Context, enrollment *models.Enrollment, checkPrerequisites bool) error {
 	// Check if already enrolled first
 	enrolled, err := s.enrollmentRepo.CheckEnrollment(ctx, enrollment.CourseID, enrollment.UserID)
 	if err != nil {
@@ -43,16 +74,101 @@ func (s *EnrollmentService) CreateEnrollment(ctx context.Context, enrollment *mo
 		return err
 	}
 
-	if course.AvailableSeats <= 0 {
+	// Draft and archived courses are not open for enrollment
+	if !course.IsPublished() {
+		return models.ErrCourseNotFound
+	}
+
+	if err := course.CheckEnrollmentWindow(time.Now()); err != nil {
+		return err
+	}
+
+	if checkPrerequisites && s.prerequisites != nil {
+		if err := s.prerequisites.CheckPrerequisites(ctx, course, enrollment.UserID); err != nil {
+			return err
+		}
+	}
+
+	if !course.IsFree() {
+		return models.ErrPaymentRequired
+	}
+
+	// Seats offered to waitlisted users are held for them until the offer
+	// expires, and seats held during checkout are not open either; everyone
+	// else may only take the remaining ones.
+	now := time.Now()
+	entry, err := s.waitlistRepo.FindActive(ctx, enrollment.CourseID, enrollment.UserID)
+	if err != nil && err != models.ErrWaitlistEntryNotFound {
+		return err
+	}
+	reserved := 0
+	if entry == nil || !entry.HasOpenOffer(now) {
+		if reserved, err = s.waitlistRepo.CountOpenOffers(ctx, enrollment.CourseID, now); err != nil {
+			return err
+		}
+	}
+	if course.OpenSeats() <= reserved {
 		return models.ErrNoAvailableSeats
 	}
+	enrollment.Date = now
 
-	if err := s.enrollmentRepo.Create(ctx, enrollment); err != nil {
+	// The conditional decrement and the unique (course_id, user_id) index are
+	// what actually prevent overbooking and duplicates across replicas; the
+	// checks above only avoid starting a transaction that is bound to fail.
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		updated, err := s.courseRepo.ReserveSeat(ctx, enrollment.CourseID, reserved)
+		if err != nil {
+			return err
+		}
+		if err := s.enrollmentRepo.Create(ctx, enrollment); err != nil {
+			return err
+		}
+		if entry != nil {
+			// The sweeper may have expired the entry meanwhile; the seat is still ours
+			if err := claimWaitlistEntry(ctx, s.waitlistRepo, s.courseRepo, entry); err != nil {
+				return err
+			}
+		}
+		course = updated
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
-	course.AvailableSeats--
-	return s.courseRepo.Update(ctx, course)
+	publishSeatCount(s.messageQueue, course)
+	return nil
+}
+
+// BulkEnroll enrolls each user on an admin's behalf under the same rules as
+// EnrollUser. Every enrollment takes its seat with its own conditional
+// decrement, so the course is never overbooked: once it is full, the
+// remaining users are still processed and each fails with
+// ErrNoAvailableSeats. Every user gets their own result.
+func (s *EnrollmentService) BulkEnroll(ctx context.Context, courseID primitive.ObjectID, userIDs []int, overridePrerequisites bool) (*models.BulkEnrollment, error) {
+	if len(userIDs) == 0 || len(userIDs) > models.MaxBulkEnrollUsers {
+		return nil, models.ErrInvalidBulkUsers
+	}
+	for _, userID := range userIDs {
+		if userID <= 0 {
+			return nil, models.ErrInvalidBulkUsers
+		}
+	}
+
+	result := &models.BulkEnrollment{Results: make([]models.BulkEnrollResult, 0, len(userIDs))}
+	for _, userID := range userIDs {
+		line := models.BulkEnrollResult{UserID: userID}
+		enrollment := models.Enrollment{CourseID: courseID, UserID: userID}
+		if err := s.EnrollUser(ctx, &enrollment, overridePrerequisites); err != nil {
+			line.Error = err.Error()
+			result.Failed++
+		} else {
+			line.EnrollmentID = &enrollment.ID
+			result.Enrolled++
+		}
+		result.Results = append(result.Results, line)
+	}
+	return result, nil
 }
 
 func (s *EnrollmentService) GetUserEnrollments(ctx context.Context, userID int) ([]models.Enrollment, error) {
@@ -61,4 +177,75 @@ func (s *EnrollmentService) GetUserEnrollments(ctx context.Context, userID int)
 
 func (s *EnrollmentService) CheckEnrollment(ctx context.Context, courseID primitive.ObjectID, userID int) (bool, error) {
 	return s.enrollmentRepo.CheckEnrollment(ctx, courseID, userID)
-} 
\ No newline at end of file
+}
+
+// Unenroll removes the user's enrollment, gives the seat back and records who
+// cancelled it and why. cancelledBy is the user themselves or an admin.
+func (s *EnrollmentService) Unenroll(ctx context.Context, courseID primitive.ObjectID, userID, cancelledBy int, reason string) (*models.EnrollmentCancellation, error) {
+	reason = strings.TrimSpace(reason)
+	if len(reason) > 500 {
+		return nil, models.ErrInvalidReason
+	}
+
+	enrollment, err := s.enrollmentRepo.FindByCourseAndUser(ctx, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cancellation := models.EnrollmentCancellation{
+		EnrollmentID: enrollment.ID,
+		CourseID:     courseID,
+		UserID:       userID,
+		EnrolledAt:   enrollment.Date,
+		Reason:       reason,
+		CancelledBy:  cancelledBy,
+		CancelledAt:  time.Now(),
+	}
+
+	var course *models.Course
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		// Only the request that actually deletes the enrollment restores the seat
+		if err := s.enrollmentRepo.Delete(ctx, enrollment.ID); err != nil {
+			return err
+		}
+		if enrollment.LicenseID != nil {
+			// A licensed seat goes back to the license, not to the course
+			if err := s.licenseRepo.ReleaseSeat(ctx, *enrollment.LicenseID); err != nil {
+				return err
+			}
+			return s.cancellationRepo.Create(ctx, &cancellation)
+		}
+		updated, err := s.courseRepo.IncrementSeats(ctx, courseID, 1)
+		if err != nil {
+			return err
+		}
+		course = updated
+		return s.cancellationRepo.Create(ctx, &cancellation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if course == nil {
+		return &cancellation, nil
+	}
+	publishSeatCount(s.messageQueue, course)
+
+	if s.onSeatsReleased != nil {
+		if err := s.onSeatsReleased.OnSeatsReleased(ctx, courseID); err != nil {
+			log.Printf("Error promoting waitlist for course %s: %v", courseID.Hex(), err)
+		}
+	}
+	return &cancellation, nil
+}
+
+// publishSeatCount keeps the seat count in the search index current. The
+// change is already committed, so a failed publish is only logged.
+func publishSeatCount(messageQueue MessageQueue, course *models.Course) {
+	if !course.IsPublished() {
+		return
+	}
+	if err := messageQueue.PublishCourseUpdate(course, "upsert"); err != nil {
+		log.Printf("Error publishing seat update for course %s: %v", course.ID.Hex(), err)
+	}
+}
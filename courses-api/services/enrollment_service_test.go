@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/repositories/mongodb"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	hammerSeats    = 10
+	hammerStudents = 100
+)
+
+// memoryTx applies rollbacks registered by the memory repositories when the
+// transaction function fails.
+type memoryTx struct{}
+
+type rollbackKey struct{}
+
+func (memoryTx) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	var undo []func()
+	err := fn(context.WithValue(ctx, rollbackKey{}, &undo))
+	if err != nil {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+	}
+	return err
+}
+
+func onRollback(ctx context.Context, fn func()) {
+	if undo, ok := ctx.Value(rollbackKey{}).(*[]func()); ok {
+		*undo = append(*undo, fn)
+	}
+}
+
+type memoryCourseRepo struct {
+	models.CourseRepository
+	mu     sync.Mutex
+	course models.Course
+}
+
+func (r *memoryCourseRepo) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Course, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	course := r.course
+	return &course, nil
+}
+
+func (r *memoryCourseRepo) ReserveSeat(ctx context.Context, id primitive.ObjectID, reserved int) (*models.Course, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.course.AvailableSeats <= reserved {
+		return nil, models.ErrNoAvailableSeats
+	}
+	r.course.AvailableSeats--
+	onRollback(ctx, func() {
+		r.mu.Lock()
+		r.course.AvailableSeats++
+		r.mu.Unlock()
+	})
+	course := r.course
+	return &course, nil
+}
+
+func (r *memoryCourseRepo) UpdateWithSeats(ctx context.Context, course *models.Course, seatDelta int) (*models.Course, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if seatDelta < 0 && r.course.AvailableSeats+seatDelta < r.course.HeldSeats {
+		return nil, models.ErrSeatsInUse
+	}
+	updated := *course
+	updated.AvailableSeats = r.course.AvailableSeats + seatDelta
+	updated.HeldSeats = r.course.HeldSeats
+	updated.OfferedSeats = r.course.OfferedSeats
+	updated.AverageRating = r.course.AverageRating
+	updated.ReviewCount = r.course.ReviewCount
+	r.course = updated
+	return &updated, nil
+}
+
+type memoryEnrollmentRepo struct {
+	models.EnrollmentRepository
+	mu    sync.Mutex
+	users map[int]bool
+}
+
+func (r *memoryEnrollmentRepo) CheckEnrollment(ctx context.Context, courseID primitive.ObjectID, userID int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.users[userID], nil
+}
+
+func (r *memoryEnrollmentRepo) Create(ctx context.Context, enrollment *models.Enrollment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.users[enrollment.UserID] {
+		return models.ErrAlreadyEnrolled
+	}
+	r.users[enrollment.UserID] = true
+	onRollback(ctx, func() {
+		r.mu.Lock()
+		delete(r.users, enrollment.UserID)
+		r.mu.Unlock()
+	})
+	return nil
+}
+
+type emptyWaitlistRepo struct {
+	models.WaitlistRepository
+}
+
+func (emptyWaitlistRepo) FindActive(ctx context.Context, courseID primitive.ObjectID, userID int) (*models.WaitlistEntry, error) {
+	return nil, models.ErrWaitlistEntryNotFound
+}
+
+func (emptyWaitlistRepo) CountOpenOffers(ctx context.Context, courseID primitive.ObjectID, now time.Time) (int, error) {
+	return 0, nil
+}
+
+type discardQueue struct{}
+
+func (discardQueue) PublishCourseUpdate(course *models.Course, action string) error { return nil }
+func (discardQueue) PublishCourseDelete(courseID interface{}) error                 { return nil }
+func (discardQueue) PublishNotification(notification *models.Notification) error    { return nil }
+
+// hammer enrolls the given users into the course concurrently and returns
+// the number of successes and the errors of the failed attempts.
+func hammer(service *EnrollmentService, courseID primitive.ObjectID, users []int) (int, []error) {
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		successes int
+		failures  []error
+	)
+	for _, userID := range users {
+		wg.Add(1)
+		go func(userID int) {
+			defer wg.Done()
+			err := service.CreateEnrollment(context.Background(), &models.Enrollment{
+				CourseID: courseID,
+				UserID:   userID,
+				Date:     time.Now(),
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures = append(failures, err)
+				return
+			}
+			successes++
+		}(userID)
+	}
+	wg.Wait()
+	return successes, failures
+}
+
+func distinctUsers(n int) []int {
+	users := make([]int, n)
+	for i := range users {
+		users[i] = i + 1
+	}
+	return users
+}
+
+func sameUser(n int) []int {
+	users := make([]int, n)
+	for i := range users {
+		users[i] = 42
+	}
+	return users
+}
+
+func newMemoryEnrollmentService(seats int) (*EnrollmentService, *memoryCourseRepo, *memoryEnrollmentRepo) {
+	courses := &memoryCourseRepo{course: models.Course{
+		ID:             primitive.NewObjectID(),
+		Status:         models.CourseStatusPublished,
+		AvailableSeats: seats,
+	}}
+	enrollments := &memoryEnrollmentRepo{users: make(map[int]bool)}
+	service := NewEnrollmentService(enrollments, courses, emptyWaitlistRepo{}, nil, nil, memoryTx{}, nil, discardQueue{}, nil)
+	return service, courses, enrollments
+}
+
+func TestCreateEnrollmentConcurrentNeverOverbooks(t *testing.T) {
+	service, courses, enrollments := newMemoryEnrollmentService(hammerSeats)
+
+	successes, failures := hammer(service, courses.course.ID, distinctUsers(hammerStudents))
+
+	assert.Equal(t, hammerSeats, successes)
+	assert.Len(t, enrollments.users, hammerSeats)
+	assert.Equal(t, 0, courses.course.AvailableSeats)
+	for _, err := range failures {
+		assert.Equal(t, models.ErrNoAvailableSeats, err)
+	}
+}
+
+func TestCreateEnrollmentConcurrentSameUser(t *testing.T) {
+	service, courses, enrollments := newMemoryEnrollmentService(hammerSeats)
+
+	successes, failures := hammer(service, courses.course.ID, sameUser(20))
+
+	assert.Equal(t, 1, successes)
+	assert.Len(t, enrollments.users, 1)
+	assert.Equal(t, hammerSeats-1, courses.course.AvailableSeats)
+	for _, err := range failures {
+		assert.Equal(t, models.ErrAlreadyEnrolled, err)
+	}
+}
+
+// TestCreateEnrollmentConcurrentMongo runs the same hammer against a real
+// MongoDB replica set, e.g. MONGO_TEST_URI=mongodb://localhost:27017/?replicaSet=rs0
+func TestCreateEnrollmentConcurrentMongo(t *testing.T) {
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI not set")
+	}
+
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	require.NoError(t, err)
+	defer client.Disconnect(ctx)
+
+	db := client.Database("courses_test_" + primitive.NewObjectID().Hex())
+	defer db.Drop(ctx)
+
+	courseRepo := mongodb.NewCourseRepository(db)
+	enrollmentRepo := mongodb.NewEnrollmentRepository(db)
+	require.NoError(t, enrollmentRepo.EnsureIndexes(ctx))
+
+	course := models.Course{
+		ID:             primitive.NewObjectID(),
+		Title:          "Concurrency",
+		Status:         models.CourseStatusPublished,
+		AvailableSeats: hammerSeats,
+	}
+	_, err = db.Collection("courses").InsertOne(ctx, &course)
+	require.NoError(t, err)
+
+	service := NewEnrollmentService(
+		enrollmentRepo,
+		courseRepo,
+		mongodb.NewWaitlistRepository(db),
+		mongodb.NewEnrollmentCancellationRepository(db),
+		mongodb.NewSeatLicenseRepository(db),
+		mongodb.NewTransactor(db),
+		nil,
+		discardQueue{},
+		nil,
+	)
+
+	users := append(distinctUsers(hammerStudents), sameUser(20)...)
+	successes, failures := hammer(service, course.ID, users)
+
+	stored, err := courseRepo.FindByID(ctx, course.ID)
+	require.NoError(t, err)
+	count, err := db.Collection("enrollments").CountDocuments(ctx, map[string]interface{}{"course_id": course.ID})
+	require.NoError(t, err)
+
+	assert.Equal(t, hammerSeats, successes)
+	assert.Equal(t, int64(hammerSeats), count)
+	assert.Equal(t, 0, stored.AvailableSeats)
+	for _, err := range failures {
+		assert.Contains(t, []error{models.ErrNoAvailableSeats, models.ErrAlreadyEnrolled}, err)
+	}
+}
+
+// resizeWhileEnrolling changes the course's capacity from an admin edit
+// while students hammer it, and returns the outcome of the edit.
+func resizeWhileEnrolling(t *testing.T, seats int) (*memoryCourseRepo, int, error) {
+	service, courses, _ := newMemoryEnrollmentService(hammerSeats)
+	courses.course.Title = "Go"
+	courses.course.Description = "Concurrency"
+	courses.course.Instructor = "Gopher"
+	courses.course.Duration = 10
+	courses.course.Category = models.DefaultCategories[0].Slug
+	courseService := NewCourseService(courses, nil, nil, discardQueue{}, nil)
+
+	edit, err := courseService.GetCourse(context.Background(), courses.course.ID)
+	require.NoError(t, err)
+	edit.AvailableSeats = seats
+
+	var (
+		wg        sync.WaitGroup
+		successes int
+		updateErr error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		successes, _ = hammer(service, courses.course.ID, distinctUsers(hammerStudents))
+	}()
+	go func() {
+		defer wg.Done()
+		updateErr = courseService.UpdateCourse(context.Background(), edit, 0, true)
+	}()
+	wg.Wait()
+	return courses, successes, updateErr
+}
+
+func TestUpdateCourseConcurrentWithEnrollments(t *testing.T) {
+	t.Run("added seats are not lost", func(t *testing.T) {
+		courses, successes, err := resizeWhileEnrolling(t, hammerSeats+5)
+
+		require.NoError(t, err)
+		assert.Equal(t, hammerSeats+5, successes+courses.course.AvailableSeats)
+		assert.GreaterOrEqual(t, courses.course.AvailableSeats, 0)
+	})
+
+	t.Run("taken seats are not handed out again", func(t *testing.T) {
+		courses, successes, err := resizeWhileEnrolling(t, hammerSeats-4)
+
+		capacity := hammerSeats - 4
+		if err != nil {
+			assert.Equal(t, models.ErrSeatsInUse, err)
+			capacity = hammerSeats
+		}
+		assert.Equal(t, capacity, successes+courses.course.AvailableSeats)
+		assert.GreaterOrEqual(t, courses.course.AvailableSeats, 0)
+	})
+}
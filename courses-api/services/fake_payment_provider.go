@@ -0,0 +1,128 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"courses-api/models"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Payment methods understood by the fake provider
+const (
+	FakeMethodSuccess        = "success"
+	FakeMethodDecline        = "decline"
+	FakeMethodDelayed        = "delayed"
+	FakeMethodDelayedDecline = "delayed_decline"
+)
+
+// FakePaymentProvider simulates a card processor for development. The payment
+// method picks the outcome: immediate success or decline, or a pending
+// payment that is confirmed or declined later through a signed webhook.
+type FakePaymentProvider struct {
+	secret     []byte
+	webhookURL string
+	delay      time.Duration
+	client     *http.Client
+}
+
+func NewFakePaymentProvider() *FakePaymentProvider {
+	secret := os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	if secret == "" {
+		secret = "dev-webhook-secret"
+	}
+	webhookURL := os.Getenv("PAYMENT_WEBHOOK_URL")
+	if webhookURL == "" {
+		webhookURL = "http://localhost:8002/payments/webhook"
+	}
+	delay, err := time.ParseDuration(os.Getenv("PAYMENT_WEBHOOK_DELAY"))
+	if err != nil {
+		delay = 5 * time.Second
+	}
+	return &FakePaymentProvider{
+		secret:     []byte(secret),
+		webhookURL: webhookURL,
+		delay:      delay,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *FakePaymentProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakePaymentProvider) CreatePayment(ctx context.Context, request PaymentRequest) (*PaymentResult, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	result := PaymentResult{Reference: "fake_" + hex.EncodeToString(buf)}
+
+	switch request.Method {
+	case FakeMethodSuccess, "":
+		result.Status = PaymentSucceeded
+	case FakeMethodDecline:
+		result.Status = PaymentDeclined
+		result.Reason = "card declined"
+	case FakeMethodDelayed:
+		result.Status = PaymentPending
+		p.sendWebhookLater(PaymentEvent{Reference: result.Reference, Status: PaymentSucceeded})
+	case FakeMethodDelayedDecline:
+		result.Status = PaymentPending
+		p.sendWebhookLater(PaymentEvent{Reference: result.Reference, Status: PaymentDeclined, Reason: "insufficient funds"})
+	default:
+		return nil, models.ErrInvalidPaymentMethod
+	}
+	return &result, nil
+}
+
+func (p *FakePaymentProvider) Refund(ctx context.Context, reference string) error {
+	log.Printf("Fake payment provider: refunded %s", reference)
+	return nil
+}
+
+func (p *FakePaymentProvider) ParseWebhook(payload []byte, signature string) (*PaymentEvent, error) {
+	if err := verifyWebhookSignature(p.secret, payload, signature, time.Now()); err != nil {
+		return nil, err
+	}
+	var event PaymentEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, models.ErrInvalidSignature
+	}
+	return &event, nil
+}
+
+// sendWebhookLater posts the signed event to the webhook URL after the
+// configured delay, the way a real provider confirms asynchronous payments.
+func (p *FakePaymentProvider) sendWebhookLater(event PaymentEvent) {
+	time.AfterFunc(p.delay, func() {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			log.Printf("Fake payment provider: encoding webhook: %v", err)
+			return
+		}
+
+		req, err := http.NewRequest(http.MethodPost, p.webhookURL, bytes.NewReader(payload))
+		if err != nil {
+			log.Printf("Fake payment provider: building webhook: %v", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Payment-Signature", signWebhook(p.secret, payload, time.Now()))
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			log.Printf("Fake payment provider: delivering webhook for %s: %v", event.Reference, err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			log.Printf("Fake payment provider: webhook for %s returned %d", event.Reference, resp.StatusCode)
+		}
+	})
+}
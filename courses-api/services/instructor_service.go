@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"time"
+)
+
+type InstructorService struct {
+	instructorRepo models.InstructorRepository
+	courseRepo     models.CourseRepository
+	users          models.UserDirectory
+}
+
+func NewInstructorService(
+	instructorRepo models.InstructorRepository,
+	courseRepo models.CourseRepository,
+	users models.UserDirectory,
+) *InstructorService {
+	return &InstructorService{
+		instructorRepo: instructorRepo,
+		courseRepo:     courseRepo,
+		users:          users,
+	}
+}
+
+// GetInstructor returns the public profile and published courses of an
+// instructor. The instructor themselves and admins also see unpublished ones.
+func (s *InstructorService) GetInstructor(ctx context.Context, userID, viewerID int, admin bool) (*models.InstructorPage, error) {
+	profile, err := s.instructorRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	courses, err := s.courseRepo.FindByInstructorID(ctx, userID, admin || viewerID == userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.InstructorPage{InstructorProfile: *profile, Courses: courses}, nil
+}
+
+// SaveProfile creates or updates an instructor profile. Only admins can make
+// a user an instructor; instructors may then edit their own profile.
+func (s *InstructorService) SaveProfile(ctx context.Context, profile *models.InstructorProfile, callerID int, admin bool) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+
+	existing, err := s.instructorRepo.FindByUserID(ctx, profile.UserID)
+	switch {
+	case err == models.ErrInstructorNotFound && admin:
+		// The account must exist in users-api before it can teach
+		if _, err := s.users.GetUser(ctx, profile.UserID); err != nil {
+			return err
+		}
+	case err == models.ErrInstructorNotFound:
+		return models.ErrForbidden
+	case err != nil:
+		return err
+	case !admin && profile.UserID != callerID:
+		return models.ErrForbidden
+	}
+
+	// Edits only move updated_at; created_at is set when the profile is inserted
+	now := time.Now()
+	profile.CreatedAt = now
+	if existing != nil {
+		profile.CreatedAt = existing.CreatedAt
+	}
+	profile.UpdatedAt = now
+	return s.instructorRepo.Save(ctx, profile)
+}
+
+// DeleteProfile stops a user from being an instructor. Their courses keep
+// the instructor ID so an admin can reassign them.
+func (s *InstructorService) DeleteProfile(ctx context.Context, userID int) error {
+	return s.instructorRepo.Delete(ctx, userID)
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveProfileKeepsCreatedAt(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	profiles := profileTable{7: {UserID: 7, DisplayName: "Gopher", CreatedAt: created, UpdatedAt: created}}
+	service := NewInstructorService(profiles, nil, nil)
+
+	profile := &models.InstructorProfile{UserID: 7, DisplayName: "Gopher", Bio: "Writes Go"}
+	require.NoError(t, service.SaveProfile(context.Background(), profile, 7, false))
+
+	assert.Equal(t, created, profiles[7].CreatedAt)
+	assert.True(t, profiles[7].UpdatedAt.After(created))
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const licenseRevokedReason = "License seat revoked"
+
+// Unenroller removes an enrollment and gives its seat back.
+type Unenroller interface {
+	Unenroll(ctx context.Context, courseID primitive.ObjectID, userID, cancelledBy int, reason string) (*models.EnrollmentCancellation, error)
+}
+
+// LicenseService manages seat licenses. Admins create them, and their
+// managers assign the seats to users, each assignment being an enrollment.
+type LicenseService struct {
+	licenseRepo    models.SeatLicenseRepository
+	courseRepo     models.CourseRepository
+	enrollmentRepo models.EnrollmentRepository
+	waitlistRepo   models.WaitlistRepository
+	tx             models.Transactor
+	prerequisites  PrerequisiteChecker
+	messageQueue   MessageQueue
+	unenroller     Unenroller
+}
+
+func NewLicenseService(
+	licenseRepo models.SeatLicenseRepository,
+	courseRepo models.CourseRepository,
+	enrollmentRepo models.EnrollmentRepository,
+	waitlistRepo models.WaitlistRepository,
+	tx models.Transactor,
+	prerequisites PrerequisiteChecker,
+	messageQueue MessageQueue,
+	unenroller Unenroller,
+) *LicenseService {
+	return &LicenseService{
+		licenseRepo:    licenseRepo,
+		courseRepo:     courseRepo,
+		enrollmentRepo: enrollmentRepo,
+		waitlistRepo:   waitlistRepo,
+		tx:             tx,
+		prerequisites:  prerequisites,
+		messageQueue:   messageQueue,
+		unenroller:     unenroller,
+	}
+}
+
+// CreateLicense takes the license's seats out of the course in one step,
+// so a license is only created if the whole pool fits.
+func (s *LicenseService) CreateLicense(ctx context.Context, license *models.SeatLicense, adminID int) error {
+	license.UsedSeats = 0
+	license.CreatedBy = adminID
+	license.CreatedAt = time.Now()
+	if err := license.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.courseRepo.FindByID(ctx, license.CourseID); err != nil {
+		return err
+	}
+
+	// Seats offered to waitlisted users stay theirs
+	reserved, err := s.waitlistRepo.CountOpenOffers(ctx, license.CourseID, license.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	var course *models.Course
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		updated, err := s.courseRepo.ReserveSeats(ctx, license.CourseID, license.Seats, reserved)
+		if err != nil {
+			return err
+		}
+		course = updated
+		return s.licenseRepo.Create(ctx, license)
+	})
+	if err != nil {
+		return err
+	}
+
+	publishSeatCount(s.messageQueue, course)
+	return nil
+}
+
+// ListLicenses returns every license to admins and the licenses a user
+// manages to everyone else.
+func (s *LicenseService) ListLicenses(ctx context.Context, userID int, admin bool) ([]models.SeatLicense, error) {
+	if admin {
+		return s.licenseRepo.FindAll(ctx)
+	}
+	return s.licenseRepo.FindByManager(ctx, userID)
+}
+
+// GetLicense returns the license with its current assignments.
+func (s *LicenseService) GetLicense(ctx context.Context, id primitive.ObjectID, userID int, admin bool) (*models.LicenseDetail, error) {
+	license, err := s.managedLicense(ctx, id, userID, admin)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.enrollmentRepo.FindByLicenseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &models.LicenseDetail{SeatLicense: *license, Assignments: assignments}, nil
+}
+
+// AssignSeat enrolls the user in the license's course on one of its seats.
+// The course must be open for enrollment and the user must meet its
+// prerequisites; payment is covered by the license.
+func (s *LicenseService) AssignSeat(ctx context.Context, id primitive.ObjectID, userID, managerID int, admin bool) (*models.Enrollment, error) {
+	license, err := s.managedLicense(ctx, id, managerID, admin)
+	if err != nil {
+		return nil, err
+	}
+	if license.FreeSeats() <= 0 {
+		return nil, models.ErrNoLicenseSeats
+	}
+
+	course, err := s.courseRepo.FindByID(ctx, license.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	if !course.IsPublished() {
+		return nil, models.ErrCourseNotFound
+	}
+	now := time.Now()
+	if err := course.CheckEnrollmentWindow(now); err != nil {
+		return nil, err
+	}
+	if s.prerequisites != nil {
+		if err := s.prerequisites.CheckPrerequisites(ctx, course, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	enrolled, err := s.enrollmentRepo.CheckEnrollment(ctx, course.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrolled {
+		return nil, models.ErrAlreadyEnrolled
+	}
+	entry, err := s.waitlistRepo.FindActive(ctx, course.ID, userID)
+	if err != nil && err != models.ErrWaitlistEntryNotFound {
+		return nil, err
+	}
+
+	licenseID := license.ID
+	enrollment := models.Enrollment{
+		CourseID:  course.ID,
+		UserID:    userID,
+		Date:      now,
+		LicenseID: &licenseID,
+	}
+	// The conditional claim and the unique enrollment index keep concurrent
+	// assignments within the pool and free of duplicates
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.licenseRepo.ClaimSeat(ctx, license.ID); err != nil {
+			return err
+		}
+		if err := s.enrollmentRepo.Create(ctx, &enrollment); err != nil {
+			return err
+		}
+		if entry != nil {
+			if err := claimWaitlistEntry(ctx, s.waitlistRepo, s.courseRepo, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+// RevokeSeat unenrolls a user assigned from the license and returns the
+// seat to its pool.
+func (s *LicenseService) RevokeSeat(ctx context.Context, id primitive.ObjectID, userID, managerID int, admin bool) (*models.EnrollmentCancellation, error) {
+	license, err := s.managedLicense(ctx, id, managerID, admin)
+	if err != nil {
+		return nil, err
+	}
+	enrollment, err := s.enrollmentRepo.FindByCourseAndUser(ctx, license.CourseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment.LicenseID == nil || *enrollment.LicenseID != license.ID {
+		return nil, models.ErrEnrollmentNotFound
+	}
+	return s.unenroller.Unenroll(ctx, license.CourseID, userID, managerID, licenseRevokedReason)
+}
+
+func (s *LicenseService) managedLicense(ctx context.Context, id primitive.ObjectID, userID int, admin bool) (*models.SeatLicense, error) {
+	license, err := s.licenseRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !admin && !license.IsManagedBy(userID) {
+		return nil, models.ErrForbidden
+	}
+	return license, nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryLicenseRepo struct {
+	models.SeatLicenseRepository
+	mu      sync.Mutex
+	license models.SeatLicense
+}
+
+func (r *memoryLicenseRepo) FindByID(ctx context.Context, id primitive.ObjectID) (*models.SeatLicense, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id != r.license.ID {
+		return nil, models.ErrLicenseNotFound
+	}
+	license := r.license
+	return &license, nil
+}
+
+func (r *memoryLicenseRepo) ClaimSeat(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.license.UsedSeats >= r.license.Seats {
+		return models.ErrNoLicenseSeats
+	}
+	r.license.UsedSeats++
+	onRollback(ctx, func() {
+		r.mu.Lock()
+		r.license.UsedSeats--
+		r.mu.Unlock()
+	})
+	return nil
+}
+
+func TestBulkEnrollReportsEachUser(t *testing.T) {
+	service, courses, enrollments := newMemoryEnrollmentService(2)
+
+	result, err := service.BulkEnroll(context.Background(), courses.course.ID, []int{1, 2, 2, 3}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Enrolled)
+	assert.Equal(t, 2, result.Failed)
+	assert.Len(t, enrollments.users, 2)
+	assert.Equal(t, 0, courses.course.AvailableSeats)
+	assert.Empty(t, result.Results[1].Error)
+	assert.Equal(t, models.ErrAlreadyEnrolled.Error(), result.Results[2].Error)
+	assert.Equal(t, models.ErrNoAvailableSeats.Error(), result.Results[3].Error)
+
+	_, err = service.BulkEnroll(context.Background(), courses.course.ID, []int{1, 0}, false)
+	assert.Equal(t, models.ErrInvalidBulkUsers, err)
+}
+
+func TestAssignSeatConcurrentStaysWithinPool(t *testing.T) {
+	_, courses, enrollments := newMemoryEnrollmentService(0)
+	licenses := &memoryLicenseRepo{license: models.SeatLicense{
+		ID:         primitive.NewObjectID(),
+		CourseID:   courses.course.ID,
+		ManagerIDs: []int{7},
+		Seats:      3,
+	}}
+	service := NewLicenseService(licenses, courses, enrollments, emptyWaitlistRepo{}, memoryTx{}, nil, discardQueue{}, nil)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		successes int
+	)
+	for userID := 1; userID <= 20; userID++ {
+		wg.Add(1)
+		go func(userID int) {
+			defer wg.Done()
+			enrollment, err := service.AssignSeat(context.Background(), licenses.license.ID, userID, 7, false)
+			if err != nil {
+				assert.Equal(t, models.ErrNoLicenseSeats, err)
+				return
+			}
+			assert.Equal(t, licenses.license.ID, *enrollment.LicenseID)
+			mu.Lock()
+			successes++
+			mu.Unlock()
+		}(userID)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 3, successes)
+	assert.Len(t, enrollments.users, 3)
+	assert.Equal(t, 3, licenses.license.UsedSeats)
+	// Licensed seats never come out of the course's open pool
+	assert.Equal(t, 0, courses.course.AvailableSeats)
+
+	_, err := service.AssignSeat(context.Background(), licenses.license.ID, 99, 8, false)
+	assert.Equal(t, models.ErrForbidden, err)
+}
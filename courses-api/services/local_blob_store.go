@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalBlobStore writes files under a directory that every replica shares
+// and serves them back over HTTP. Private stores have a signing key and
+// only serve files through URLs signed with it.
+type LocalBlobStore struct {
+	dir        string
+	baseURL    string
+	signingKey []byte
+	private    bool
+	now        func() time.Time
+}
+
+func NewLocalBlobStore(dir, baseURL string) *LocalBlobStore {
+	return &LocalBlobStore{dir: dir, baseURL: strings.TrimRight(baseURL, "/"), now: time.Now}
+}
+
+func NewPrivateLocalBlobStore(dir, baseURL string, signingKey []byte) *LocalBlobStore {
+	store := NewLocalBlobStore(dir, baseURL)
+	store.signingKey = signingKey
+	store.private = true
+	return store
+}
+
+func (s *LocalBlobStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (string, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return "", err
+	}
+
+	// Write to a temporary file first so readers never see a partial upload
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return "", err
+	}
+	return s.baseURL + "/" + key, nil
+}
+
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// SignedURL returns a link to the file that expires after ttl.
+func (s *LocalBlobStore) SignedURL(key, filename string, ttl time.Duration) (string, error) {
+	if len(s.signingKey) == 0 {
+		return "", errors.New("blob store has no signing key")
+	}
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(s.now().Add(ttl).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("filename", filename)
+	query.Set("signature", s.sign(key, expires, filename))
+	return s.baseURL + "/" + escapeKey(key) + "?" + query.Encode(), nil
+}
+
+func (s *LocalBlobStore) sign(key, expires, filename string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(key + "\n" + expires + "\n" + filename))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks a signed URL's signature and expiry.
+func (s *LocalBlobStore) verify(key string, query url.Values) bool {
+	if len(s.signingKey) == 0 {
+		return false
+	}
+	expires := query.Get("expires")
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || s.now().Unix() > unix {
+		return false
+	}
+	expected := s.sign(key, expires, query.Get("filename"))
+	return hmac.Equal([]byte(expected), []byte(query.Get("signature")))
+}
+
+// Handler serves stored files by key without listing directories; mount it
+// under the path of the base URL with the prefix stripped. Private stores
+// require a valid signed URL.
+func (s *LocalBlobStore) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		name, err := s.path(key)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		filename := ""
+		if s.private {
+			if !s.verify(key, r.URL.Query()) {
+				http.Error(w, models.ErrInvalidDownloadLink.Error(), http.StatusForbidden)
+				return
+			}
+			filename = r.URL.Query().Get("filename")
+		}
+		file, err := os.Open(name)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil || info.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+		if filename == "" {
+			filename = info.Name()
+		} else {
+			w.Header().Set("Content-Disposition", attachmentDisposition(filename))
+			w.Header().Set("Cache-Control", "private, no-store")
+		}
+		http.ServeContent(w, r, filename, info.ModTime(), file)
+	})
+}
+
+// path maps a key to a file, refusing keys that would escape the directory.
+func (s *LocalBlobStore) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if key == "" || clean != "/"+key {
+		return "", errors.New("invalid blob key")
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBlobStoreServesPublicFiles(t *testing.T) {
+	store := NewLocalBlobStore(t.TempDir(), "http://cdn.test/uploads/")
+
+	u, err := store.Put(context.Background(), "courses/1/a.png", strings.NewReader("png"), 3, "image/png")
+	require.NoError(t, err)
+	assert.Equal(t, "http://cdn.test/uploads/courses/1/a.png", u)
+
+	rec := httptest.NewRecorder()
+	store.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/courses/1/a.png", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "png", rec.Body.String())
+
+	// Directories are never listed
+	rec = httptest.NewRecorder()
+	store.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/courses/1/", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	_, err = store.Put(context.Background(), "../escape.png", strings.NewReader("png"), 3, "image/png")
+	assert.Error(t, err)
+
+	require.NoError(t, store.Delete(context.Background(), "courses/1/a.png"))
+	require.NoError(t, store.Delete(context.Background(), "courses/1/a.png"))
+}
+
+func TestLocalBlobStoreRequiresSignedLinks(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := NewPrivateLocalBlobStore(t.TempDir(), "http://api.test/files", []byte("secret"))
+	store.now = func() time.Time { return now }
+
+	_, err := store.Put(context.Background(), "courses/1/x.pdf", strings.NewReader("%PDF"), 4, "application/pdf")
+	require.NoError(t, err)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		store.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusForbidden, get("/courses/1/x.pdf").Code)
+
+	link, err := store.SignedURL("courses/1/x.pdf", "Week 1.pdf", time.Minute)
+	require.NoError(t, err)
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "api.test", parsed.Host)
+	target := strings.TrimPrefix(parsed.RequestURI(), "/files")
+
+	rec := get(target)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body, _ := io.ReadAll(rec.Body)
+	assert.Equal(t, "%PDF", string(body))
+	assert.Equal(t, `attachment; filename="Week 1.pdf"`, rec.Header().Get("Content-Disposition"))
+
+	// The signature covers the key and the download name
+	assert.Equal(t, http.StatusForbidden, get(strings.Replace(target, "x.pdf", "y.pdf", 1)).Code)
+	assert.Equal(t, http.StatusForbidden, get(strings.Replace(target, "Week", "Evil", 1)).Code)
+
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, http.StatusForbidden, get(target).Code)
+}
+
+func TestLocalBlobStoreWithoutKeyRefusesLinks(t *testing.T) {
+	store := NewPrivateLocalBlobStore(t.TempDir(), "http://api.test/files", nil)
+	_, err := store.SignedURL("courses/1/x.pdf", "x.pdf", time.Minute)
+	assert.Error(t, err)
+
+	rec := httptest.NewRecorder()
+	store.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/courses/1/x.pdf?expires=9999999999&signature=", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
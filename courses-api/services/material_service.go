@@ -0,0 +1,195 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"courses-api/models"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaterialService manages files attached to courses. Instructors and admins
+// upload and delete them; enrolled students download them through
+// short-lived signed links.
+type MaterialService struct {
+	materialRepo   models.MaterialRepository
+	courseRepo     models.CourseRepository
+	enrollmentRepo models.EnrollmentRepository
+	store          SignedBlobStore
+	quota          int64
+}
+
+func NewMaterialService(materialRepo models.MaterialRepository, courseRepo models.CourseRepository, enrollmentRepo models.EnrollmentRepository, store SignedBlobStore, quota int64) *MaterialService {
+	return &MaterialService{
+		materialRepo:   materialRepo,
+		courseRepo:     courseRepo,
+		enrollmentRepo: enrollmentRepo,
+		store:          store,
+		quota:          quota,
+	}
+}
+
+// ListMaterials returns the course's materials and storage usage to anyone
+// who may download them.
+func (s *MaterialService) ListMaterials(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool) (*models.MaterialList, error) {
+	if err := s.checkAccess(ctx, courseID, userID, admin, false); err != nil {
+		return nil, err
+	}
+
+	materials, err := s.materialRepo.FindByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	used, err := s.materialRepo.StorageUsed(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.MaterialList{Materials: materials, UsedBytes: used, QuotaBytes: s.quota}, nil
+}
+
+// UploadMaterial stores a file of the given size read from body. The bytes
+// are reserved against the course quota before the upload starts.
+func (s *MaterialService) UploadMaterial(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool, name string, size int64, body io.Reader) (*models.Material, error) {
+	if err := s.checkAccess(ctx, courseID, userID, admin, true); err != nil {
+		return nil, err
+	}
+
+	// Sniff the start of the file, then put it back in front of the rest
+	head := make([]byte, 512)
+	n, err := io.ReadFull(body, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	material := &models.Material{
+		CourseID:   courseID,
+		Name:       name,
+		Size:       size,
+		UploadedBy: userID,
+		CreatedAt:  time.Now(),
+	}
+	if err := material.Validate(http.DetectContentType(head)); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	material.Key = "courses/" + courseID.Hex() + "/" + hex.EncodeToString(buf) + strings.ToLower(filepath.Ext(material.Name))
+
+	if err := s.materialRepo.ReserveStorage(ctx, courseID, size, s.quota); err != nil {
+		return nil, err
+	}
+
+	reader := io.MultiReader(bytes.NewReader(head), body)
+	if _, err := s.store.Put(ctx, material.Key, reader, size, material.ContentType); err != nil {
+		s.releaseStorage(ctx, courseID, size)
+		return nil, err
+	}
+	if err := s.materialRepo.Create(ctx, material); err != nil {
+		s.deleteBlob(ctx, material.Key)
+		s.releaseStorage(ctx, courseID, size)
+		return nil, err
+	}
+	return material, nil
+}
+
+// DeleteMaterial removes the material and frees its storage.
+func (s *MaterialService) DeleteMaterial(ctx context.Context, courseID, materialID primitive.ObjectID, userID int, admin bool) error {
+	if err := s.checkAccess(ctx, courseID, userID, admin, true); err != nil {
+		return err
+	}
+	material, err := s.courseMaterial(ctx, courseID, materialID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.materialRepo.Delete(ctx, material.ID); err != nil {
+		return err
+	}
+	s.releaseStorage(ctx, courseID, material.Size)
+
+	// Clones share the file with the course they were copied from
+	if count, err := s.materialRepo.CountByKey(ctx, material.Key); err == nil && count == 0 {
+		s.deleteBlob(ctx, material.Key)
+	}
+	return nil
+}
+
+// DownloadLink issues a signed URL for the material that expires after
+// models.MaterialLinkTTL.
+func (s *MaterialService) DownloadLink(ctx context.Context, courseID, materialID primitive.ObjectID, userID int, admin bool) (*models.MaterialLink, error) {
+	if err := s.checkAccess(ctx, courseID, userID, admin, false); err != nil {
+		return nil, err
+	}
+	material, err := s.courseMaterial(ctx, courseID, materialID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(models.MaterialLinkTTL)
+	url, err := s.store.SignedURL(material.Key, material.Name, models.MaterialLinkTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &models.MaterialLink{URL: url, ExpiresAt: expiresAt}, nil
+}
+
+// checkAccess lets admins and the course's instructors manage materials,
+// and enrolled students read them.
+func (s *MaterialService) checkAccess(ctx context.Context, courseID primitive.ObjectID, userID int, admin, manage bool) error {
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return err
+	}
+	if admin || course.IsTaughtBy(userID) {
+		return nil
+	}
+	if manage {
+		return models.ErrForbidden
+	}
+
+	enrolled, err := s.enrollmentRepo.CheckEnrollment(ctx, courseID, userID)
+	if err != nil {
+		return err
+	}
+	if !enrolled {
+		return models.ErrForbidden
+	}
+	return nil
+}
+
+func (s *MaterialService) courseMaterial(ctx context.Context, courseID, materialID primitive.ObjectID) (*models.Material, error) {
+	material, err := s.materialRepo.FindByID(ctx, materialID)
+	if err != nil {
+		return nil, err
+	}
+	if material.CourseID != courseID {
+		return nil, models.ErrMaterialNotFound
+	}
+	return material, nil
+}
+
+// releaseStorage and deleteBlob undo partial work; failures only leave the
+// usage counter high or an orphaned file behind, so they are logged.
+func (s *MaterialService) releaseStorage(ctx context.Context, courseID primitive.ObjectID, size int64) {
+	if err := s.materialRepo.ReleaseStorage(ctx, courseID, size); err != nil {
+		log.Printf("Error releasing %d bytes of storage for course %s: %v", size, courseID.Hex(), err)
+	}
+}
+
+func (s *MaterialService) deleteBlob(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil {
+		log.Printf("Error deleting blob %s: %v", key, err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 type MessageQueue interface {
 	PublishCourseUpdate(course *models.Course, action string) error
 	PublishCourseDelete(courseID interface{}) error
+	PublishNotification(notification *models.Notification) error
 }
 
 type RabbitMQService struct {
@@ -117,4 +118,46 @@ func (r *RabbitMQService) PublishCourseDelete(courseID interface{}) error {
 			ContentType: "application/json",
 			Body:        body,
 		})
-} 
\ No newline at end of file
+}
+
+// PublishNotification queues a user-facing notification for delivery.
+func (r *RabbitMQService) PublishNotification(notification *models.Notification) error {
+	conn, err := amqp091.Dial(r.uri)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	q, err := ch.QueueDeclare(
+		"notifications",
+		true,
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	return ch.Publish(
+		"",
+		q.Name,
+		false,
+		false,
+		amqp091.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		})
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SeatHolder keeps a seat aside while an order's payment is pending.
+type SeatHolder interface {
+	CreateHold(ctx context.Context, courseID primitive.ObjectID, userID, minutes int) (*models.SeatHold, error)
+	ConfirmPaidHold(ctx context.Context, holdID primitive.ObjectID, userID int) (*models.Enrollment, error)
+	ReleaseHold(ctx context.Context, holdID primitive.ObjectID, userID int) error
+}
+
+type OrderService struct {
+	orderRepo  models.OrderRepository
+	courseRepo models.CourseRepository
+	couponRepo models.CouponRepository
+	tx         models.Transactor
+	holds      SeatHolder
+	provider   PaymentProvider
+}
+
+func NewOrderService(
+	orderRepo models.OrderRepository,
+	courseRepo models.CourseRepository,
+	couponRepo models.CouponRepository,
+	tx models.Transactor,
+	holds SeatHolder,
+	provider PaymentProvider,
+) *OrderService {
+	return &OrderService{
+		orderRepo:  orderRepo,
+		courseRepo: courseRepo,
+		couponRepo: couponRepo,
+		tx:         tx,
+		holds:      holds,
+		provider:   provider,
+	}
+}
+
+// Quote prices a course for the user, applying the coupon code if one is given.
+func (s *OrderService) Quote(ctx context.Context, courseID primitive.ObjectID, userID int, couponCode string) (*models.Quote, error) {
+	course, err := s.purchasableCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := models.Quote{
+		CourseID:  course.ID,
+		Currency:  course.Currency,
+		ListPrice: course.Price,
+		Total:     course.Price,
+	}
+	if couponCode != "" {
+		coupon, discount, err := applyCoupon(ctx, s.couponRepo, couponCode, course, userID)
+		if err != nil {
+			return nil, err
+		}
+		quote.CouponCode = coupon.Code
+		quote.Discount = discount
+		quote.Total -= discount
+	}
+	return &quote, nil
+}
+
+// Checkout holds a seat in a paid course and charges the user for it, less
+// any coupon discount. The returned order is paid, failed, or still pending
+// a webhook confirmation.
+func (s *OrderService) Checkout(ctx context.Context, courseID primitive.ObjectID, userID int, method, couponCode string) (*models.Order, error) {
+	course, err := s.purchasableCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var coupon *models.Coupon
+	var discount int64
+	if couponCode != "" {
+		coupon, discount, err = applyCoupon(ctx, s.couponRepo, couponCode, course, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.orderRepo.FindPending(ctx, courseID, userID); err == nil {
+		return nil, models.ErrCheckoutInProgress
+	} else if err != models.ErrOrderNotFound {
+		return nil, err
+	}
+
+	// Give slow payments the longest hold; the seat comes back if it expires
+	hold, err := s.holds.CreateHold(ctx, courseID, userID, models.MaxHoldMinutes)
+	if err != nil {
+		if err == models.ErrHoldExists {
+			return nil, models.ErrCheckoutInProgress
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	order := models.Order{
+		CourseID:  courseID,
+		UserID:    userID,
+		ListPrice: course.Price,
+		Discount:  discount,
+		Amount:    course.Price - discount,
+		Currency:  course.Currency,
+		Status:    models.OrderPending,
+		HoldID:    hold.ID,
+		Provider:  s.provider.Name(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if coupon != nil {
+		order.CouponID = &coupon.ID
+		order.CouponCode = coupon.Code
+	}
+
+	// The redemption only counts if the order that carries it is stored
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if coupon != nil {
+			if err := s.couponRepo.Redeem(ctx, coupon, userID, now); err != nil {
+				return err
+			}
+		}
+		return s.orderRepo.Create(ctx, &order)
+	})
+	if err != nil {
+		s.releaseHold(ctx, &order)
+		return nil, err
+	}
+
+	// Nothing to charge when the coupon covers the whole price
+	if order.Amount == 0 {
+		if err := s.complete(ctx, &order); err != nil {
+			return nil, err
+		}
+		return &order, nil
+	}
+
+	result, err := s.provider.CreatePayment(ctx, PaymentRequest{
+		OrderID:  order.ID,
+		Amount:   order.Amount,
+		Currency: order.Currency,
+		Method:   method,
+	})
+	if err != nil {
+		if err != models.ErrInvalidPaymentMethod {
+			log.Printf("Error creating payment for order %s: %v", order.ID.Hex(), err)
+			err = models.ErrPaymentFailed
+		}
+		s.fail(ctx, &order, err.Error())
+		return nil, err
+	}
+
+	order.ProviderRef = result.Reference
+	if err := s.orderRepo.SetProviderRef(ctx, order.ID, result.Reference); err != nil {
+		return nil, err
+	}
+
+	switch result.Status {
+	case PaymentSucceeded:
+		if err := s.complete(ctx, &order); err != nil {
+			return nil, err
+		}
+	case PaymentDeclined:
+		s.fail(ctx, &order, result.Reason)
+		return &order, models.ErrPaymentDeclined
+	}
+	return &order, nil
+}
+
+// HandleWebhook applies a signed payment notification. Replays and events
+// for orders that were already settled are ignored.
+func (s *OrderService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.provider.ParseWebhook(payload, signature)
+	if err != nil {
+		return err
+	}
+
+	order, err := s.orderRepo.FindByProviderRef(ctx, s.provider.Name(), event.Reference)
+	if err != nil {
+		return err
+	}
+
+	if order.Status != models.OrderPending {
+		// The user cancelled while the payment was still processing
+		if event.Status == PaymentSucceeded && (order.Status == models.OrderCancelled || order.Status == models.OrderFailed) {
+			return s.provider.Refund(ctx, order.ProviderRef)
+		}
+		return nil
+	}
+
+	switch event.Status {
+	case PaymentSucceeded:
+		return s.complete(ctx, order)
+	case PaymentDeclined:
+		s.fail(ctx, order, event.Reason)
+	}
+	return nil
+}
+
+func (s *OrderService) GetOrder(ctx context.Context, id primitive.ObjectID, userID int, admin bool) (*models.Order, error) {
+	order, err := s.orderRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !admin && order.UserID != userID {
+		return nil, models.ErrOrderNotFound
+	}
+	return order, nil
+}
+
+func (s *OrderService) GetUserOrders(ctx context.Context, userID int) ([]models.Order, error) {
+	return s.orderRepo.FindByUserID(ctx, userID)
+}
+
+// CancelOrder abandons a pending checkout and releases its seat. A payment
+// that completes afterwards is refunded when its webhook arrives.
+func (s *OrderService) CancelOrder(ctx context.Context, id primitive.ObjectID, userID int) (*models.Order, error) {
+	order, err := s.GetOrder(ctx, id, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	order.Status = models.OrderCancelled
+	if err := s.orderRepo.Transition(ctx, order, models.OrderPending); err != nil {
+		return nil, err
+	}
+	s.releaseHold(ctx, order)
+	s.releaseCoupon(ctx, order)
+	return order, nil
+}
+
+// complete marks the order paid and converts its hold into the enrollment.
+// If the seat can no longer be given, the payment is refunded.
+func (s *OrderService) complete(ctx context.Context, order *models.Order) error {
+	now := time.Now()
+	order.Status = models.OrderPaid
+	order.PaidAt = &now
+	if err := s.orderRepo.Transition(ctx, order, models.OrderPending); err != nil {
+		if err == models.ErrOrderNotPending {
+			return nil
+		}
+		return err
+	}
+
+	enrollment, err := s.holds.ConfirmPaidHold(ctx, order.HoldID, order.UserID)
+	if err != nil {
+		log.Printf("Error enrolling paid order %s, refunding: %v", order.ID.Hex(), err)
+		s.releaseHold(ctx, order)
+		if order.ProviderRef != "" {
+			if refundErr := s.provider.Refund(ctx, order.ProviderRef); refundErr != nil {
+				return refundErr
+			}
+		}
+		s.releaseCoupon(ctx, order)
+		order.Status = models.OrderRefunded
+		order.FailureReason = err.Error()
+		return s.orderRepo.Transition(ctx, order, models.OrderPaid)
+	}
+
+	order.EnrollmentID = &enrollment.ID
+	return s.orderRepo.Transition(ctx, order, models.OrderPaid)
+}
+
+func (s *OrderService) fail(ctx context.Context, order *models.Order, reason string) {
+	order.Status = models.OrderFailed
+	order.FailureReason = reason
+	if err := s.orderRepo.Transition(ctx, order, models.OrderPending); err != nil {
+		log.Printf("Error failing order %s: %v", order.ID.Hex(), err)
+		return
+	}
+	s.releaseHold(ctx, order)
+	s.releaseCoupon(ctx, order)
+}
+
+// releaseHold gives the seat back; the hold may already have expired.
+func (s *OrderService) releaseHold(ctx context.Context, order *models.Order) {
+	err := s.holds.ReleaseHold(ctx, order.HoldID, order.UserID)
+	if err != nil && err != models.ErrHoldNotFound && err != models.ErrHoldExpired {
+		log.Printf("Error releasing hold for order %s: %v", order.ID.Hex(), err)
+	}
+}
+
+// releaseCoupon returns the redemption of an order that did not go through.
+func (s *OrderService) releaseCoupon(ctx context.Context, order *models.Order) {
+	if order.CouponID == nil {
+		return
+	}
+	if err := s.couponRepo.Release(ctx, *order.CouponID, order.UserID); err != nil {
+		log.Printf("Error releasing coupon for order %s: %v", order.ID.Hex(), err)
+	}
+}
+
+func (s *OrderService) purchasableCourse(ctx context.Context, courseID primitive.ObjectID) (*models.Course, error) {
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if !course.IsPublished() {
+		return nil, models.ErrCourseNotFound
+	}
+	if course.IsFree() {
+		return nil, models.ErrCourseIsFree
+	}
+	return course, nil
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Payment outcomes reported by providers
+const (
+	PaymentSucceeded = "succeeded"
+	PaymentDeclined  = "declined"
+	PaymentPending   = "pending"
+)
+
+// webhookTolerance bounds the age of a signed webhook to limit replays.
+const webhookTolerance = 5 * time.Minute
+
+type PaymentRequest struct {
+	OrderID  primitive.ObjectID
+	Amount   int64
+	Currency string
+	Method   string
+}
+
+type PaymentResult struct {
+	Reference string
+	Status    string
+	Reason    string
+}
+
+// PaymentEvent is a verified asynchronous notification from a provider.
+type PaymentEvent struct {
+	Reference string `json:"reference"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// PaymentProvider charges customers. Payments may complete synchronously or
+// report PaymentPending and confirm later through a signed webhook.
+type PaymentProvider interface {
+	Name() string
+	CreatePayment(ctx context.Context, request PaymentRequest) (*PaymentResult, error)
+	Refund(ctx context.Context, reference string) error
+	ParseWebhook(payload []byte, signature string) (*PaymentEvent, error)
+}
+
+// signWebhook returns a "t=<unix>,v1=<hex hmac>" signature header where the
+// HMAC-SHA256 covers both the timestamp and the payload.
+func signWebhook(secret []byte, payload []byte, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, webhookMAC(secret, timestamp, payload))
+}
+
+// verifyWebhookSignature checks a header produced by signWebhook in constant
+// time and rejects signatures older than webhookTolerance.
+func verifyWebhookSignature(secret []byte, payload []byte, header string, now time.Time) error {
+	var timestamp, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+	if timestamp == "" || signature == "" {
+		return models.ErrInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return models.ErrInvalidSignature
+	}
+	age := now.Sub(time.Unix(unix, 0))
+	if age > webhookTolerance || age < -webhookTolerance {
+		return models.ErrInvalidSignature
+	}
+
+	expected := webhookMAC(secret, timestamp, payload)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return models.ErrInvalidSignature
+	}
+	return nil
+}
+
+func webhookMAC(secret []byte, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
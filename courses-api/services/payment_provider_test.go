@@ -0,0 +1,25 @@
+package services
+
+import (
+	"courses-api/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSignature(t *testing.T) {
+	secret := []byte("secret")
+	payload := []byte(`{"reference":"fake_1","status":"succeeded"}`)
+	now := time.Unix(1700000000, 0)
+	header := signWebhook(secret, payload, now)
+
+	assert.NoError(t, verifyWebhookSignature(secret, payload, header, now.Add(time.Minute)))
+
+	tampered := []byte(`{"reference":"fake_2","status":"succeeded"}`)
+	assert.Equal(t, models.ErrInvalidSignature, verifyWebhookSignature(secret, tampered, header, now))
+	assert.Equal(t, models.ErrInvalidSignature, verifyWebhookSignature([]byte("other"), payload, header, now))
+	assert.Equal(t, models.ErrInvalidSignature, verifyWebhookSignature(secret, payload, header, now.Add(time.Hour)))
+	assert.Equal(t, models.ErrInvalidSignature, verifyWebhookSignature(secret, payload, "", now))
+	assert.Equal(t, models.ErrInvalidSignature, verifyWebhookSignature(secret, payload, "t=abc,v1=00", now))
+}
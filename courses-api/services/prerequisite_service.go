@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PrerequisiteChecker decides whether a user has satisfied a course's prerequisites.
+type PrerequisiteChecker interface {
+	CheckPrerequisites(ctx context.Context, course *models.Course, userID int) error
+}
+
+type PrerequisiteService struct {
+	courseRepo     models.CourseRepository
+	enrollmentRepo models.EnrollmentRepository
+	lessonRepo     models.LessonRepository
+}
+
+func NewPrerequisiteService(
+	courseRepo models.CourseRepository,
+	enrollmentRepo models.EnrollmentRepository,
+	lessonRepo models.LessonRepository,
+) *PrerequisiteService {
+	return &PrerequisiteService{
+		courseRepo:     courseRepo,
+		enrollmentRepo: enrollmentRepo,
+		lessonRepo:     lessonRepo,
+	}
+}
+
+// CheckPrerequisites returns a *models.PrerequisitesError listing every
+// prerequisite the user still lacks. Courses with lessons must be completed;
+// completion isn't tracked for courses without any, so enrolling is enough.
+// Prerequisites that have since been deleted are ignored.
+func (s *PrerequisiteService) CheckPrerequisites(ctx context.Context, course *models.Course, userID int) error {
+	if len(course.Prerequisites) == 0 {
+		return nil
+	}
+
+	prerequisites, err := s.courseRepo.FindByIDs(ctx, course.Prerequisites)
+	if err != nil {
+		return err
+	}
+
+	var missing []models.MissingPrerequisite
+	for _, prerequisite := range prerequisites {
+		requirement := models.RequireEnrollment
+		lessons, err := s.lessonRepo.FindByCourseID(ctx, prerequisite.ID)
+		if err != nil {
+			return err
+		}
+		if len(lessons) > 0 {
+			requirement = models.RequireCompletion
+		}
+
+		enrollment, err := s.enrollmentRepo.FindByCourseAndUser(ctx, prerequisite.ID, userID)
+		if err != nil && err != models.ErrEnrollmentNotFound {
+			return err
+		}
+		if enrollment != nil && (requirement == models.RequireEnrollment || enrollment.CompletedAt != nil) {
+			continue
+		}
+
+		missing = append(missing, models.MissingPrerequisite{
+			CourseID:    prerequisite.ID,
+			Title:       prerequisite.Title,
+			Requirement: requirement,
+		})
+	}
+
+	if len(missing) > 0 {
+		return &models.PrerequisitesError{Missing: missing}
+	}
+	return nil
+}
+
+// checkPrerequisiteGraph verifies that the course's prerequisites exist and
+// that following them never leads back to the course itself.
+func checkPrerequisiteGraph(ctx context.Context, repo models.CourseRepository, course *models.Course) error {
+	if len(course.Prerequisites) == 0 {
+		return nil
+	}
+
+	visited := map[primitive.ObjectID]bool{}
+	frontier := course.Prerequisites
+	for first := true; len(frontier) > 0; first = false {
+		courses, err := repo.FindByIDs(ctx, frontier)
+		if err != nil {
+			return err
+		}
+		if first && len(courses) != len(frontier) {
+			return models.ErrInvalidPrerequisites
+		}
+
+		var next []primitive.ObjectID
+		for _, c := range courses {
+			visited[c.ID] = true
+			for _, id := range c.Prerequisites {
+				if id == course.ID {
+					return models.ErrPrerequisiteCycle
+				}
+				if !visited[id] {
+					visited[id] = true
+					next = append(next, id)
+				}
+			}
+		}
+		frontier = next
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type catalogRepo struct {
+	models.CourseRepository
+	courses map[primitive.ObjectID]models.Course
+}
+
+func (r catalogRepo) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Course, error) {
+	var courses []models.Course
+	for _, id := range ids {
+		if course, ok := r.courses[id]; ok {
+			courses = append(courses, course)
+		}
+	}
+	return courses, nil
+}
+
+type lessonCountRepo struct {
+	models.LessonRepository
+	counts map[primitive.ObjectID]int
+}
+
+func (r lessonCountRepo) FindByCourseID(ctx context.Context, courseID primitive.ObjectID) ([]models.Lesson, error) {
+	return make([]models.Lesson, r.counts[courseID]), nil
+}
+
+type userEnrollmentRepo struct {
+	models.EnrollmentRepository
+	enrollments map[primitive.ObjectID]models.Enrollment
+}
+
+func (r userEnrollmentRepo) FindByCourseAndUser(ctx context.Context, courseID primitive.ObjectID, userID int) (*models.Enrollment, error) {
+	enrollment, ok := r.enrollments[courseID]
+	if !ok {
+		return nil, models.ErrEnrollmentNotFound
+	}
+	return &enrollment, nil
+}
+
+func catalog(courses ...models.Course) catalogRepo {
+	repo := catalogRepo{courses: map[primitive.ObjectID]models.Course{}}
+	for _, course := range courses {
+		repo.courses[course.ID] = course
+	}
+	return repo
+}
+
+func TestCheckPrerequisiteGraphDetectsCycles(t *testing.T) {
+	basics := models.Course{ID: primitive.NewObjectID(), Title: "Basics"}
+	intermediate := models.Course{ID: primitive.NewObjectID(), Title: "Intermediate", Prerequisites: []primitive.ObjectID{basics.ID}}
+	advanced := models.Course{ID: primitive.NewObjectID(), Title: "Advanced", Prerequisites: []primitive.ObjectID{intermediate.ID}}
+	repo := catalog(basics, intermediate, advanced)
+
+	assert.NoError(t, checkPrerequisiteGraph(context.Background(), repo, &advanced))
+
+	basics.Prerequisites = []primitive.ObjectID{advanced.ID}
+	assert.Equal(t, models.ErrPrerequisiteCycle, checkPrerequisiteGraph(context.Background(), repo, &basics))
+
+	unknown := models.Course{Prerequisites: []primitive.ObjectID{primitive.NewObjectID()}}
+	assert.Equal(t, models.ErrInvalidPrerequisites, checkPrerequisiteGraph(context.Background(), repo, &unknown))
+}
+
+func TestCheckPrerequisitesListsMissingCourses(t *testing.T) {
+	tracked := models.Course{ID: primitive.NewObjectID(), Title: "Tracked"}
+	untracked := models.Course{ID: primitive.NewObjectID(), Title: "Untracked"}
+	advanced := models.Course{ID: primitive.NewObjectID(), Prerequisites: []primitive.ObjectID{tracked.ID, untracked.ID}}
+
+	enrollments := userEnrollmentRepo{enrollments: map[primitive.ObjectID]models.Enrollment{
+		tracked.ID:   {CourseID: tracked.ID},
+		untracked.ID: {CourseID: untracked.ID},
+	}}
+	service := NewPrerequisiteService(
+		catalog(tracked, untracked),
+		enrollments,
+		lessonCountRepo{counts: map[primitive.ObjectID]int{tracked.ID: 3}},
+	)
+
+	// Enrollment satisfies the untracked course but not the one with lessons
+	err := service.CheckPrerequisites(context.Background(), &advanced, 1)
+	var missing *models.PrerequisitesError
+	require.True(t, errors.As(err, &missing))
+	assert.True(t, errors.Is(err, models.ErrPrerequisitesNotMet))
+	assert.Equal(t, []models.MissingPrerequisite{
+		{CourseID: tracked.ID, Title: "Tracked", Requirement: models.RequireCompletion},
+	}, missing.Missing)
+
+	completed := time.Now()
+	enrollments.enrollments[tracked.ID] = models.Enrollment{CourseID: tracked.ID, CompletedAt: &completed}
+	assert.NoError(t, service.CheckPrerequisites(context.Background(), &advanced, 1))
+}
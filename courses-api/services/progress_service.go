@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"log"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CompletionHandler is notified the first time an enrollment completes its course
+type CompletionHandler interface {
+	OnCourseCompleted(ctx context.Context, courseID primitive.ObjectID, userID int) error
+}
+
+type ProgressService struct {
+	progressRepo   models.LessonProgressRepository
+	enrollmentRepo models.EnrollmentRepository
+	sectionRepo    models.SectionRepository
+	lessonRepo     models.LessonRepository
+	onComplete     CompletionHandler
+}
+
+func NewProgressService(
+	progressRepo models.LessonProgressRepository,
+	enrollmentRepo models.EnrollmentRepository,
+	sectionRepo models.SectionRepository,
+	lessonRepo models.LessonRepository,
+	onComplete CompletionHandler,
+) *ProgressService {
+	return &ProgressService{
+		progressRepo:   progressRepo,
+		enrollmentRepo: enrollmentRepo,
+		sectionRepo:    sectionRepo,
+		lessonRepo:     lessonRepo,
+		onComplete:     onComplete,
+	}
+}
+
+// MarkLesson records that the user started or completed a lesson and returns
+// the updated course progress. A completed lesson is never moved back to started.
+func (s *ProgressService) MarkLesson(ctx context.Context, courseID, lessonID primitive.ObjectID, userID int, status string) (*models.CourseProgress, error) {
+	if status != models.LessonStarted && status != models.LessonCompleted {
+		return nil, models.ErrInvalidProgress
+	}
+
+	enrollment, err := s.enrollmentRepo.FindByCourseAndUser(ctx, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	lesson, err := s.lessonRepo.FindByID(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson.CourseID != courseID {
+		return nil, models.ErrLessonNotFound
+	}
+
+	// Upsert leaves completed lessons as they are
+	now := time.Now()
+	progress := &models.LessonProgress{
+		EnrollmentID: enrollment.ID,
+		CourseID:     courseID,
+		LessonID:     lessonID,
+		UserID:       userID,
+		Status:       status,
+		StartedAt:    now,
+	}
+	if status == models.LessonCompleted {
+		progress.CompletedAt = &now
+	}
+	if err := s.progressRepo.Upsert(ctx, progress); err != nil {
+		return nil, err
+	}
+
+	enrollment.LastLessonID = &lessonID
+	summary, err := s.computeProgress(ctx, enrollment)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stamp the completion time the first time all required lessons are done
+	var completedAt *time.Time
+	if summary.Completed && enrollment.CompletedAt == nil {
+		completedAt = &now
+		summary.CompletedAt = completedAt
+	}
+	if err := s.enrollmentRepo.UpdateProgress(ctx, enrollment.ID, lessonID, completedAt); err != nil {
+		return nil, err
+	}
+
+	// The certificate can still be requested later if issuing it fails now
+	if completedAt != nil && s.onComplete != nil {
+		if err := s.onComplete.OnCourseCompleted(ctx, courseID, userID); err != nil {
+			log.Printf("Error handling completion of course %s by user %d: %v", courseID.Hex(), userID, err)
+		}
+	}
+	return summary, nil
+}
+
+// GetProgress returns the user's progress through a course.
+func (s *ProgressService) GetProgress(ctx context.Context, courseID primitive.ObjectID, userID int) (*models.CourseProgress, error) {
+	enrollment, err := s.enrollmentRepo.FindByCourseAndUser(ctx, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.computeProgress(ctx, enrollment)
+}
+
+// GetUserProgress returns the user's progress in every enrolled course, keyed
+// by course ID.
+func (s *ProgressService) GetUserProgress(ctx context.Context, userID int) (map[string]models.CourseProgress, error) {
+	enrollments, err := s.enrollmentRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]models.CourseProgress, len(enrollments))
+	for i := range enrollments {
+		progress, err := s.computeProgress(ctx, &enrollments[i])
+		if err != nil {
+			return nil, err
+		}
+		result[enrollments[i].CourseID.Hex()] = *progress
+	}
+	return result, nil
+}
+
+func (s *ProgressService) computeProgress(ctx context.Context, enrollment *models.Enrollment) (*models.CourseProgress, error) {
+	lessons, err := s.syllabusLessons(ctx, enrollment.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := s.progressRepo.FindByEnrollmentID(ctx, enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := models.ComputeProgress(enrollment, lessons, progress)
+	return &summary, nil
+}
+
+// syllabusLessons returns the course's lessons ordered by section, then by
+// position within the section.
+func (s *ProgressService) syllabusLessons(ctx context.Context, courseID primitive.ObjectID) ([]models.Lesson, error) {
+	sections, err := s.sectionRepo.FindByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	lessons, err := s.lessonRepo.FindByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	sectionPosition := make(map[primitive.ObjectID]int, len(sections))
+	for _, section := range sections {
+		sectionPosition[section.ID] = section.Position
+	}
+
+	sort.SliceStable(lessons, func(i, j int) bool {
+		si, sj := sectionPosition[lessons[i].SectionID], sectionPosition[lessons[j].SectionID]
+		if si != sj {
+			return si < sj
+		}
+		return lessons[i].Position < lessons[j].Position
+	})
+	return lessons, nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"courses-api/repositories/mongodb"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TestLessonProgressCompletionSticksMongo checks that marking a completed
+// lesson as started keeps it completed, e.g.
+// MONGO_TEST_URI=mongodb://localhost:27017
+func TestLessonProgressCompletionSticksMongo(t *testing.T) {
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI not set")
+	}
+
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	require.NoError(t, err)
+	defer client.Disconnect(ctx)
+
+	db := client.Database("courses_test_" + primitive.NewObjectID().Hex())
+	defer db.Drop(ctx)
+
+	repo := mongodb.NewLessonProgressRepository(db)
+	require.NoError(t, repo.EnsureIndexes(ctx))
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	progress := func(status string) *models.LessonProgress {
+		p := &models.LessonProgress{
+			EnrollmentID: primitive.NewObjectIDFromTimestamp(time.Unix(0, 0)),
+			LessonID:     primitive.NewObjectIDFromTimestamp(time.Unix(1, 0)),
+			Status:       status,
+			StartedAt:    now,
+		}
+		if status == models.LessonCompleted {
+			p.CompletedAt = &now
+		}
+		return p
+	}
+
+	require.NoError(t, repo.Upsert(ctx, progress(models.LessonCompleted)))
+	started := progress(models.LessonStarted)
+	require.NoError(t, repo.Upsert(ctx, started))
+	assert.Equal(t, models.LessonCompleted, started.Status)
+
+	stored, err := repo.FindByEnrollmentID(ctx, started.EnrollmentID)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, models.LessonCompleted, stored[0].Status)
+}
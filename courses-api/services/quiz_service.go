@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// submissionGrace absorbs network latency on submissions made right at the time limit
+const submissionGrace = 30 * time.Second
+
+type QuizService struct {
+	quizRepo       models.QuizRepository
+	attemptRepo    models.QuizAttemptRepository
+	enrollmentRepo models.EnrollmentRepository
+	courseRepo     models.CourseRepository
+}
+
+func NewQuizService(
+	quizRepo models.QuizRepository,
+	attemptRepo models.QuizAttemptRepository,
+	enrollmentRepo models.EnrollmentRepository,
+	courseRepo models.CourseRepository,
+) *QuizService {
+	return &QuizService{
+		quizRepo:       quizRepo,
+		attemptRepo:    attemptRepo,
+		enrollmentRepo: enrollmentRepo,
+		courseRepo:     courseRepo,
+	}
+}
+
+func (s *QuizService) CreateQuiz(ctx context.Context, quiz *models.Quiz) error {
+	if err := quiz.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.courseRepo.FindByID(ctx, quiz.CourseID); err != nil {
+		return err
+	}
+	return s.quizRepo.Create(ctx, quiz)
+}
+
+func (s *QuizService) UpdateQuiz(ctx context.Context, quiz *models.Quiz) error {
+	if _, err := s.findQuiz(ctx, quiz.CourseID, quiz.ID); err != nil {
+		return err
+	}
+	if err := quiz.Validate(); err != nil {
+		return err
+	}
+	return s.quizRepo.Update(ctx, quiz)
+}
+
+func (s *QuizService) DeleteQuiz(ctx context.Context, courseID, quizID primitive.ObjectID) error {
+	if _, err := s.findQuiz(ctx, courseID, quizID); err != nil {
+		return err
+	}
+	return s.quizRepo.Delete(ctx, quizID)
+}
+
+// ListQuizzes returns the course's quizzes. Answer keys are only included for admins.
+func (s *QuizService) ListQuizzes(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool) ([]models.Quiz, error) {
+	if !admin {
+		if _, err := s.enrollmentRepo.FindByCourseAndUser(ctx, courseID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	quizzes, err := s.quizRepo.FindByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Quiz, 0, len(quizzes))
+	for i := range quizzes {
+		if admin {
+			result = append(result, quizzes[i])
+		} else {
+			result = append(result, quizzes[i].ForStudent())
+		}
+	}
+	return result, nil
+}
+
+// GetQuiz returns a single quiz. Answer keys are only included for admins.
+func (s *QuizService) GetQuiz(ctx context.Context, courseID, quizID primitive.ObjectID, userID int, admin bool) (*models.Quiz, error) {
+	if !admin {
+		if _, err := s.enrollmentRepo.FindByCourseAndUser(ctx, courseID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	quiz, err := s.findQuiz(ctx, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+	if !admin {
+		student := quiz.ForStudent()
+		return &student, nil
+	}
+	return quiz, nil
+}
+
+// StartAttempt opens a new attempt, or returns the attempt still in progress.
+func (s *QuizService) StartAttempt(ctx context.Context, courseID, quizID primitive.ObjectID, userID int) (*models.QuizAttempt, error) {
+	enrollment, err := s.enrollmentRepo.FindByCourseAndUser(ctx, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	quiz, err := s.findQuiz(ctx, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.attemptRepo.FindByQuizAndUser(ctx, quizID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range attempts {
+		attempt := &attempts[i]
+		if attempt.Status != models.AttemptInProgress {
+			continue
+		}
+		if !s.pastDeadline(attempt, now) {
+			return attempt, nil
+		}
+		if err := s.expire(ctx, attempt, now); err != nil && err != models.ErrAttemptClosed {
+			return nil, err
+		}
+	}
+
+	if quiz.MaxAttempts > 0 && len(attempts) >= quiz.MaxAttempts {
+		return nil, models.ErrAttemptLimitReached
+	}
+
+	// Attempt numbers are unique per user and quiz, so of two concurrent
+	// starts only one can claim the next number
+	attempt := &models.QuizAttempt{
+		QuizID:       quizID,
+		CourseID:     courseID,
+		EnrollmentID: enrollment.ID,
+		UserID:       userID,
+		Number:       len(attempts) + 1,
+		Status:       models.AttemptInProgress,
+		StartedAt:    now,
+	}
+	if quiz.TimeLimit > 0 {
+		deadline := now.Add(time.Duration(quiz.TimeLimit) * time.Minute)
+		attempt.Deadline = &deadline
+	}
+	if err := s.attemptRepo.Create(ctx, attempt); err != nil {
+		if err == models.ErrAttemptLimitReached {
+			// The number was taken by a concurrent start; resume that attempt
+			if current := s.inProgress(ctx, quizID, userID, now); current != nil {
+				return current, nil
+			}
+		}
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// inProgress returns the user's open attempt at the quiz, if there is one.
+func (s *QuizService) inProgress(ctx context.Context, quizID primitive.ObjectID, userID int, now time.Time) *models.QuizAttempt {
+	attempts, err := s.attemptRepo.FindByQuizAndUser(ctx, quizID, userID)
+	if err != nil {
+		return nil
+	}
+	for i := range attempts {
+		if attempts[i].Status == models.AttemptInProgress && !s.pastDeadline(&attempts[i], now) {
+			return &attempts[i]
+		}
+	}
+	return nil
+}
+
+// SubmitAttempt grades the answers server-side and closes the attempt.
+func (s *QuizService) SubmitAttempt(ctx context.Context, courseID, quizID, attemptID primitive.ObjectID, userID int, answers []models.Answer) (*models.QuizAttempt, error) {
+	quiz, err := s.findQuiz(ctx, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt, err := s.attemptRepo.FindByID(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.QuizID != quizID || attempt.UserID != userID {
+		return nil, models.ErrAttemptNotFound
+	}
+	if attempt.Status != models.AttemptInProgress {
+		return nil, models.ErrAttemptClosed
+	}
+
+	now := time.Now()
+	if s.pastDeadline(attempt, now) {
+		if err := s.expire(ctx, attempt, now); err != nil {
+			return nil, err
+		}
+		return nil, models.ErrAttemptExpired
+	}
+
+	answers = quiz.DedupeAnswers(answers)
+	results, score, maxScore := quiz.Grade(answers)
+	attempt.Status = models.AttemptSubmitted
+	attempt.SubmittedAt = &now
+	attempt.Answers = answers
+	attempt.Results = results
+	attempt.Score = score
+	attempt.MaxScore = maxScore
+	if maxScore > 0 {
+		attempt.Percent = score * 100 / maxScore
+	}
+	attempt.Passed = attempt.Percent >= quiz.PassingScore
+
+	if err := s.attemptRepo.Complete(ctx, attempt); err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// GetAttempts returns the user's attempts at a quiz, oldest first.
+func (s *QuizService) GetAttempts(ctx context.Context, courseID, quizID primitive.ObjectID, userID int) ([]models.QuizAttempt, error) {
+	if _, err := s.findQuiz(ctx, courseID, quizID); err != nil {
+		return nil, err
+	}
+	attempts, err := s.attemptRepo.FindByQuizAndUser(ctx, quizID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if attempts == nil {
+		attempts = []models.QuizAttempt{}
+	}
+	return attempts, nil
+}
+
+// GetStats reports how submitted attempts fared on each question.
+func (s *QuizService) GetStats(ctx context.Context, courseID, quizID primitive.ObjectID) (*models.QuizStats, error) {
+	quiz, err := s.findQuiz(ctx, courseID, quizID)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := s.attemptRepo.FindSubmittedByQuiz(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.QuizStats{
+		QuizID:    quizID,
+		Attempts:  len(attempts),
+		Questions: make([]models.QuestionStats, len(quiz.Questions)),
+	}
+	index := make(map[primitive.ObjectID]int, len(quiz.Questions))
+	for i, question := range quiz.Questions {
+		index[question.ID] = i
+		stats.Questions[i] = models.QuestionStats{
+			QuestionID: question.ID,
+			Prompt:     question.Prompt,
+		}
+		if question.Type != models.QuestionShortAnswer {
+			stats.Questions[i].OptionCounts = make(map[string]int, len(question.Options))
+			for _, option := range question.Options {
+				stats.Questions[i].OptionCounts[option.ID] = 0
+			}
+		}
+	}
+
+	totalPercent, passed := 0, 0
+	for _, attempt := range attempts {
+		totalPercent += attempt.Percent
+		if attempt.Passed {
+			passed++
+		}
+		for _, answer := range attempt.Answers {
+			i, ok := index[answer.QuestionID]
+			if !ok {
+				continue
+			}
+			stats.Questions[i].Answered++
+			for _, optionID := range answer.OptionIDs {
+				if _, known := stats.Questions[i].OptionCounts[optionID]; known {
+					stats.Questions[i].OptionCounts[optionID]++
+				}
+			}
+		}
+		for _, result := range attempt.Results {
+			if i, ok := index[result.QuestionID]; ok && result.Correct {
+				stats.Questions[i].Correct++
+			}
+		}
+	}
+
+	if len(attempts) > 0 {
+		stats.AverageScore = float64(totalPercent) / float64(len(attempts))
+		stats.PassRate = float64(passed) / float64(len(attempts))
+	}
+	for i := range stats.Questions {
+		if stats.Questions[i].Answered > 0 {
+			stats.Questions[i].CorrectRate = float64(stats.Questions[i].Correct) / float64(stats.Questions[i].Answered)
+		}
+	}
+	return stats, nil
+}
+
+func (s *QuizService) findQuiz(ctx context.Context, courseID, quizID primitive.ObjectID) (*models.Quiz, error) {
+	quiz, err := s.quizRepo.FindByID(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+	if quiz.CourseID != courseID {
+		return nil, models.ErrQuizNotFound
+	}
+	return quiz, nil
+}
+
+func (s *QuizService) pastDeadline(attempt *models.QuizAttempt, now time.Time) bool {
+	return attempt.Deadline != nil && now.After(attempt.Deadline.Add(submissionGrace))
+}
+
+// expire closes an attempt that ran out of time with a score of zero.
+func (s *QuizService) expire(ctx context.Context, attempt *models.QuizAttempt, now time.Time) error {
+	attempt.Status = models.AttemptExpired
+	attempt.SubmittedAt = &now
+	return s.attemptRepo.Complete(ctx, attempt)
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultReviewPageSize = 10
+	maxReviewPageSize     = 50
+)
+
+type ReviewService struct {
+	reviewRepo     models.ReviewRepository
+	courseRepo     models.CourseRepository
+	enrollmentRepo models.EnrollmentRepository
+	users          models.UserDirectory
+	messageQueue   MessageQueue
+}
+
+func NewReviewService(
+	reviewRepo models.ReviewRepository,
+	courseRepo models.CourseRepository,
+	enrollmentRepo models.EnrollmentRepository,
+	users models.UserDirectory,
+	messageQueue MessageQueue,
+) *ReviewService {
+	return &ReviewService{
+		reviewRepo:     reviewRepo,
+		courseRepo:     courseRepo,
+		enrollmentRepo: enrollmentRepo,
+		users:          users,
+		messageQueue:   messageQueue,
+	}
+}
+
+// CreateReview posts the user's review of a course they are enrolled in.
+func (s *ReviewService) CreateReview(ctx context.Context, review *models.Review) error {
+	if err := review.Validate(); err != nil {
+		return err
+	}
+
+	enrolled, err := s.enrollmentRepo.CheckEnrollment(ctx, review.CourseID, review.UserID)
+	if err != nil {
+		return err
+	}
+	if !enrolled {
+		return models.ErrNotEnrolled
+	}
+
+	if _, err := s.reviewRepo.FindByCourseAndUser(ctx, review.CourseID, review.UserID); err == nil {
+		return models.ErrAlreadyReviewed
+	} else if err != models.ErrReviewNotFound {
+		return err
+	}
+
+	// The author name is a convenience; the review is still stored without it
+	if user, err := s.users.GetUser(ctx, review.UserID); err == nil {
+		review.Username = user.Username
+	} else {
+		log.Printf("Error looking up user %d for review: %v", review.UserID, err)
+	}
+
+	now := time.Now()
+	review.Hidden = false
+	review.CreatedAt = now
+	review.UpdatedAt = now
+	if err := s.reviewRepo.Create(ctx, review); err != nil {
+		return err
+	}
+	return s.refreshRating(ctx, review.CourseID)
+}
+
+// UpdateReview edits the rating and comment of the user's own review.
+func (s *ReviewService) UpdateReview(ctx context.Context, courseID primitive.ObjectID, userID int, rating int, comment string) (*models.Review, error) {
+	review, err := s.reviewRepo.FindByCourseAndUser(ctx, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Rating = rating
+	review.Comment = comment
+	review.UpdatedAt = time.Now()
+	if err := review.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.reviewRepo.Update(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, s.refreshRating(ctx, courseID)
+}
+
+// GetReviews returns a page of a course's reviews. Hidden reviews are only
+// listed for admins who ask for them.
+func (s *ReviewService) GetReviews(ctx context.Context, query models.ReviewQuery) (*models.ReviewPage, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 {
+		query.Limit = defaultReviewPageSize
+	}
+	if query.Limit > maxReviewPageSize {
+		query.Limit = maxReviewPageSize
+	}
+
+	reviews, total, err := s.reviewRepo.Find(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ReviewPage{
+		Reviews: reviews,
+		Total:   total,
+		Page:    query.Page,
+		Limit:   query.Limit,
+	}, nil
+}
+
+// SetHidden hides or restores a review for moderation.
+func (s *ReviewService) SetHidden(ctx context.Context, courseID, reviewID primitive.ObjectID, hidden bool) error {
+	review, err := s.reviewRepo.FindByID(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if review.CourseID != courseID {
+		return models.ErrReviewNotFound
+	}
+
+	if err := s.reviewRepo.SetHidden(ctx, reviewID, hidden); err != nil {
+		return err
+	}
+	return s.refreshRating(ctx, courseID)
+}
+
+// refreshRating recomputes the course's rating aggregates from its visible
+// reviews and pushes them to the search index.
+func (s *ReviewService) refreshRating(ctx context.Context, courseID primitive.ObjectID) error {
+	average, count, err := s.reviewRepo.RatingSummary(ctx, courseID)
+	if err != nil {
+		return err
+	}
+	if err := s.courseRepo.UpdateRating(ctx, courseID, average, count); err != nil {
+		return err
+	}
+
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return err
+	}
+	if !course.IsPublished() {
+		return nil
+	}
+	return s.messageQueue.PublishCourseUpdate(course, "upsert")
+}
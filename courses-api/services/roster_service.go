@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultRosterPageSize = 50
+	maxRosterPageSize     = 200
+	// rosterLookups bounds the concurrent users-api requests for one page
+	rosterLookups = 8
+)
+
+var rosterColumns = []string{"user_id", "username", "email", "enrolled_at", "status", "completed_at"}
+
+// RosterService lists the students enrolled in a course for its instructors
+// and admins.
+type RosterService struct {
+	enrollmentRepo models.EnrollmentRepository
+	courseRepo     models.CourseRepository
+	users          models.UserDirectory
+}
+
+func NewRosterService(enrollmentRepo models.EnrollmentRepository, courseRepo models.CourseRepository, users models.UserDirectory) *RosterService {
+	return &RosterService{
+		enrollmentRepo: enrollmentRepo,
+		courseRepo:     courseRepo,
+		users:          users,
+	}
+}
+
+// RosterCourse returns the course if the user may see its roster.
+func (s *RosterService) RosterCourse(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool) (*models.Course, error) {
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if !admin && !course.IsTaughtBy(userID) {
+		return nil, models.ErrForbidden
+	}
+	return course, nil
+}
+
+// GetRoster returns one page of the course's enrollments, oldest first.
+func (s *RosterService) GetRoster(ctx context.Context, courseID primitive.ObjectID, userID int, admin bool, page, limit int) (*models.RosterPage, error) {
+	if _, err := s.RosterCourse(ctx, courseID, userID, admin); err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultRosterPageSize
+	}
+	if limit > maxRosterPageSize {
+		limit = maxRosterPageSize
+	}
+
+	enrollments, total, err := s.enrollmentRepo.FindByCourseID(ctx, courseID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RosterPage{
+		Entries: s.rosterEntries(ctx, enrollments),
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+	}, nil
+}
+
+// ExportRoster writes the whole roster of a course returned by RosterCourse
+// to w as CSV, flushing after every page so large courses stream out.
+func (s *RosterService) ExportRoster(ctx context.Context, course *models.Course, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(rosterColumns); err != nil {
+		return err
+	}
+
+	for page := 1; ; page++ {
+		enrollments, _, err := s.enrollmentRepo.FindByCourseID(ctx, course.ID, page, maxRosterPageSize)
+		if err != nil {
+			return err
+		}
+		for _, entry := range s.rosterEntries(ctx, enrollments) {
+			completedAt := ""
+			if entry.CompletedAt != nil {
+				completedAt = entry.CompletedAt.UTC().Format(time.RFC3339)
+			}
+			err := writer.Write([]string{
+				strconv.Itoa(entry.UserID),
+				csvCell(entry.Username),
+				csvCell(entry.Email),
+				entry.EnrolledAt.UTC().Format(time.RFC3339),
+				entry.Status,
+				completedAt,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+		if len(enrollments) < maxRosterPageSize {
+			return nil
+		}
+	}
+}
+
+// rosterEntries looks up the enrolled users in parallel. A user that cannot
+// be looked up is still listed, without a name.
+func (s *RosterService) rosterEntries(ctx context.Context, enrollments []models.Enrollment) []models.RosterEntry {
+	entries := make([]models.RosterEntry, len(enrollments))
+	sem := make(chan struct{}, rosterLookups)
+	var wg sync.WaitGroup
+	for i := range enrollments {
+		enrollment := &enrollments[i]
+		entries[i] = models.RosterEntry{
+			EnrollmentID: enrollment.ID,
+			UserID:       enrollment.UserID,
+			EnrolledAt:   enrollment.Date,
+			Status:       enrollment.Status(),
+			CompletedAt:  enrollment.CompletedAt,
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(entry *models.RosterEntry) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if user, err := s.users.GetUser(ctx, entry.UserID); err == nil {
+				entry.Username = user.Username
+				entry.Email = user.Email
+			}
+		}(&entries[i])
+	}
+	wg.Wait()
+	return entries
+}
+
+// csvCell keeps spreadsheets from evaluating user-supplied text as a formula.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"courses-api/models"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type pagedEnrollmentRepo struct {
+	models.EnrollmentRepository
+	enrollments []models.Enrollment
+}
+
+func (r *pagedEnrollmentRepo) FindByCourseID(ctx context.Context, courseID primitive.ObjectID, page, limit int) ([]models.Enrollment, int64, error) {
+	start := (page - 1) * limit
+	if start > len(r.enrollments) {
+		start = len(r.enrollments)
+	}
+	end := start + limit
+	if end > len(r.enrollments) {
+		end = len(r.enrollments)
+	}
+	return r.enrollments[start:end], int64(len(r.enrollments)), nil
+}
+
+type userTable map[int]models.User
+
+func (t userTable) GetUser(ctx context.Context, id int) (*models.User, error) {
+	user, ok := t[id]
+	if !ok {
+		return nil, models.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func newRosterFixture(students int) (*RosterService, models.Course) {
+	course := models.Course{ID: primitive.NewObjectID(), Title: "Go", InstructorIDs: []int{1}}
+	lesson := primitive.NewObjectID()
+	done := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	repo := &pagedEnrollmentRepo{}
+	users := userTable{}
+	for i := 0; i < students; i++ {
+		enrollment := models.Enrollment{
+			ID:       primitive.NewObjectID(),
+			CourseID: course.ID,
+			UserID:   100 + i,
+			Date:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(i) * time.Hour),
+		}
+		switch i % 3 {
+		case 1:
+			enrollment.LastLessonID = &lesson
+		case 2:
+			enrollment.LastLessonID = &lesson
+			enrollment.CompletedAt = &done
+		}
+		repo.enrollments = append(repo.enrollments, enrollment)
+		if i != 0 {
+			users[100+i] = models.User{ID: 100 + i, Username: fmt.Sprintf("student%d", i), Email: "s@example.com"}
+		}
+	}
+	users[101] = models.User{ID: 101, Username: "=HYPERLINK(\"x\")", Email: "evil@example.com"}
+
+	return NewRosterService(repo, &memoryCourseRepo{course: course}, users), course
+}
+
+func TestGetRosterPagesAndEnrichesEntries(t *testing.T) {
+	service, course := newRosterFixture(5)
+
+	roster, err := service.GetRoster(context.Background(), course.ID, 1, false, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), roster.Total)
+	assert.Equal(t, 2, roster.Page)
+	require.Len(t, roster.Entries, 2)
+	assert.Equal(t, 102, roster.Entries[0].UserID)
+	assert.Equal(t, models.EnrollmentStatusCompleted, roster.Entries[0].Status)
+	assert.Equal(t, models.EnrollmentStatusNotStarted, roster.Entries[1].Status)
+
+	first, err := service.GetRoster(context.Background(), course.ID, 0, true, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, defaultRosterPageSize, first.Limit)
+	// Unknown accounts are listed without a name
+	assert.Empty(t, first.Entries[0].Username)
+	assert.Equal(t, models.EnrollmentStatusInProgress, first.Entries[1].Status)
+
+	_, err = service.GetRoster(context.Background(), course.ID, 2, false, 1, 10)
+	assert.Equal(t, models.ErrForbidden, err)
+}
+
+func TestExportRosterStreamsEveryPage(t *testing.T) {
+	service, course := newRosterFixture(maxRosterPageSize + 3)
+
+	var buf bytes.Buffer
+	require.NoError(t, service.ExportRoster(context.Background(), &course, &buf))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, maxRosterPageSize+4)
+	assert.Equal(t, strings.Join(rosterColumns, ","), lines[0])
+	assert.Equal(t, "100,,,2024-01-01T00:00:00Z,not_started,", lines[1])
+	assert.Equal(t, `101,"'=HYPERLINK(""x"")",evil@example.com,2024-01-01T01:00:00Z,in_progress,`, lines[2])
+	assert.Contains(t, lines[3], ",completed,2024-03-01T12:00:00Z")
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SeatHoldService struct {
+	holdRepo        models.SeatHoldRepository
+	courseRepo      models.CourseRepository
+	enrollmentRepo  models.EnrollmentRepository
+	waitlistRepo    models.WaitlistRepository
+	tx              models.Transactor
+	prerequisites   PrerequisiteChecker
+	messageQueue    MessageQueue
+	onSeatsReleased SeatReleaseHandler
+}
+
+func NewSeatHoldService(
+	holdRepo models.SeatHoldRepository,
+	courseRepo models.CourseRepository,
+	enrollmentRepo models.EnrollmentRepository,
+	waitlistRepo models.WaitlistRepository,
+	tx models.Transactor,
+	prerequisites PrerequisiteChecker,
+	messageQueue MessageQueue,
+	onSeatsReleased SeatReleaseHandler,
+) *SeatHoldService {
+	return &SeatHoldService{
+		holdRepo:        holdRepo,
+		courseRepo:      courseRepo,
+		enrollmentRepo:  enrollmentRepo,
+		waitlistRepo:    waitlistRepo,
+		tx:              tx,
+		prerequisites:   prerequisites,
+		messageQueue:    messageQueue,
+		onSeatsReleased: onSeatsReleased,
+	}
+}
+
+// CreateHold sets an open seat aside for the user for the given number of
+// minutes (DefaultHoldMinutes when zero) while they finish checkout.
+func (s *SeatHoldService) CreateHold(ctx context.Context, courseID primitive.ObjectID, userID, minutes int) (*models.SeatHold, error) {
+	if minutes == 0 {
+		minutes = models.DefaultHoldMinutes
+	}
+	if minutes < 1 || minutes > models.MaxHoldMinutes {
+		return nil, models.ErrInvalidHoldTime
+	}
+
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if !course.IsPublished() {
+		return nil, models.ErrCourseNotFound
+	}
+	now := time.Now()
+	if err := course.CheckEnrollmentWindow(now); err != nil {
+		return nil, err
+	}
+	if s.prerequisites != nil {
+		if err := s.prerequisites.CheckPrerequisites(ctx, course, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	enrolled, err := s.enrollmentRepo.CheckEnrollment(ctx, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrolled {
+		return nil, models.ErrAlreadyEnrolled
+	}
+
+	reserved, err := s.reservedFor(ctx, courseID, userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	hold := models.SeatHold{
+		CourseID:  courseID,
+		UserID:    userID,
+		Status:    models.HoldActive,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Duration(minutes) * time.Minute),
+	}
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.courseRepo.HoldSeat(ctx, courseID, reserved); err != nil {
+			return err
+		}
+		return s.holdRepo.Create(ctx, &hold)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// ConfirmHold turns the user's hold on a free course into an enrollment.
+// Holds on paid courses are confirmed by the order flow instead.
+func (s *SeatHoldService) ConfirmHold(ctx context.Context, holdID primitive.ObjectID, userID int) (*models.Enrollment, error) {
+	hold, err := s.findOwnHold(ctx, holdID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	course, err := s.courseRepo.FindByID(ctx, hold.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	if !course.IsFree() {
+		return nil, models.ErrPaymentRequired
+	}
+	return s.confirm(ctx, hold)
+}
+
+// ConfirmPaidHold turns a hold into an enrollment once its payment has been
+// confirmed.
+func (s *SeatHoldService) ConfirmPaidHold(ctx context.Context, holdID primitive.ObjectID, userID int) (*models.Enrollment, error) {
+	hold, err := s.findOwnHold(ctx, holdID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.confirm(ctx, hold)
+}
+
+func (s *SeatHoldService) confirm(ctx context.Context, hold *models.SeatHold) (*models.Enrollment, error) {
+	userID := hold.UserID
+	entry, err := s.waitlistRepo.FindActive(ctx, hold.CourseID, userID)
+	if err != nil && err != models.ErrWaitlistEntryNotFound {
+		return nil, err
+	}
+
+	enrollment := models.Enrollment{
+		CourseID: hold.CourseID,
+		UserID:   userID,
+		Date:     time.Now(),
+	}
+	var course *models.Course
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		// Fails if the sweeper expired the hold in the meantime
+		err := s.holdRepo.UpdateStatus(ctx, hold.ID, models.HoldActive, models.HoldConfirmed)
+		if err == models.ErrHoldNotFound {
+			return models.ErrHoldExpired
+		}
+		if err != nil {
+			return err
+		}
+		updated, err := s.courseRepo.ConsumeHeldSeat(ctx, hold.CourseID)
+		if err != nil {
+			return err
+		}
+		if err := s.enrollmentRepo.Create(ctx, &enrollment); err != nil {
+			return err
+		}
+		if entry != nil {
+			if err := claimWaitlistEntry(ctx, s.waitlistRepo, s.courseRepo, entry); err != nil {
+				return err
+			}
+		}
+		course = updated
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishSeatCount(s.messageQueue, course)
+	return &enrollment, nil
+}
+
+// ReleaseHold gives the user's held seat back before the hold expires.
+func (s *SeatHoldService) ReleaseHold(ctx context.Context, holdID primitive.ObjectID, userID int) error {
+	hold, err := s.findOwnHold(ctx, holdID, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.release(ctx, hold, models.HoldReleased); err != nil {
+		return err
+	}
+	s.seatsReleased(ctx, hold.CourseID)
+	return nil
+}
+
+// ExpireHolds releases every hold whose time is up.
+func (s *SeatHoldService) ExpireHolds(ctx context.Context, now time.Time) error {
+	holds, err := s.holdRepo.FindExpired(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	released := make(map[primitive.ObjectID]bool)
+	for i := range holds {
+		// Another replica may already have expired or confirmed this hold
+		if err := s.release(ctx, &holds[i], models.HoldExpired); err != nil {
+			if err == models.ErrHoldNotFound {
+				continue
+			}
+			return err
+		}
+		released[holds[i].CourseID] = true
+	}
+
+	for courseID := range released {
+		s.seatsReleased(ctx, courseID)
+	}
+	return nil
+}
+
+func (s *SeatHoldService) release(ctx context.Context, hold *models.SeatHold, status string) error {
+	return s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.holdRepo.UpdateStatus(ctx, hold.ID, models.HoldActive, status); err != nil {
+			return err
+		}
+		return s.courseRepo.ReleaseHeldSeat(ctx, hold.CourseID)
+	})
+}
+
+func (s *SeatHoldService) findOwnHold(ctx context.Context, holdID primitive.ObjectID, userID int) (*models.SeatHold, error) {
+	hold, err := s.holdRepo.FindByID(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	if hold.UserID != userID {
+		return nil, models.ErrHoldNotFound
+	}
+	switch {
+	case hold.IsActive(time.Now()):
+		return hold, nil
+	case hold.Status == models.HoldActive, hold.Status == models.HoldExpired:
+		return nil, models.ErrHoldExpired
+	default:
+		return nil, models.ErrHoldNotFound
+	}
+}
+
+// reservedFor counts the seats held for waitlist offers other than the
+// user's own, which the user may not take.
+func (s *SeatHoldService) reservedFor(ctx context.Context, courseID primitive.ObjectID, userID int, now time.Time) (int, error) {
+	offers, err := s.waitlistRepo.CountOpenOffers(ctx, courseID, now)
+	if err != nil {
+		return 0, err
+	}
+	entry, err := s.waitlistRepo.FindActive(ctx, courseID, userID)
+	if err != nil && err != models.ErrWaitlistEntryNotFound {
+		return 0, err
+	}
+	if entry != nil && entry.HasOpenOffer(now) {
+		offers--
+	}
+	return offers, nil
+}
+
+func (s *SeatHoldService) seatsReleased(ctx context.Context, courseID primitive.ObjectID) {
+	if s.onSeatsReleased == nil {
+		return
+	}
+	if err := s.onSeatsReleased.OnSeatsReleased(ctx, courseID); err != nil {
+		log.Printf("Error promoting waitlist for course %s: %v", courseID.Hex(), err)
+	}
+}
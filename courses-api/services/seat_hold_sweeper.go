@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// SeatHoldSweeper periodically releases expired seat holds.
+type SeatHoldSweeper struct {
+	holdService *SeatHoldService
+	interval    time.Duration
+}
+
+func NewSeatHoldSweeper(holdService *SeatHoldService, interval time.Duration) *SeatHoldSweeper {
+	return &SeatHoldSweeper{
+		holdService: holdService,
+		interval:    interval,
+	}
+}
+
+// Run blocks until ctx is cancelled, expiring holds on every tick.
+func (s *SeatHoldSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.holdService.ExpireHolds(ctx, now); err != nil {
+				log.Printf("Error expiring seat holds: %v", err)
+			}
+		}
+	}
+}
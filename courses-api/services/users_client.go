@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// UsersClient reads accounts from users-api over HTTP
+type UsersClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewUsersClient() *UsersClient {
+	baseURL := os.Getenv("USERS_API_URL")
+	if baseURL == "" {
+		baseURL = "http://users-api:8001"
+	}
+	return &UsersClient{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (c *UsersClient) GetUser(ctx context.Context, id int) (*models.User, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/users/%d", c.baseURL, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling users-api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, models.ErrUserNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("users-api returned %s", resp.Status)
+	}
+
+	var user models.User
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("error decoding users-api response: %w", err)
+	}
+	return &user, nil
+}
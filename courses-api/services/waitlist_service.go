@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SeatReleaseHandler is notified when seats become available in a course, so
+// that they can be offered to waitlisted users first.
+type SeatReleaseHandler interface {
+	OnSeatsReleased(ctx context.Context, courseID primitive.ObjectID) error
+}
+
+type WaitlistService struct {
+	waitlistRepo   models.WaitlistRepository
+	courseRepo     models.CourseRepository
+	enrollmentRepo models.EnrollmentRepository
+	messageQueue   MessageQueue
+}
+
+func NewWaitlistService(
+	waitlistRepo models.WaitlistRepository,
+	courseRepo models.CourseRepository,
+	enrollmentRepo models.EnrollmentRepository,
+	messageQueue MessageQueue,
+) *WaitlistService {
+	return &WaitlistService{
+		waitlistRepo:   waitlistRepo,
+		courseRepo:     courseRepo,
+		enrollmentRepo: enrollmentRepo,
+		messageQueue:   messageQueue,
+	}
+}
+
+// Join puts the user at the end of the course waitlist. It is only allowed
+// while every seat is taken or held by an outstanding offer.
+func (s *WaitlistService) Join(ctx context.Context, courseID primitive.ObjectID, userID int) (*models.WaitlistPosition, error) {
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if !course.IsPublished() {
+		return nil, models.ErrCourseNotFound
+	}
+
+	enrolled, err := s.enrollmentRepo.CheckEnrollment(ctx, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrolled {
+		return nil, models.ErrAlreadyEnrolled
+	}
+
+	if _, err := s.waitlistRepo.FindActive(ctx, courseID, userID); err == nil {
+		return nil, models.ErrAlreadyWaitlisted
+	} else if err != models.ErrWaitlistEntryNotFound {
+		return nil, err
+	}
+
+	offers, err := s.waitlistRepo.CountOpenOffers(ctx, courseID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if course.OpenSeats() > offers {
+		return nil, models.ErrSeatsAvailable
+	}
+
+	entry := models.WaitlistEntry{
+		CourseID: courseID,
+		UserID:   userID,
+		Status:   models.WaitlistWaiting,
+		JoinedAt: time.Now(),
+	}
+	if err := s.waitlistRepo.Create(ctx, &entry); err != nil {
+		return nil, err
+	}
+	return s.position(ctx, &entry)
+}
+
+// GetPosition reports the user's place in line or their pending offer.
+func (s *WaitlistService) GetPosition(ctx context.Context, courseID primitive.ObjectID, userID int) (*models.WaitlistPosition, error) {
+	entry, err := s.waitlistRepo.FindActive(ctx, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.position(ctx, entry)
+}
+
+// Leave removes the user from the waitlist. Declining an open offer passes
+// the seat on to the next person in line.
+func (s *WaitlistService) Leave(ctx context.Context, courseID primitive.ObjectID, userID int) error {
+	entry, err := s.waitlistRepo.FindActive(ctx, courseID, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.waitlistRepo.UpdateStatus(ctx, entry.ID, entry.Status, models.WaitlistCancelled); err != nil {
+		return err
+	}
+	if entry.Status == models.WaitlistOffered {
+		if err := s.courseRepo.ReleaseOfferedSeat(ctx, courseID); err != nil {
+			return err
+		}
+		return s.OnSeatsReleased(ctx, courseID)
+	}
+	return nil
+}
+
+// GetWaitlist lists the active entries of a course in queue order (admins).
+func (s *WaitlistService) GetWaitlist(ctx context.Context, courseID primitive.ObjectID) ([]models.WaitlistEntry, error) {
+	if _, err := s.courseRepo.FindByID(ctx, courseID); err != nil {
+		return nil, err
+	}
+	return s.waitlistRepo.FindByCourseID(ctx, courseID)
+}
+
+// OnSeatsReleased offers every open seat not already covered by an offer to
+// the next waiting users, notifying each of them. Each offer first claims
+// its seat on the course, so callers running at the same time, here or on
+// other replicas, never offer the same seat twice.
+func (s *WaitlistService) OnSeatsReleased(ctx context.Context, courseID primitive.ObjectID) error {
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for {
+		if err := s.courseRepo.OfferSeat(ctx, courseID); err != nil {
+			if err == models.ErrNoAvailableSeats {
+				return nil
+			}
+			return err
+		}
+
+		expiresAt := now.Add(models.WaitlistClaimWindow)
+		entry, err := s.waitlistRepo.OfferNext(ctx, courseID, now, expiresAt)
+		if err != nil {
+			// Nobody took the seat; give it back
+			if releaseErr := s.courseRepo.ReleaseOfferedSeat(ctx, courseID); releaseErr != nil {
+				log.Printf("Error releasing offered seat for course %s: %v", courseID.Hex(), releaseErr)
+			}
+			if err == models.ErrWaitlistEntryNotFound {
+				return nil
+			}
+			return err
+		}
+
+		s.notify(&models.Notification{
+			UserID:  entry.UserID,
+			Type:    models.NotificationWaitlistOffer,
+			Message: fmt.Sprintf("A seat opened up in %s. Enroll before %s to claim it.", course.Title, expiresAt.Format(time.RFC1123)),
+			Data: map[string]interface{}{
+				"course_id":  courseID.Hex(),
+				"expires_at": expiresAt,
+			},
+		})
+	}
+}
+
+// ExpireOffers releases offers whose claim window has passed and offers the
+// seats to the next users in line.
+func (s *WaitlistService) ExpireOffers(ctx context.Context, now time.Time) error {
+	entries, err := s.waitlistRepo.FindExpiredOffers(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	released := make(map[primitive.ObjectID]bool)
+	for _, entry := range entries {
+		// Another replica may already have expired this offer
+		if err := s.waitlistRepo.UpdateStatus(ctx, entry.ID, models.WaitlistOffered, models.WaitlistExpired); err != nil {
+			if err == models.ErrWaitlistEntryNotFound {
+				continue
+			}
+			return err
+		}
+		if err := s.courseRepo.ReleaseOfferedSeat(ctx, entry.CourseID); err != nil {
+			return err
+		}
+		released[entry.CourseID] = true
+
+		s.notify(&models.Notification{
+			UserID:  entry.UserID,
+			Type:    models.NotificationWaitlistExpired,
+			Message: "Your waitlist offer has expired.",
+			Data: map[string]interface{}{
+				"course_id": entry.CourseID.Hex(),
+			},
+		})
+	}
+
+	for courseID := range released {
+		if err := s.OnSeatsReleased(ctx, courseID); err != nil {
+			log.Printf("Error promoting waitlist for course %s: %v", courseID.Hex(), err)
+		}
+	}
+	return nil
+}
+
+func (s *WaitlistService) position(ctx context.Context, entry *models.WaitlistEntry) (*models.WaitlistPosition, error) {
+	position := models.WaitlistPosition{
+		CourseID:       entry.CourseID,
+		Status:         entry.Status,
+		OfferExpiresAt: entry.OfferExpiresAt,
+	}
+	if entry.Status == models.WaitlistWaiting {
+		ahead, err := s.waitlistRepo.CountAhead(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+		position.Position = ahead + 1
+	}
+	return &position, nil
+}
+
+// notify is best effort: a lost notification must not undo the promotion,
+// the user can still see the offer through GetPosition.
+func (s *WaitlistService) notify(notification *models.Notification) {
+	notification.CreatedAt = time.Now()
+	if err := s.messageQueue.PublishNotification(notification); err != nil {
+		log.Printf("Error publishing notification to user %d: %v", notification.UserID, err)
+	}
+}
+
+// claimWaitlistEntry marks the user's waitlist entry as claimed by their
+// enrollment and ends the seat claim of an offer. The sweeper may have
+// expired the entry meanwhile, in which case it already ended the claim.
+func claimWaitlistEntry(ctx context.Context, waitlistRepo models.WaitlistRepository, courseRepo models.CourseRepository, entry *models.WaitlistEntry) error {
+	err := waitlistRepo.UpdateStatus(ctx, entry.ID, entry.Status, models.WaitlistClaimed)
+	if err == models.ErrWaitlistEntryNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if entry.Status == models.WaitlistOffered {
+		return courseRepo.ReleaseOfferedSeat(ctx, entry.CourseID)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"courses-api/models"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryWaitlistRepo struct {
+	models.WaitlistRepository
+	mu      sync.Mutex
+	entries []models.WaitlistEntry
+}
+
+func (r *memoryWaitlistRepo) OfferNext(ctx context.Context, courseID primitive.ObjectID, now, expiresAt time.Time) (*models.WaitlistEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.entries {
+		if r.entries[i].Status == models.WaitlistWaiting {
+			r.entries[i].Status = models.WaitlistOffered
+			r.entries[i].OfferExpiresAt = &expiresAt
+			entry := r.entries[i]
+			return &entry, nil
+		}
+	}
+	return nil, models.ErrWaitlistEntryNotFound
+}
+
+func (r *memoryWaitlistRepo) offered() int {
+	offered := 0
+	for _, entry := range r.entries {
+		if entry.Status == models.WaitlistOffered {
+			offered++
+		}
+	}
+	return offered
+}
+
+func (r *memoryCourseRepo) OfferSeat(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.course.AvailableSeats <= r.course.HeldSeats+r.course.OfferedSeats {
+		return models.ErrNoAvailableSeats
+	}
+	r.course.OfferedSeats++
+	return nil
+}
+
+func (r *memoryCourseRepo) ReleaseOfferedSeat(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.course.OfferedSeats > 0 {
+		r.course.OfferedSeats--
+	}
+	return nil
+}
+
+func TestOnSeatsReleasedConcurrentNeverOverOffers(t *testing.T) {
+	const seats = 3
+	courses := &memoryCourseRepo{course: models.Course{ID: primitive.NewObjectID(), AvailableSeats: seats}}
+	waitlist := &memoryWaitlistRepo{}
+	for _, userID := range distinctUsers(hammerStudents) {
+		waitlist.entries = append(waitlist.entries, models.WaitlistEntry{
+			ID:       primitive.NewObjectID(),
+			CourseID: courses.course.ID,
+			UserID:   userID,
+			Status:   models.WaitlistWaiting,
+		})
+	}
+	service := NewWaitlistService(waitlist, courses, nil, discardQueue{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, service.OnSeatsReleased(context.Background(), courses.course.ID))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, seats, waitlist.offered())
+	assert.Equal(t, seats, courses.course.OfferedSeats)
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// WaitlistSweeper periodically expires unclaimed waitlist offers.
+type WaitlistSweeper struct {
+	waitlistService *WaitlistService
+	interval        time.Duration
+}
+
+func NewWaitlistSweeper(waitlistService *WaitlistService, interval time.Duration) *WaitlistSweeper {
+	return &WaitlistSweeper{
+		waitlistService: waitlistService,
+		interval:        interval,
+	}
+}
+
+// Run blocks until ctx is cancelled, expiring offers on every tick.
+func (s *WaitlistSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.waitlistService.ExpireOffers(ctx, now); err != nil {
+				log.Printf("Error expiring waitlist offers: %v", err)
+			}
+		}
+	}
+}
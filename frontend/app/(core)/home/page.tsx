@@ -6,6 +6,7 @@ import { useEffect, useState } from "react"
 import { CourseType } from "@/lib/types";
 import CourseCard from "@/components/CourseCard/CourseCard.components";
 import { useDebounce } from "@/hooks/useDebounce";
+import { useCategories } from "@/hooks/useCategories";
 import { Checkbox } from "@/components/ui/checkbox"
 import { Label } from "@/components/ui/label"
 
@@ -16,6 +17,7 @@ export default function Component() {
   const [isLoading, setIsLoading] = useState(false)
   const [availableOnly, setAvailableOnly] = useState(false)
   const debouncedSearch = useDebounce(search, 500)
+  const categories = useCategories()
 
   const fetchCourses = async (searchTerm: string, categoryFilter: string) => {
     setIsLoading(true)
@@ -42,7 +44,7 @@ export default function Component() {
         // Merge availability data with courses
         const coursesWithAvailability = data.courses.map((course: CourseType) => ({
           ...course,
-          available_seats: availData[course.id as keyof typeof availData] || 0
+          available_seats: availData[course.id as keyof typeof availData]?.open_seats || 0
         }))
 
         setCourses(coursesWithAvailability)
@@ -87,11 +89,9 @@ export default function Component() {
             <DropdownMenuContent>
               <DropdownMenuRadioGroup value={category} onValueChange={setCategory}>
                 <DropdownMenuRadioItem value="">All</DropdownMenuRadioItem>
-                <DropdownMenuRadioItem value="web-development">Web Development</DropdownMenuRadioItem>
-                <DropdownMenuRadioItem value="mobile-development">Mobile Development</DropdownMenuRadioItem>
-                <DropdownMenuRadioItem value="data-science">Data Science</DropdownMenuRadioItem>
-                <DropdownMenuRadioItem value="design">Design</DropdownMenuRadioItem>
-                <DropdownMenuRadioItem value="business">Business</DropdownMenuRadioItem>
+                {categories.map((c) => (
+                  <DropdownMenuRadioItem key={c.slug} value={c.slug}>{c.display_name}</DropdownMenuRadioItem>
+                ))}
               </DropdownMenuRadioGroup>
             </DropdownMenuContent>
           </DropdownMenu>
@@ -8,6 +8,7 @@ import { Textarea } from "@/components/ui/textarea";
 import { Select, SelectTrigger, SelectValue, SelectContent, SelectItem } from "@/components/ui/select";
 import { Button } from "@/components/ui/button";
 import { useUser } from "@/hooks/useUser";
+import { useCategories } from "@/hooks/useCategories";
 
 export default function Component() {
   const [courseImage, setCourseImage] = useState<string>("");
@@ -23,6 +24,7 @@ export default function Component() {
 
   const router = useRouter();
   const { isAdmin, isLoading } = useUser();
+  const categories = useCategories();
 
   // Redirect if user is not admin
   useEffect(() => {
@@ -141,11 +143,9 @@ export default function Component() {
                   <SelectValue placeholder="Select category" />
                 </SelectTrigger>
                 <SelectContent>
-                  <SelectItem value="web-development">Web Development</SelectItem>
-                  <SelectItem value="mobile-development">Mobile Development</SelectItem>
-                  <SelectItem value="data-science">Data Science</SelectItem>
-                  <SelectItem value="design">Design</SelectItem>
-                  <SelectItem value="business">Business</SelectItem>
+                  {categories.map((c) => (
+                    <SelectItem key={c.slug} value={c.slug}>{c.display_name}</SelectItem>
+                  ))}
                 </SelectContent>
               </Select>
             </div>
@@ -0,0 +1,22 @@
+import { NextResponse } from "next/server";
+
+export async function GET() {
+  const baseUrl =
+    process.env.NEXT_PUBLIC_COURSES_API_URL ?? "http://courses-api:8002";
+
+  try {
+    const res = await fetch(`${baseUrl}/categories`, { cache: "no-cache" });
+    if (!res.ok) {
+      throw new Error("Failed to fetch categories");
+    }
+
+    const data = await res.json();
+    return NextResponse.json({ categories: data.data || [] }, { status: 200 });
+  } catch (error: any) {
+    console.error("Error fetching categories:", error);
+    return NextResponse.json(
+      { message: "Error fetching categories", categories: [] },
+      { status: 500 }
+    );
+  }
+}
@@ -0,0 +1,32 @@
+import { formatCookies } from "@/lib/api.utils";
+import { NextRequest, NextResponse } from "next/server";
+
+// Forwards the multipart upload as-is; courses-api validates the image and
+// checks that the caller may edit the course.
+export async function POST(
+  request: NextRequest,
+  { params }: { params: { id: string } }
+) {
+  const baseUrl = process.env.NEXT_PUBLIC_COURSES_API_URL ?? "";
+
+  try {
+    const formData = await request.formData();
+
+    const response = await fetch(`${baseUrl}/courses/${params.id}/image`, {
+      method: "POST",
+      headers: {
+        cookie: formatCookies(),
+      },
+      body: formData,
+    });
+
+    const data = await response.json();
+    return NextResponse.json(data, { status: response.status });
+  } catch (error: any) {
+    console.error("Error uploading course image:", error);
+    return NextResponse.json(
+      { message: error.message || "Error uploading course image" },
+      { status: 500 }
+    );
+  }
+}
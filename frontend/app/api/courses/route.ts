@@ -8,13 +8,16 @@ export async function GET(request: NextRequest) {
   const name = searchParams.get("name") || "";
   const category = searchParams.get("category") || "";
   const available = searchParams.get("available") === "true";
+  const tags = searchParams.getAll("tag");
 
   try {
     const searchApiUrl =
       process.env.NEXT_PUBLIC_SEARCH_API_URL ?? "http://search-api:8003";
     const searchUrl = `${searchApiUrl}/search?q=${encodeURIComponent(
       name
-    )}&category=${encodeURIComponent(category)}&available=${available}`;
+    )}&category=${encodeURIComponent(category)}&available=${available}${tags
+      .map((tag) => `&tag=${encodeURIComponent(tag)}`)
+      .join("")}`;
 
     const response = await fetch(searchUrl, {
       method: "GET",
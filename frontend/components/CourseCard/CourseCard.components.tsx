@@ -8,7 +8,7 @@ import { CourseType } from "@/lib/types";
 import { useState } from "react";
 
 export default function CourseCard({ course, enrolled }: { course: CourseType, enrolled?: boolean }) {
-  const [src, setSrc] = useState<string>(course.image_url ?? "/placeholder.svg")
+  const [src, setSrc] = useState<string>(course.thumbnail_url || course.image_url || "/placeholder.svg")
 
   const courseId = course.id;
 
@@ -0,0 +1,15 @@
+import { useEffect, useState } from "react";
+import { CategoryType } from "@/lib/types";
+
+export function useCategories() {
+  const [categories, setCategories] = useState<CategoryType[]>([]);
+
+  useEffect(() => {
+    fetch("/api/categories")
+      .then((res) => res.json())
+      .then((data) => setCategories(data.categories || []))
+      .catch((error) => console.error("Error fetching categories:", error));
+  }, []);
+
+  return categories;
+}
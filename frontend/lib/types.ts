@@ -4,9 +4,14 @@ export type CourseType = {
   description: string;
   instructor: string;
   category: string;
+  tags?: string[];
   duration: number;
   available_seats: number;
   image_url: string;
+  thumbnail_url?: string;
+  cohort?: string;
+  cloned_from?: string;
+  locale?: string;
   is_subscribed: boolean;
 };
 
@@ -31,3 +36,11 @@ export type CommentType = {
   CreationTime: string;
   LastUpdated: string;
 };
+
+export type CategoryType = {
+  id: string;
+  slug: string;
+  display_name: string;
+  icon?: string;
+  parent_id?: string;
+};
@@ -6,6 +6,13 @@ const nextConfig = {
         protocol: "https",
         hostname: "**",
       },
+      {
+        // Uploads served by courses-api through nginx
+        protocol: "http",
+        hostname: "localhost",
+        port: "8080",
+        pathname: "/uploads/**",
+      },
     ],
   },
 };
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"search-api/domain"
 	"search-api/services"
 )
 
@@ -19,12 +22,17 @@ func NewSearchController(service *services.CourseService) *SearchController {
 func (c *SearchController) SearchHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Vary", "Accept-Language")
 
 	query := r.URL.Query().Get("q")
 	category := r.URL.Query().Get("category")
+	instructorID := r.URL.Query().Get("instructor_id")
 	available := r.URL.Query().Get("available")
+	sort := r.URL.Query().Get("sort")
+	tags := r.URL.Query()["tag"]
+	lang := negotiateLocale(r.URL.Query().Get("lang"), r.Header.Get("Accept-Language"))
 
-	result, err := c.service.SearchCourses(query, category, available)
+	result, err := c.service.SearchCourses(query, category, instructorID, available, sort, lang, tags)
 	if err != nil {
 		log.Printf("Error searching courses: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -33,3 +41,37 @@ func (c *SearchController) SearchHandler(w http.ResponseWriter, r *http.Request)
 
 	json.NewEncoder(w).Encode(result)
 }
+
+// negotiateLocale picks ?lang= if it is indexed, otherwise the preferred
+// indexed language in Accept-Language, matching regional variants such as
+// es-AR to their language. It returns "" when nothing matches.
+func negotiateLocale(lang, acceptLanguage string) string {
+	if lang = strings.ToLower(strings.TrimSpace(lang)); domain.IsLocale(lang) {
+		return lang
+	}
+
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if primary, _, ok := strings.Cut(tag, "-"); ok {
+			tag = primary
+		}
+		if !domain.IsLocale(tag) {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	return best
+}
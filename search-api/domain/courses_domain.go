@@ -1,12 +1,55 @@
 package domain
 
 type Course struct {
-	ID             string `json:"id"`
-	Title          string `json:"title"`
-	Description    string `json:"description"`
-	Instructor     string `json:"instructor"`
-	Category       string `json:"category"`
-	ImageURL       string `json:"image_url"`
-	Duration       int    `json:"duration"`
-	AvailableSeats int    `json:"available_seats"`
+	ID             string                 `json:"id"`
+	Title          string                 `json:"title"`
+	Description    string                 `json:"description"`
+	DefaultLocale  string                 `json:"default_locale"`
+	Translations   map[string]Translation `json:"translations"`
+	Instructor     string                 `json:"instructor"`
+	InstructorIDs  []int                  `json:"instructor_ids"`
+	Category       string                 `json:"category"`
+	Tags           []string               `json:"tags"`
+	ImageURL       string                 `json:"image_url"`
+	Duration       int                    `json:"duration"`
+	AvailableSeats int                    `json:"available_seats"`
+	AverageRating  float64                `json:"average_rating"`
+	ReviewCount    int                    `json:"review_count"`
+}
+
+// Translation is a course's title and description in another locale
+type Translation struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// DefaultLocale is the language of courses that do not set one
+const DefaultLocale = "en"
+
+// Locales are the languages indexed with their own analyzers, as
+// title_<locale> and description_<locale>
+var Locales = []string{"en", "es"}
+
+func IsLocale(locale string) bool {
+	for _, l := range Locales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Localized returns the title and description in the given locale, falling
+// back to the course's own text where there is no translation.
+func (c Course) Localized(locale string) (string, string) {
+	title, description := c.Title, c.Description
+	if translation, ok := c.Translations[locale]; ok {
+		if translation.Title != "" {
+			title = translation.Title
+		}
+		if translation.Description != "" {
+			description = translation.Description
+		}
+	}
+	return title, description
 }
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 
 	"search-api/domain"
@@ -34,10 +35,27 @@ func (r *SolrRepository) UpdateCourse(course domain.Course) error {
 		"title":          course.Title,
 		"description":    course.Description,
 		"instructor":     course.Instructor,
+		"instructor_ids": course.InstructorIDs,
 		"category":       course.Category,
+		"tags":           course.Tags,
 		"image_url":      course.ImageURL,
 		"duration":       course.Duration,
 		"available_seats": course.AvailableSeats,
+		"average_rating":  course.AverageRating,
+		"review_count":    course.ReviewCount,
+	}
+
+	// Each locale gets fields analyzed for its language; untranslated
+	// courses are indexed with their own text in every locale
+	locale := course.DefaultLocale
+	if locale == "" {
+		locale = domain.DefaultLocale
+	}
+	doc["default_locale"] = locale
+	for _, l := range domain.Locales {
+		title, description := course.Localized(l)
+		doc["title_"+l] = title
+		doc["description_"+l] = description
 	}
 
 	jsonData, err := json.Marshal([]interface{}{doc})
@@ -65,7 +83,7 @@ func (r *SolrRepository) UpdateCourse(course domain.Course) error {
 	return nil
 }
 
-func (r *SolrRepository) SearchCourses(query, category, available string) (map[string]interface{}, error) {
+func (r *SolrRepository) SearchCourses(query, category, instructorID, available, sort, lang string, tags []string) (map[string]interface{}, error) {
 	searchURL := fmt.Sprintf("%s/solr/courses/select", r.SolrURL)
 	
 	// Build query parameters
@@ -78,6 +96,12 @@ func (r *SolrRepository) SearchCourses(query, category, available string) (map[s
 	// Handle text search if present
 	if query != "" && query != "*:*" {
 		baseQuery = fmt.Sprintf("title:*%s* OR description:*%s*", query, query)
+		// Also match stemmed words in every language, so "cursos" finds "curso"
+		if terms := escapeQueryTerms(query); terms != "" {
+			for _, l := range domain.Locales {
+				baseQuery += fmt.Sprintf(" OR title_%s:(%s) OR description_%s:(%s)", l, terms, l, terms)
+			}
+		}
 	}
 	
 	// Add base query
@@ -89,13 +113,37 @@ func (r *SolrRepository) SearchCourses(query, category, available string) (map[s
 		params.Add("fq", fmt.Sprintf("category:\"%s\"", category))
 	}
 	
+	// Handle tag filters; courses must have every requested tag
+	for _, tag := range tags {
+		params.Add("fq", fmt.Sprintf("tags:\"%s\"", strings.ReplaceAll(tag, `"`, `\"`)))
+	}
+	
+	// Handle instructor filter
+	if id, err := strconv.Atoi(instructorID); err == nil {
+		params.Add("fq", fmt.Sprintf("instructor_ids:%d", id))
+	}
+	
 	// Handle available seats filter
 	if available == "true" {
 		params.Add("fq", "available_seats:[1 TO *]")
 	}
 	
+	// Handle sorting
+	if sort == "rating" {
+		params.Add("sort", "average_rating desc,review_count desc")
+	}
+	
+	// Facet on tags so clients can offer them as filters
+	params.Add("facet", "true")
+	params.Add("facet.field", "tags")
+	params.Add("facet.mincount", "1")
+	
 	// Specify fields to return
-	params.Add("fl", "id,title,description,instructor,category,image_url,duration,available_seats")
+	fields := "id,title,description,instructor,instructor_ids,category,tags,image_url,duration,available_seats,average_rating,review_count"
+	if lang != "" {
+		fields += fmt.Sprintf(",title_%s,description_%s", lang, lang)
+	}
+	params.Add("fl", fields)
 	
 	// Add parameters to URL
 	finalURL := fmt.Sprintf("%s?%s", searchURL, params.Encode())
@@ -125,9 +173,45 @@ func (r *SolrRepository) SearchCourses(query, category, available string) (map[s
 		return nil, fmt.Errorf("error decoding Solr response: %w", err)
 	}
 	
+	if lang != "" {
+		localizeDocs(result, lang)
+	}
 	return result, nil
 }
 
+// localizeDocs replaces each result's title and description with the
+// requested locale's, keeping the response shape the same for every language.
+func localizeDocs(result map[string]interface{}, lang string) {
+	response, _ := result["response"].(map[string]interface{})
+	docs, _ := response["docs"].([]interface{})
+	for _, d := range docs {
+		doc, ok := d.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, field := range []string{"title", "description"} {
+			if value, ok := doc[field+"_"+lang]; ok {
+				doc[field] = value
+				delete(doc, field+"_"+lang)
+			}
+		}
+		doc["locale"] = lang
+	}
+}
+
+// escapeQueryTerms escapes Solr query syntax in user input so it can be
+// used as a group of plain terms.
+func escapeQueryTerms(query string) string {
+	var b strings.Builder
+	for _, r := range query {
+		if strings.ContainsRune(`+-&|!(){}[]^"~*?:\/`, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return strings.TrimSpace(b.String())
+}
+
 func (r *SolrRepository) DeleteCourse(courseID string) error {
 	deleteURL := fmt.Sprintf("%s/solr/courses/update?commit=true", r.SolrURL)
 	deleteQuery := fmt.Sprintf(`{"delete": { "id": "%s" }}`, courseID)
@@ -17,8 +17,8 @@ func (s *CourseService) UpdateCourse(course domain.Course) error {
 	return s.repo.UpdateCourse(course)
 }
 
-func (s *CourseService) SearchCourses(query, category, available string) (map[string]interface{}, error) {
-	return s.repo.SearchCourses(query, category, available)
+func (s *CourseService) SearchCourses(query, category, instructorID, available, sort, lang string, tags []string) (map[string]interface{}, error) {
+	return s.repo.SearchCourses(query, category, instructorID, available, sort, lang, tags)
 }
 
 func (s *CourseService) DeleteCourse(courseID string) error {
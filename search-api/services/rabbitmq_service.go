@@ -52,6 +52,11 @@ func (s *RabbitMQService) ProcessMessage(msg []byte) {
 	action := event["action"].(string)
 	courseData := event["course"].(map[string]interface{})
 
+	// Only published courses belong in the index
+	if status, ok := courseData["status"].(string); ok && action == "upsert" && status != "published" {
+		action = "delete"
+	}
+
 	switch action {
 	case "upsert":
 		course := domain.Course{
@@ -64,6 +69,43 @@ func (s *RabbitMQService) ProcessMessage(msg []byte) {
 			Duration:       int(courseData["duration"].(float64)),
 			AvailableSeats: int(courseData["available_seats"].(float64)),
 		}
+		// Rating aggregates are absent from events published before reviews existed
+		if rating, ok := courseData["average_rating"].(float64); ok {
+			course.AverageRating = rating
+		}
+		if count, ok := courseData["review_count"].(float64); ok {
+			course.ReviewCount = int(count)
+		}
+		if tags, ok := courseData["tags"].([]interface{}); ok {
+			for _, tag := range tags {
+				if tag, ok := tag.(string); ok {
+					course.Tags = append(course.Tags, tag)
+				}
+			}
+		}
+		if locale, ok := courseData["default_locale"].(string); ok {
+			course.DefaultLocale = locale
+		}
+		if translations, ok := courseData["translations"].(map[string]interface{}); ok {
+			course.Translations = map[string]domain.Translation{}
+			for locale, value := range translations {
+				fields, ok := value.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				var translation domain.Translation
+				translation.Title, _ = fields["title"].(string)
+				translation.Description, _ = fields["description"].(string)
+				course.Translations[locale] = translation
+			}
+		}
+		if ids, ok := courseData["instructor_ids"].([]interface{}); ok {
+			for _, id := range ids {
+				if id, ok := id.(float64); ok {
+					course.InstructorIDs = append(course.InstructorIDs, int(id))
+				}
+			}
+		}
 		s.courseService.UpdateCourse(course)
 	case "delete":
 		courseID := courseData["id"].(string)